@@ -19,23 +19,23 @@ package v1alpha1
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	invalidUpdateErrorMsg string = " is an immutable field and can not be modified."
-	duplicateKeysErr      string = "Duplicate Project Keys are not allowed"
+	invalidUpdateErrorMsg     string = " is an immutable field and can not be modified."
+	duplicateKeysErr          string = "Duplicate Project Keys are not allowed"
+	duplicateOrganizationsErr string = "Duplicate Organizations are not allowed"
 )
 
 // CustomerSpec defines the desired state of Customer
 type CustomerSpec struct {
-	// Name of the customer
+	// Name of the customer. Legacy customers choose their own name when they sign up, so it is only used for normal customers
 	// +required
 	Name string `json:"name"`
 
-	// Email of the customer
+	// Email of the customer. On Jira Cloud, changing the email moves the customer to a new account with the new email
 	// +kubebuilder:validation:Pattern=\S+@\S+\.\S+
 	// +required
 	Email string `json:"email"`
@@ -44,6 +44,7 @@ type CustomerSpec struct {
 	// In case of a legacy Customer, a signup link is sent to the customer email which he can than use to signup
 	// In case of a normal Customer, no signup link is sent to the customer. The customer than has to signup manually using the portal
 	// If not given, default behaviour is false i.e. normal customer
+	// Legacy customers are not supported on Jira Data Center
 	// +optional
 	LegacyCustomer bool `json:"legacyCustomer,omitempty"`
 
@@ -51,20 +52,76 @@ type CustomerSpec struct {
 	// +kubebuilder:validation:MinItems=1
 	// +required
 	Projects []string `json:"projects"`
+
+	// List of Organization custom resource names, in the same namespace, in which customer will be added
+	// +optional
+	Organizations []string `json:"organizations,omitempty"`
+
+	// What happens to the customer on JSD when the custom resource is deleted. Orphan removes the customer from
+	// its projects and organizations but keeps the account. If not given, the default deletion policy of the operator is used
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Connection of the Jira site the customer is managed on. If not given, the site configured for the operator is used.
+	// The organizations of the customer have to use the same connection
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
 }
 
 // CustomerStatus defines the observed state of Customer
 type CustomerStatus struct {
-	// Jira Service Desk Customer Account Id
+	// Jira Service Desk Customer Account Id, or the username of the customer on Jira Data Center
 	CustomerId string `json:"customerId"`
 
+	// Generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// List of ProjectKeys in which customer has bee added
 	AssociatedProjects []string `json:"associatedProjects,omitempty"`
 
+	// List of Organization custom resource names in which customer has been added
+	AssociatedOrganizations []string `json:"associatedOrganizations,omitempty"`
+
+	// Progress of moving the customer to a new account after its email was changed, on Jira sites that can not update accounts
+	// +optional
+	Migration *CustomerMigration `json:"migration,omitempty"`
+
 	// Status conditions
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// CustomerMigrationPhase is the last completed step of moving a customer to a new account
+type CustomerMigrationPhase string
+
+const (
+	// The customer with the new email was created on JSD
+	CustomerMigrationCreated CustomerMigrationPhase = "Created"
+	// The customer with the new email was added to the projects of the old customer, which was removed from them
+	CustomerMigrationProjectsMoved CustomerMigrationPhase = "ProjectsMoved"
+	// The customer with the new email was added to the organizations of the old customer, which was removed from them
+	CustomerMigrationOrganizationsMoved CustomerMigrationPhase = "OrganizationsMoved"
+	// The old customer was removed according to the deletion policy and the custom resource manages the new customer
+	CustomerMigrationCompleted CustomerMigrationPhase = "Completed"
+)
+
+// CustomerMigration reports the progress of moving a customer to a new account with a new email
+type CustomerMigration struct {
+	// Account Id of the customer with the old email
+	FromCustomerId string `json:"fromCustomerId"`
+
+	// Account Id of the customer with the new email
+	// +optional
+	ToCustomerId string `json:"toCustomerId,omitempty"`
+
+	// Email the customer is moved to
+	Email string `json:"email"`
+
+	// Last completed step of the migration
+	// +kubebuilder:validation:Enum=Created;ProjectsMoved;OrganizationsMoved;Completed
+	// +optional
+	Phase CustomerMigrationPhase `json:"phase,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -98,42 +155,41 @@ func (customer *Customer) SetReconcileStatus(reconcileStatus []metav1.Condition)
 	customer.Status.Conditions = reconcileStatus
 }
 
+// IsMigrating reports whether the customer is being moved to a new account, which was already created on JSD
+func (customer *Customer) IsMigrating() bool {
+	migration := customer.Status.Migration
+	return migration != nil && len(migration.Phase) > 0 && migration.Phase != CustomerMigrationCompleted
+}
+
 func (customer *Customer) IsValid() (bool, error) {
 
 	if duplicateKeysExist(customer.Spec.Projects) {
 		return false, errors.New(duplicateKeysErr)
 	}
 
+	if duplicateKeysExist(customer.Spec.Organizations) {
+		return false, errors.New(duplicateOrganizationsErr)
+	}
+
 	return true, nil
 }
 
 func (customer *Customer) IsValidUpdate(existingCustomer Customer) (bool, error) {
 
-	if !strings.EqualFold(customer.Spec.Email, existingCustomer.Spec.Email) {
-		return false, fmt.Errorf("%s %s", "CustomerEmail", invalidUpdateErrorMsg)
-	}
-	if customer.Spec.Name != existingCustomer.Spec.Name {
-		return false, fmt.Errorf("%s %s", "CustomerName", invalidUpdateErrorMsg)
-	}
-
 	if customer.Spec.LegacyCustomer != existingCustomer.Spec.LegacyCustomer {
 		return false, fmt.Errorf("%s %s", "LegacyCustomer", invalidUpdateErrorMsg)
 	}
 
+	if ok, err := isValidConnectionRefUpdate(customer.Spec.ConnectionRef, existingCustomer.Spec.ConnectionRef); !ok {
+		return false, err
+	}
+
 	if duplicateKeysExist(customer.Spec.Projects) {
 		return false, errors.New(duplicateKeysErr)
 	}
 
-	return true, nil
-}
-
-func (customer *Customer) IsValidCustomerUpdate(existingCustomer Customer) (bool, error) {
-	if !strings.EqualFold(customer.Spec.Email, existingCustomer.Spec.Email) {
-		// It takes a few seconds for customers to be persisted at JSD. Check if it's pending with known values are return.
-		if existingCustomer.Spec.Name == "User " && existingCustomer.Spec.Email == "?" {
-			return true, nil
-		}
-		return false, fmt.Errorf("%s %s", "Customer email", invalidUpdateErrorMsg)
+	if duplicateKeysExist(customer.Spec.Organizations) {
+		return false, errors.New(duplicateOrganizationsErr)
 	}
 
 	return true, nil
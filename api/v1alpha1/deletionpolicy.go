@@ -0,0 +1,39 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// DeletionPolicy specifies what happens on JSD when a custom resource is deleted
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// Delete the object from JSD
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// Keep the object and its associations on JSD as they are
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// Keep the object on JSD, but remove the associations that were managed by the custom resource
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// IsValid checks whether the deletion policy is one of the supported policies
+func (policy DeletionPolicy) IsValid() bool {
+	switch policy {
+	case DeletionPolicyDelete, DeletionPolicyRetain, DeletionPolicyOrphan:
+		return true
+	}
+	return false
+}
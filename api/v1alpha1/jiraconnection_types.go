@@ -0,0 +1,133 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	JiraConnectionKind        string = "JiraConnection"
+	ClusterJiraConnectionKind string = "ClusterJiraConnection"
+)
+
+// ConnectionReference references the JiraConnection or ClusterJiraConnection of the Jira site a resource is managed on
+type ConnectionReference struct {
+	// Kind of the connection
+	// +kubebuilder:validation:Enum=JiraConnection;ClusterJiraConnection
+	// +kubebuilder:default=JiraConnection
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the connection. A JiraConnection has to be in the same namespace as the referencing resource
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+// ConnectionSecretReference references the secret holding the credentials of a Jira site. The secret has the same
+// keys as the secret of the operator: JIRA_SERVICE_DESK_API_TOKEN, JIRA_SERVICE_DESK_API_BASE_URL and JIRA_SERVICE_DESK_EMAIL
+type ConnectionSecretReference struct {
+	// Name of the secret
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+// ClusterConnectionSecretReference references the secret holding the credentials of a Jira site in any namespace
+type ClusterConnectionSecretReference struct {
+	// Name of the secret
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the secret
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Namespace string `json:"namespace"`
+}
+
+// JiraConnectionSpec defines the Jira site a JiraConnection connects to
+type JiraConnectionSpec struct {
+	// Secret, in the same namespace, holding the credentials of the Jira site
+	// +required
+	SecretRef ConnectionSecretReference `json:"secretRef"`
+}
+
+//+kubebuilder:object:root=true
+
+// JiraConnection is the Schema for the jiraconnections API
+type JiraConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec JiraConnectionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// JiraConnectionList contains a list of JiraConnection
+type JiraConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JiraConnection `json:"items"`
+}
+
+// ClusterJiraConnectionSpec defines the Jira site a ClusterJiraConnection connects to
+type ClusterJiraConnectionSpec struct {
+	// Secret holding the credentials of the Jira site
+	// +required
+	SecretRef ClusterConnectionSecretReference `json:"secretRef"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ClusterJiraConnection is the Schema for the clusterjiraconnections API
+type ClusterJiraConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterJiraConnectionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterJiraConnectionList contains a list of ClusterJiraConnection
+type ClusterJiraConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterJiraConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JiraConnection{}, &JiraConnectionList{})
+	SchemeBuilder.Register(&ClusterJiraConnection{}, &ClusterJiraConnectionList{})
+}
+
+// isValidConnectionRefUpdate checks that a resource is not moved to another Jira site
+func isValidConnectionRefUpdate(connectionRef *ConnectionReference, oldConnectionRef *ConnectionReference) (bool, error) {
+	if connectionRef == nil && oldConnectionRef == nil {
+		return true, nil
+	}
+	if connectionRef == nil || oldConnectionRef == nil || *connectionRef != *oldConnectionRef {
+		return false, fmt.Errorf("%s %s", "ConnectionRef", errorImmutableFieldMsg)
+	}
+	return true, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrganizationSpec defines the desired state of Organization
+type OrganizationSpec struct {
+	// Name of the organization
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// List of ProjectKeys of the service desks to which the organization will be added
+	// +optional
+	Projects []string `json:"projects,omitempty"`
+
+	// Connection of the Jira site the organization is managed on. If not given, the site configured for the operator is used.
+	// Customers in the organization have to use the same connection
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+}
+
+// OrganizationStatus defines the observed state of Organization
+type OrganizationStatus struct {
+	// Jira Service Desk Organization Id
+	OrganizationId string `json:"organizationId"`
+
+	// Generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// List of ProjectKeys of the service desks to which the organization has been added
+	AssociatedProjects []string `json:"associatedProjects,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// Organization is the Schema for the organizations API
+type Organization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrganizationSpec   `json:"spec,omitempty"`
+	Status OrganizationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OrganizationList contains a list of Organization
+type OrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Organization `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Organization{}, &OrganizationList{})
+}
+
+func (organization *Organization) GetReconcileStatus() []metav1.Condition {
+	return organization.Status.Conditions
+}
+
+func (organization *Organization) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	organization.Status.Conditions = reconcileStatus
+}
+
+func (organization *Organization) IsValid() (bool, error) {
+
+	if duplicateKeysExist(organization.Spec.Projects) {
+		return false, errors.New(duplicateKeysErr)
+	}
+
+	return true, nil
+}
+
+func (organization *Organization) IsValidUpdate(existingOrganization Organization) (bool, error) {
+	// Organizations can be renamed and moved between service desks, but not between Jira sites
+	if ok, err := isValidConnectionRefUpdate(organization.Spec.ConnectionRef, existingOrganization.Spec.ConnectionRef); !ok {
+		return false, err
+	}
+
+	return organization.IsValid()
+}
@@ -19,11 +19,15 @@ package v1alpha1
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	errorImmutableFieldMsg string = "is an immutable field, can't be changed while updating"
+
+	// Project type of service desks, the only projects customers have access to
+	ServiceDeskProjectTypeKey string = "service_desk"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -60,7 +64,7 @@ type ProjectSpec struct {
 	// +required
 	AssigneeType string `json:"assigneeType"`
 
-	// ID of project lead
+	// Account ID of the project lead, or the username of the lead on Jira Data Center
 	// +kubebuilder:validation:MaxLength=128
 	// +required
 	LeadAccountId string `json:"leadAccountId"`
@@ -70,29 +74,86 @@ type ProjectSpec struct {
 	// +optional
 	URL string `json:"url,omitempty"`
 
-	// An integer value for the project's avatar.
+	// An integer value for the project's avatar. Can not be given together with avatar
 	// +optional
 	AvatarId int `json:"avatarId,omitempty"`
 
-	// The ID of the issue security scheme for the project, which enables you to control who can and cannot view issues
+	// Image that is uploaded and selected as the avatar of the project. The image is uploaded again when its content changes
+	// +optional
+	Avatar *ProjectAvatar `json:"avatar,omitempty"`
+
+	// The ID of the issue security scheme for the project, which enables you to control who can and cannot view issues.
+	// If not given, the issue security scheme of the project is left as it is
 	// +optional
 	IssueSecurityScheme int `json:"issueSecurityScheme,omitempty"`
 
-	// The ID of the permission scheme for the project
+	// The ID of the permission scheme for the project. If not given, the permission scheme of the project is left as it is
 	// +optional
 	PermissionScheme int `json:"permissionScheme,omitempty"`
 
-	// The ID of the notification scheme for the project
+	// The ID of the notification scheme for the project. If not given, the notification scheme of the project is left as it is
 	// +optional
 	NotificationScheme int `json:"notificationScheme,omitempty"`
 
-	// The ID of the project's category
+	// The ID of the project's category. If not given, the category of the project is left as it is
 	// +optional
 	CategoryId int `json:"categoryId,omitempty"`
 
 	// The Open Access status, which dictates who can access the project. If set to true all customers can access the project. If false, only customers added to project can access the project.
+	// Deprecated: use customerAccess instead. Only used when the project is created without customerAccess, in which case false
+	// turns off open access and public signup of a service_desk project and leaves its other settings as they are
 	// +optional, if not provided default behaviour is False
 	OpenAccess bool `json:"openAccess,omitempty"`
+
+	// Which customers can access the project and what they can do. Only supported by service_desk projects.
+	// If not given, the customer access of the project is not managed
+	// +optional
+	CustomerAccess *CustomerAccess `json:"customerAccess,omitempty"`
+
+	// What happens to the project on JSD when the custom resource is deleted. Orphan behaves like Retain for projects.
+	// If not given, the default deletion policy of the operator is used
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Connection of the Jira site the project is managed on. If not given, the site configured for the operator is used
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+}
+
+// CustomerAccess defines the request security settings of a service desk project
+type CustomerAccess struct {
+	// All customers can raise requests in the project. If false, only customers added to the project can raise requests
+	// +optional
+	OpenAccess bool `json:"openAccess,omitempty"`
+
+	// Anyone can sign up as a customer from the help center of the project. Requires openAccess
+	// +optional
+	PublicSignup bool `json:"publicSignup,omitempty"`
+
+	// Customers can search for other customers of the project, e.g. when sharing requests
+	// +optional
+	AutocompleteEnabled bool `json:"autocompleteEnabled,omitempty"`
+
+	// Customers can add other customers to the project, e.g. by sharing requests with them
+	// +optional
+	ManageEnabled bool `json:"manageEnabled,omitempty"`
+}
+
+// ProjectAvatar is the source of the image of a project avatar. Exactly one source has to be given
+type ProjectAvatar struct {
+	// Key of a ConfigMap, in the same namespace, holding a PNG or SVG image. The key is looked up in binaryData before data
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// Key of a Secret, in the same namespace, holding a PNG or SVG image
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// HTTPS URL of a PNG or SVG image, which is downloaded by the operator. Hosts with private, loopback or link-local
+	// addresses are rejected
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 // ProjectStatus defines the observed state of Project
@@ -100,6 +161,21 @@ type ProjectStatus struct {
 	// Jira service desk project ID
 	ID string `json:"id"`
 
+	// ID of the avatar that was uploaded from spec.avatar
+	AvatarId string `json:"avatarId,omitempty"`
+
+	// SHA-256 hash of the image of the uploaded avatar, to notice changes of its source
+	AvatarHash string `json:"avatarHash,omitempty"`
+
+	// URL the uploaded avatar was downloaded from
+	AvatarURL string `json:"avatarUrl,omitempty"`
+
+	// ETag of the image at avatarUrl, so that the image is only downloaded again when it changed
+	AvatarETag string `json:"avatarETag,omitempty"`
+
+	// Generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Status conditions
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -138,7 +214,32 @@ func (project *Project) SetReconcileStatus(reconcileStatus []metav1.Condition) {
 }
 
 func (project *Project) IsValid() (bool, error) {
-	// TODO: Add logic for additional validation here
+	if avatar := project.Spec.Avatar; avatar != nil {
+		if project.Spec.AvatarId != 0 {
+			return false, fmt.Errorf("AvatarId and Avatar can not be given together")
+		}
+		sources := 0
+		if avatar.ConfigMapKeyRef != nil {
+			sources++
+		}
+		if avatar.SecretKeyRef != nil {
+			sources++
+		}
+		if len(avatar.URL) > 0 {
+			sources++
+		}
+		if sources != 1 {
+			return false, fmt.Errorf("Avatar needs exactly one of ConfigMapKeyRef, SecretKeyRef or URL")
+		}
+	}
+	if project.Spec.CustomerAccess != nil {
+		if project.Spec.ProjectTypeKey != ServiceDeskProjectTypeKey {
+			return false, fmt.Errorf("CustomerAccess is only supported by %s projects", ServiceDeskProjectTypeKey)
+		}
+		if project.Spec.CustomerAccess.PublicSignup && !project.Spec.CustomerAccess.OpenAccess {
+			return false, fmt.Errorf("CustomerAccess PublicSignup requires OpenAccess")
+		}
+	}
 	return true, nil
 }
 
@@ -150,24 +251,6 @@ func (project *Project) IsValidUpdate(existingProject Project) (bool, error) {
 	if project.Spec.ProjectTypeKey != existingProject.Spec.ProjectTypeKey {
 		return false, fmt.Errorf("%s %s", "ProjectTypeKey", errorImmutableFieldMsg)
 	}
-	if project.Spec.LeadAccountId != existingProject.Spec.LeadAccountId {
-		return false, fmt.Errorf("%s %s", "LeadAccountId", errorImmutableFieldMsg)
-	}
-	if project.Spec.CategoryId != existingProject.Spec.CategoryId {
-		return false, fmt.Errorf("%s %s", "CategoryId", errorImmutableFieldMsg)
-	}
-	if project.Spec.NotificationScheme != existingProject.Spec.NotificationScheme {
-		return false, fmt.Errorf("%s %s", "NotificationScheme", errorImmutableFieldMsg)
-	}
-	if project.Spec.PermissionScheme != existingProject.Spec.PermissionScheme {
-		return false, fmt.Errorf("%s %s", "PermissionScheme", errorImmutableFieldMsg)
-	}
-	if project.Spec.IssueSecurityScheme != existingProject.Spec.IssueSecurityScheme {
-		return false, fmt.Errorf("%s %s", "IssueSecurityScheme", errorImmutableFieldMsg)
-	}
-	if project.Spec.OpenAccess != existingProject.Spec.OpenAccess {
-		return false, fmt.Errorf("%s %s", "OpenAccess", errorImmutableFieldMsg)
-	}
 
 	return true, nil
 }
@@ -68,6 +68,9 @@ func (r *Project) ValidateUpdate(old runtime.Object) error {
 	if !ok {
 		return fmt.Errorf("Error casting old runtime object to %T from %T", oldProject, old)
 	}
+	if ok, err := isValidConnectionRefUpdate(r.Spec.ConnectionRef, oldProject.Spec.ConnectionRef); !ok {
+		return err
+	}
 	_, err := r.IsValidUpdate(*oldProject)
 	return err
 }
@@ -0,0 +1,166 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	duplicateColumnsErr string = "Duplicate Columns are not allowed"
+)
+
+// QueueOrderBy defines the ordering of the issues in a queue
+type QueueOrderBy struct {
+	// Field by which the issues in the queue are ordered, e.g. priority or created
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Field string `json:"field"`
+
+	// Direction in which the issues are ordered
+	// +kubebuilder:validation:Enum=ASC;DESC
+	// +optional
+	Direction string `json:"direction,omitempty"`
+}
+
+// QueueSpec defines the desired state of Queue
+type QueueSpec struct {
+	// Name of the Project custom resource, in the same namespace, whose service desk the queue belongs to
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// Key of the project whose service desk the queue belongs to. Use it for projects that are not managed by a Project custom resource
+	// +kubebuilder:validation:MaxLength=10
+	// +kubebuilder:validation:Pattern=^[A-Z][A-Z0-9]+$
+	// +optional
+	ProjectKey string `json:"projectKey,omitempty"`
+
+	// Name of the queue
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// JQL query that selects the issues shown in the queue. It must not contain an ORDER BY clause, use OrderBy instead
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	JQL string `json:"jql"`
+
+	// List of issue field IDs shown as columns in the queue, e.g. issuekey, summary, status
+	// +optional
+	Columns []string `json:"columns,omitempty"`
+
+	// Ordering of the issues in the queue
+	// +optional
+	OrderBy *QueueOrderBy `json:"orderBy,omitempty"`
+	// Connection of the Jira site the queue is managed on. If not given, the site configured for the operator is used.
+	// A Project referenced by projectName has to use the same connection
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+}
+
+// QueueStatus defines the observed state of Queue
+type QueueStatus struct {
+	// Jira Service Desk Queue Id
+	QueueId string `json:"queueId"`
+
+	// Generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Key of the project whose service desk the queue has been created in
+	ProjectKey string `json:"projectKey,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// Queue is the Schema for the queues API
+type Queue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QueueSpec   `json:"spec,omitempty"`
+	Status QueueStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// QueueList contains a list of Queue
+type QueueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Queue `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Queue{}, &QueueList{})
+}
+
+func (queue *Queue) GetReconcileStatus() []metav1.Condition {
+	return queue.Status.Conditions
+}
+
+func (queue *Queue) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	queue.Status.Conditions = reconcileStatus
+}
+
+// GetQueueJQL returns the JQL of the queue including its ordering
+func (queue *Queue) GetQueueJQL() string {
+	if queue.Spec.OrderBy == nil {
+		return queue.Spec.JQL
+	}
+
+	jql := queue.Spec.JQL + " ORDER BY " + queue.Spec.OrderBy.Field
+	if len(queue.Spec.OrderBy.Direction) > 0 {
+		jql += " " + queue.Spec.OrderBy.Direction
+	}
+	return jql
+}
+
+func (queue *Queue) IsValid() (bool, error) {
+
+	if (queue.Spec.ProjectName == "") == (queue.Spec.ProjectKey == "") {
+		return false, errors.New(invalidProjectReferenceErr)
+	}
+
+	if duplicateKeysExist(queue.Spec.Columns) {
+		return false, errors.New(duplicateColumnsErr)
+	}
+
+	return true, nil
+}
+
+func (queue *Queue) IsValidUpdate(existingQueue Queue) (bool, error) {
+	// Queues can not be moved between service desks
+
+	if queue.Spec.ProjectName != existingQueue.Spec.ProjectName {
+		return false, fmt.Errorf("%s %s", "ProjectName", errorImmutableFieldMsg)
+	}
+	if queue.Spec.ProjectKey != existingQueue.Spec.ProjectKey {
+		return false, fmt.Errorf("%s %s", "ProjectKey", errorImmutableFieldMsg)
+	}
+	if ok, err := isValidConnectionRefUpdate(queue.Spec.ConnectionRef, existingQueue.Spec.ConnectionRef); !ok {
+		return false, err
+	}
+
+	return queue.IsValid()
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	invalidProjectReferenceErr string = "Exactly one of ProjectName and ProjectKey must be set"
+	duplicateGroupIdsErr       string = "Duplicate Group Ids are not allowed"
+)
+
+// RequestTypeSpec defines the desired state of RequestType
+type RequestTypeSpec struct {
+	// Name of the Project custom resource, in the same namespace, whose service desk the request type belongs to
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// Key of the project whose service desk the request type belongs to. Use it for projects that are not managed by a Project custom resource
+	// +kubebuilder:validation:MaxLength=10
+	// +kubebuilder:validation:Pattern=^[A-Z][A-Z0-9]+$
+	// +optional
+	ProjectKey string `json:"projectKey,omitempty"`
+
+	// Name of the request type on the customer portal
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// Description of the request type on the customer portal
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Help text shown to customers while raising a request of this type
+	// +optional
+	HelpText string `json:"helpText,omitempty"`
+
+	// ID of the issue type that requests of this type are created as
+	// +kubebuilder:validation:Pattern=^[0-9]+$
+	// +required
+	IssueTypeId string `json:"issueTypeId"`
+
+	// List of IDs of the request type groups, i.e. portal groups, in which the request type will be shown
+	// +optional
+	GroupIds []string `json:"groupIds,omitempty"`
+	// Connection of the Jira site the request type is managed on. If not given, the site configured for the operator is used.
+	// A Project referenced by projectName has to use the same connection
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+}
+
+// RequestTypeStatus defines the observed state of RequestType
+type RequestTypeStatus struct {
+	// Jira Service Desk Request Type Id
+	RequestTypeId string `json:"requestTypeId"`
+
+	// Generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Key of the project whose service desk the request type has been created in
+	ProjectKey string `json:"projectKey,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// RequestType is the Schema for the requesttypes API
+type RequestType struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RequestTypeSpec   `json:"spec,omitempty"`
+	Status RequestTypeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RequestTypeList contains a list of RequestType
+type RequestTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RequestType `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RequestType{}, &RequestTypeList{})
+}
+
+func (requestType *RequestType) GetReconcileStatus() []metav1.Condition {
+	return requestType.Status.Conditions
+}
+
+func (requestType *RequestType) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	requestType.Status.Conditions = reconcileStatus
+}
+
+func (requestType *RequestType) IsValid() (bool, error) {
+
+	if (requestType.Spec.ProjectName == "") == (requestType.Spec.ProjectKey == "") {
+		return false, errors.New(invalidProjectReferenceErr)
+	}
+
+	if duplicateKeysExist(requestType.Spec.GroupIds) {
+		return false, errors.New(duplicateGroupIdsErr)
+	}
+
+	return true, nil
+}
+
+func (requestType *RequestType) IsValidUpdate(existingRequestType RequestType) (bool, error) {
+	// The servicedeskapi does not support updating request types, so every field is immutable
+
+	if requestType.Spec.ProjectName != existingRequestType.Spec.ProjectName {
+		return false, fmt.Errorf("%s %s", "ProjectName", errorImmutableFieldMsg)
+	}
+	if requestType.Spec.ProjectKey != existingRequestType.Spec.ProjectKey {
+		return false, fmt.Errorf("%s %s", "ProjectKey", errorImmutableFieldMsg)
+	}
+	if requestType.Spec.Name != existingRequestType.Spec.Name {
+		return false, fmt.Errorf("%s %s", "Name", errorImmutableFieldMsg)
+	}
+	if requestType.Spec.Description != existingRequestType.Spec.Description {
+		return false, fmt.Errorf("%s %s", "Description", errorImmutableFieldMsg)
+	}
+	if requestType.Spec.HelpText != existingRequestType.Spec.HelpText {
+		return false, fmt.Errorf("%s %s", "HelpText", errorImmutableFieldMsg)
+	}
+	if requestType.Spec.IssueTypeId != existingRequestType.Spec.IssueTypeId {
+		return false, fmt.Errorf("%s %s", "IssueTypeId", errorImmutableFieldMsg)
+	}
+	if !reflect.DeepEqual(requestType.Spec.GroupIds, existingRequestType.Spec.GroupIds) {
+		return false, fmt.Errorf("%s %s", "GroupIds", errorImmutableFieldMsg)
+	}
+	if ok, err := isValidConnectionRefUpdate(requestType.Spec.ConnectionRef, existingRequestType.Spec.ConnectionRef); !ok {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultGoalNotLastErr  string = "The SLA goal without JQL applies to all remaining issues and must be the last goal"
+	invalidGoalDurationErr string = "SLA goal durations must be greater than zero"
+)
+
+// SLACondition identifies a condition that starts, pauses or stops the SLA clock
+type SLACondition struct {
+	// Key of the condition factory providing the condition, e.g. issue-created-sla-condition-factory
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	FactoryKey string `json:"factoryKey"`
+
+	// Id of the condition provided by the factory, e.g. issue-created-hit-condition
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	ConditionId string `json:"conditionId"`
+}
+
+// SLAGoal defines the target time for the issues matched by its JQL
+type SLAGoal struct {
+	// JQL query that selects the issues the goal applies to. If empty, the goal applies to all remaining issues
+	// +optional
+	JQL string `json:"jql,omitempty"`
+
+	// Target time of the goal, e.g. 4h or 30m
+	// +required
+	Duration metav1.Duration `json:"duration"`
+
+	// Id of the calendar used to count the time. If not given, time is counted 24/7
+	// +optional
+	CalendarId int `json:"calendarId,omitempty"`
+}
+
+// SLASpec defines the desired state of SLA
+type SLASpec struct {
+	// Name of the Project custom resource, in the same namespace, whose service desk the SLA belongs to
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// Key of the project whose service desk the SLA belongs to. Use it for projects that are not managed by a Project custom resource
+	// +kubebuilder:validation:MaxLength=10
+	// +kubebuilder:validation:Pattern=^[A-Z][A-Z0-9]+$
+	// +optional
+	ProjectKey string `json:"projectKey,omitempty"`
+
+	// Name of the SLA metric, e.g. Time to first response
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// Conditions that start the SLA clock
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	StartConditions []SLACondition `json:"startConditions"`
+
+	// Conditions that pause the SLA clock
+	// +optional
+	PauseConditions []SLACondition `json:"pauseConditions,omitempty"`
+
+	// Conditions that stop the SLA clock
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	StopConditions []SLACondition `json:"stopConditions"`
+
+	// Goals of the SLA. They are evaluated in the given order and the first goal whose JQL matches an issue applies to it
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Goals []SLAGoal `json:"goals"`
+	// Connection of the Jira site the SLA is managed on. If not given, the site configured for the operator is used.
+	// A Project referenced by projectName has to use the same connection
+	// +optional
+	ConnectionRef *ConnectionReference `json:"connectionRef,omitempty"`
+}
+
+// SLAStatus defines the observed state of SLA
+type SLAStatus struct {
+	// Jira Service Desk SLA metric Id
+	SLAId string `json:"slaId"`
+
+	// Generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Key of the project whose service desk the SLA has been created in
+	ProjectKey string `json:"projectKey,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// SLA is the Schema for the slas API
+type SLA struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SLASpec   `json:"spec,omitempty"`
+	Status SLAStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SLAList contains a list of SLA
+type SLAList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLA `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SLA{}, &SLAList{})
+}
+
+func (sla *SLA) GetReconcileStatus() []metav1.Condition {
+	return sla.Status.Conditions
+}
+
+func (sla *SLA) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	sla.Status.Conditions = reconcileStatus
+}
+
+func (sla *SLA) IsValid() (bool, error) {
+
+	if (sla.Spec.ProjectName == "") == (sla.Spec.ProjectKey == "") {
+		return false, errors.New(invalidProjectReferenceErr)
+	}
+
+	for i, goal := range sla.Spec.Goals {
+		if goal.Duration.Duration <= 0 {
+			return false, errors.New(invalidGoalDurationErr)
+		}
+		if goal.JQL == "" && i != len(sla.Spec.Goals)-1 {
+			return false, errors.New(defaultGoalNotLastErr)
+		}
+	}
+
+	return true, nil
+}
+
+func (sla *SLA) IsValidUpdate(existingSLA SLA) (bool, error) {
+	// SLAs can not be moved between service desks
+
+	if sla.Spec.ProjectName != existingSLA.Spec.ProjectName {
+		return false, fmt.Errorf("%s %s", "ProjectName", errorImmutableFieldMsg)
+	}
+	if sla.Spec.ProjectKey != existingSLA.Spec.ProjectKey {
+		return false, fmt.Errorf("%s %s", "ProjectKey", errorImmutableFieldMsg)
+	}
+	if ok, err := isValidConnectionRefUpdate(sla.Spec.ConnectionRef, existingSLA.Spec.ConnectionRef); !ok {
+		return false, err
+	}
+
+	return sla.IsValid()
+}
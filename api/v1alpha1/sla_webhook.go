@@ -0,0 +1,90 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var slalog = logf.Log.WithName("sla-resource")
+
+func (r *SLA) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+
+//+kubebuilder:webhook:path=/mutate-jiraservicedesk-stakater-com-v1alpha1-sla,mutating=true,failurePolicy=fail,sideEffects=None,groups=jiraservicedesk.stakater.com,resources=slas,verbs=create;update,versions=v1alpha1,name=msla.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &SLA{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *SLA) Default() {
+	slalog.Info("default", "name", r.Name)
+
+	// TODO(user): fill in your defaulting logic.
+}
+
+// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
+//+kubebuilder:webhook:path=/validate-jiraservicedesk-stakater-com-v1alpha1-sla,mutating=false,failurePolicy=fail,sideEffects=None,groups=jiraservicedesk.stakater.com,resources=slas,verbs=create;update,versions=v1alpha1,name=vsla.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SLA{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SLA) ValidateCreate() error {
+	slalog.Info("validate create", "name", r.Name)
+
+	_, err := r.IsValid()
+	return err
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SLA) ValidateUpdate(old runtime.Object) error {
+	slalog.Info("validate update", "name", r.Name)
+
+	oldSLA, ok := old.(*SLA)
+	if !ok {
+		return fmt.Errorf("Error casting old runtime object to %T from %T", oldSLA, old)
+	}
+
+	_, err := r.IsValid()
+	if err != nil {
+		return err
+	}
+	_, err = r.IsValidUpdate(*oldSLA)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SLA) ValidateDelete() error {
+	slalog.Info("validate delete", "name", r.Name)
+
+	// TODO(user): fill in your validation logic upon object deletion.
+	return nil
+}
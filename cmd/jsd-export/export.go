@@ -0,0 +1,163 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// manifest is a custom resource as it is written by the export, without status and server side metadata
+type manifest struct {
+	metav1.TypeMeta `json:",inline"`
+	Metadata        manifestMetadata `json:"metadata"`
+	Spec            interface{}      `json:"spec"`
+}
+
+type manifestMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// exporter writes Project and Customer manifests for the service desks of a Jira site
+type exporter struct {
+	jiraServiceDeskClient jiraservicedeskclient.Client
+	namespace             string
+	deletionPolicy        jiraservicedeskv1alpha1.DeletionPolicy
+	names                 map[string]bool
+}
+
+func newExporter(jiraServiceDeskClient jiraservicedeskclient.Client, namespace string, deletionPolicy jiraservicedeskv1alpha1.DeletionPolicy) *exporter {
+	return &exporter{
+		jiraServiceDeskClient: jiraServiceDeskClient,
+		namespace:             namespace,
+		deletionPolicy:        deletionPolicy,
+		names:                 map[string]bool{},
+	}
+}
+
+// Export writes a Project manifest for every service desk project and a Customer manifest for every customer
+// of these projects. All manifests carry the import annotation, so applying them adopts the existing objects
+func (e *exporter) Export(ctx context.Context, out io.Writer) error {
+	projectKeys, err := e.jiraServiceDeskClient.GetServiceDeskProjectKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	var accountIds []string
+	customers := map[string]*jiraservicedeskclient.Customer{}
+
+	for _, projectKey := range projectKeys {
+		project, err := e.jiraServiceDeskClient.GetProjectByIdentifier(ctx, projectKey)
+		if err != nil {
+			return err
+		}
+
+		projectCR := e.jiraServiceDeskClient.GetProjectCRFromProject(project)
+		projectCR.Spec.DeletionPolicy = e.deletionPolicy
+
+		err = e.write(out, "Project", e.resourceName(project.Key), project.Id, projectCR.Spec)
+		if err != nil {
+			return err
+		}
+
+		projectCustomers, err := e.jiraServiceDeskClient.GetCustomersByProjectKey(ctx, projectKey)
+		if err != nil {
+			return err
+		}
+
+		// A customer can be part of several service desks, but is exported only once
+		for _, customer := range projectCustomers {
+			if existingCustomer, ok := customers[customer.AccountId]; ok {
+				existingCustomer.ProjectKeys = append(existingCustomer.ProjectKeys, projectKey)
+				continue
+			}
+			newCustomer := customer
+			newCustomer.ProjectKeys = []string{projectKey}
+			customers[customer.AccountId] = &newCustomer
+			accountIds = append(accountIds, customer.AccountId)
+		}
+	}
+
+	for _, accountId := range accountIds {
+		customer := customers[accountId]
+
+		customerCR := e.jiraServiceDeskClient.GetCustomerCRFromCustomer(*customer)
+		customerCR.Spec.DeletionPolicy = e.deletionPolicy
+
+		name := customer.Email
+		if len(name) == 0 {
+			name = customer.AccountId
+		}
+
+		err = e.write(out, "Customer", e.resourceName(name), customer.AccountId, customerCR.Spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) write(out io.Writer, kind string, name string, importId string, spec interface{}) error {
+	data, err := yaml.Marshal(manifest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: jiraservicedeskv1alpha1.GroupVersion.String(),
+			Kind:       kind,
+		},
+		Metadata: manifestMetadata{
+			Name:        name,
+			Namespace:   e.namespace,
+			Annotations: map[string]string{jiraservicedeskv1alpha1.ImportAnnotation: importId},
+		},
+		Spec: spec,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, "---\n"+string(data))
+	return err
+}
+
+// resourceName derives a unique kubernetes resource name from a project key or customer email
+func (e *exporter) resourceName(value string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+	if len(name) > 56 {
+		name = strings.Trim(name[:56], "-")
+	}
+
+	uniqueName := name
+	for i := 2; e.names[uniqueName]; i++ {
+		uniqueName = name + "-" + strconv.Itoa(i)
+	}
+	e.names[uniqueName] = true
+
+	return uniqueName
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+	"sigs.k8s.io/yaml"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+// newJiraServer starts a local stand-in for a Jira site with two service desks sharing a customer
+func newJiraServer() *httptest.Server {
+	responses := map[string]interface{}{
+		jiraservicedeskclient.ServiceDeskApiPath: map[string]interface{}{
+			"isLastPage": true,
+			"values": []map[string]string{
+				{"id": "1", "projectId": "10001", "projectKey": "SUP"},
+				{"id": "2", "projectId": "10002", "projectKey": "OPS"},
+			},
+		},
+		jiraservicedeskclient.EndpointApiVersion3Project + "/SUP": map[string]interface{}{
+			"id":              "10001",
+			"key":             "SUP",
+			"name":            "Support",
+			"description":     "Customer support",
+			"projectTypeKey":  "service_desk",
+			"style":           "classic",
+			"assigneeType":    "PROJECT_LEAD",
+			"lead":            map[string]string{"accountId": "lead123"},
+			"projectCategory": map[string]string{"id": "10200", "name": "Support"},
+		},
+		jiraservicedeskclient.EndpointApiVersion3Project + "/10001" + jiraservicedeskclient.PermissionSchemePath:   map[string]interface{}{"id": 10100, "name": "Support permissions"},
+		jiraservicedeskclient.EndpointApiVersion3Project + "/10001" + jiraservicedeskclient.NotificationSchemePath: map[string]interface{}{"id": 10000, "name": "Default Notification Scheme"},
+		jiraservicedeskclient.EndpointApiVersion3Project + "/OPS": map[string]interface{}{
+			"id":             "10002",
+			"key":            "OPS",
+			"name":           "Operations",
+			"description":    "Operations desk",
+			"projectTypeKey": "service_desk",
+			"style":          "next-gen",
+			"assigneeType":   "UNASSIGNED",
+			"lead":           map[string]string{"accountId": "lead123"},
+		},
+		jiraservicedeskclient.ServiceDeskV1ApiPath + "SUP" + jiraservicedeskclient.RequestSecurityPath: map[string]bool{
+			"serviceDeskOpenAccess":   true,
+			"serviceDeskPublicSignup": false,
+		},
+		jiraservicedeskclient.ServiceDeskV1ApiPath + "OPS" + jiraservicedeskclient.RequestSecurityPath: map[string]bool{},
+		jiraservicedeskclient.AddCustomerApiPath + "SUP" + jiraservicedeskclient.ServiceDeskCustomerApiPath: map[string]interface{}{
+			"isLastPage": true,
+			"values": []map[string]string{
+				{"accountId": "qm:1", "emailAddress": "Jane.Doe@sample.com", "displayName": "Jane Doe"},
+			},
+		},
+		jiraservicedeskclient.AddCustomerApiPath + "OPS" + jiraservicedeskclient.ServiceDeskCustomerApiPath: map[string]interface{}{
+			"isLastPage": true,
+			"values": []map[string]string{
+				{"accountId": "qm:1", "emailAddress": "Jane.Doe@sample.com", "displayName": "Jane Doe"},
+				{"accountId": "qm:2", "emailAddress": "john@sample.com", "displayName": "John"},
+			},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestExporter_Export_shouldWriteManifests_whenServiceDesksExist(t *testing.T) {
+	server := newJiraServer()
+	defer server.Close()
+
+	jiraClient := jiraservicedeskclient.NewClient("", server.URL, "")
+
+	var out bytes.Buffer
+	err := newExporter(jiraClient, "support", jiraservicedeskv1alpha1.DeletionPolicyRetain).Export(context.TODO(), &out)
+	st.Expect(t, err, nil)
+
+	documents := strings.Split(strings.TrimPrefix(out.String(), "---\n"), "---\n")
+	st.Expect(t, len(documents), 4)
+
+	var project jiraservicedeskv1alpha1.Project
+	st.Expect(t, yaml.UnmarshalStrict([]byte(documents[0]), &project), nil)
+	st.Expect(t, project.Kind, "Project")
+	st.Expect(t, project.Name, "sup")
+	st.Expect(t, project.Namespace, "support")
+	st.Expect(t, project.Annotations[jiraservicedeskv1alpha1.ImportAnnotation], "10001")
+	st.Expect(t, project.Spec.Key, "SUP")
+	st.Expect(t, project.Spec.ProjectTemplateKey, jiraservicedeskclient.ClassicProjectTemplateKey)
+	st.Expect(t, project.Spec.LeadAccountId, "lead123")
+	st.Expect(t, project.Spec.DeletionPolicy, jiraservicedeskv1alpha1.DeletionPolicyRetain)
+	st.Expect(t, project.Spec.CustomerAccess, &jiraservicedeskv1alpha1.CustomerAccess{OpenAccess: true})
+	st.Expect(t, project.Spec.PermissionScheme, 10100)
+	st.Expect(t, project.Spec.NotificationScheme, 10000)
+	st.Expect(t, project.Spec.IssueSecurityScheme, 0)
+	st.Expect(t, project.Spec.CategoryId, 10200)
+	st.Expect(t, project.Status.ID, "")
+
+	var customer jiraservicedeskv1alpha1.Customer
+	st.Expect(t, yaml.UnmarshalStrict([]byte(documents[2]), &customer), nil)
+	st.Expect(t, customer.Kind, "Customer")
+	st.Expect(t, customer.Name, "jane-doe-sample-com")
+	st.Expect(t, customer.Annotations[jiraservicedeskv1alpha1.ImportAnnotation], "qm:1")
+	st.Expect(t, customer.Spec.Name, "Jane Doe")
+	st.Expect(t, customer.Spec.Projects, []string{"SUP", "OPS"})
+
+	st.Expect(t, yaml.UnmarshalStrict([]byte(documents[3]), &customer), nil)
+	st.Expect(t, customer.Name, "john-sample-com")
+	st.Expect(t, customer.Spec.Projects, []string{"OPS"})
+}
+
+func TestExporter_Export_shouldFail_whenServiceDesksCanNotBeListed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	jiraClient := jiraservicedeskclient.NewClient("", server.URL, "")
+
+	var out bytes.Buffer
+	err := newExporter(jiraClient, "", jiraservicedeskv1alpha1.DeletionPolicyRetain).Export(context.TODO(), &out)
+
+	st.Expect(t, err.Error(), "Rest request to get service desks failed with status: 401")
+	st.Expect(t, out.Len(), 0)
+}
+
+func TestExporter_resourceName_shouldReturnUniqueNames(t *testing.T) {
+	e := newExporter(nil, "", jiraservicedeskv1alpha1.DeletionPolicyRetain)
+
+	st.Expect(t, e.resourceName("SUP"), "sup")
+	st.Expect(t, e.resourceName("sup"), "sup-2")
+	st.Expect(t, e.resourceName("Jane.Doe+Test@sample.com"), "jane-doe-test-sample-com")
+}
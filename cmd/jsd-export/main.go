@@ -0,0 +1,86 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// jsd-export writes Project and Customer manifests for the service desks of an existing Jira site,
+// which can be applied to bring the site under management of the operator
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	"github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/config"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "jsd-export: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+// run exports the manifests. Errors are returned instead of exiting, so that deferred cleanup like closing
+// the output file still happens
+func run() (err error) {
+	var namespace string
+	var output string
+	var deletionPolicy string
+	flag.StringVar(&namespace, "namespace", "", "Namespace set on the exported custom resources.")
+	flag.StringVar(&output, "output", "", "File the manifests are written to. Defaults to stdout.")
+	flag.StringVar(&deletionPolicy, "deletion-policy", string(jiraservicedeskv1alpha1.DeletionPolicyRetain),
+		"Deletion policy set on the exported custom resources. One of Delete, Retain or Orphan.")
+	flag.Parse()
+
+	if !jiraservicedeskv1alpha1.DeletionPolicy(deletionPolicy).IsValid() {
+		return fmt.Errorf("invalid deletion policy %s", deletionPolicy)
+	}
+
+	// Credentials are read from the same keys as in the secret of the operator
+	controllerConfig, err := config.LoadEnvConfig()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if len(output) > 0 {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		// Manifests that could not be written completely are reported
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		out = file
+	}
+
+	// Interrupting the export cancels the requests to Jira
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jiraServiceDeskClient := jiraservicedeskclient.NewSiteClient(controllerConfig.Site())
+
+	return newExporter(jiraServiceDeskClient, namespace, jiraservicedeskv1alpha1.DeletionPolicy(deletionPolicy)).Export(ctx, out)
+}
@@ -0,0 +1,279 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+const (
+	// Largest avatar image that is uploaded
+	AvatarMaxSize = 1 << 20
+	// Timeout of downloading an avatar image from its URL
+	AvatarDownloadTimeout = 30 * time.Second
+
+	AvatarKeyNotFoundErr string = "Key %s of %s %s does not exist"
+	AvatarDownloadErr    string = "Downloading avatar from %s failed with status: %d"
+	AvatarTooLargeErr    string = "Avatar image is larger than %d bytes"
+	AvatarFormatErr      string = "Avatar image is not a PNG or SVG image"
+	AvatarSchemeErr      string = "Avatar URL %s does not use https"
+	AvatarAddressErr     string = "Avatar host address %s is not public"
+)
+
+// avatarHTTPClient downloads avatars from public https URLs only, so that the operator can not be used to reach
+// services inside the cluster. Requests are not sent through proxies, since their addresses could not be checked
+var avatarHTTPClient = &http.Client{
+	Timeout: AvatarDownloadTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: AvatarDownloadTimeout,
+			Control: checkAvatarAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+	CheckRedirect: func(request *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return checkAvatarURL(request.URL)
+	},
+}
+
+// avatarImage is the image of a project avatar, read from its source
+type avatarImage struct {
+	contentType string
+	data        []byte
+	hash        string
+	// ETag of an image downloaded from a URL
+	etag string
+	// The image at the URL has not changed since it was downloaded with the given ETag
+	notModified bool
+}
+
+// reconcileAvatar uploads the avatar of the spec and selects it for the project, unless an avatar with the same
+// content was uploaded before. Avatars are not returned by JSD, so changes are noticed by the hash of the image
+// only, and images at URLs are only downloaded again when their ETag changed. The project is addressed by its
+// id, since its key may be changed by the same reconcile. The replaced avatar is deleted. Reports whether the
+// status of the project was changed
+func (r *ProjectReconciler) reconcileAvatar(ctx context.Context, instance *jiraservicedeskv1alpha1.Project) (bool, error) {
+	status := &instance.Status
+
+	// Removing the avatar from the spec keeps the avatar of the project as it is
+	if instance.Spec.Avatar == nil {
+		if len(status.AvatarId) == 0 && len(status.AvatarHash) == 0 && len(status.AvatarURL) == 0 && len(status.AvatarETag) == 0 {
+			return false, nil
+		}
+		status.AvatarId, status.AvatarHash, status.AvatarURL, status.AvatarETag = "", "", "", ""
+		return true, nil
+	}
+
+	// The ETag is only valid for the URL the uploaded avatar was downloaded from
+	etag := ""
+	if len(status.AvatarId) > 0 && instance.Spec.Avatar.URL == status.AvatarURL {
+		etag = status.AvatarETag
+	}
+
+	image, err := loadAvatarImage(ctx, r.APIReader, instance.Namespace, instance.Spec.Avatar, etag)
+	if err != nil {
+		return false, err
+	}
+	if image.notModified {
+		return false, nil
+	}
+	if image.hash == status.AvatarHash && len(status.AvatarId) > 0 {
+		if status.AvatarURL == instance.Spec.Avatar.URL && status.AvatarETag == image.etag {
+			return false, nil
+		}
+		status.AvatarURL, status.AvatarETag = instance.Spec.Avatar.URL, image.etag
+		return true, nil
+	}
+
+	avatarId, err := r.JiraServiceDeskClient.UploadProjectAvatar(ctx, status.ID, image.contentType, image.data)
+	if err != nil {
+		return false, err
+	}
+	err = r.JiraServiceDeskClient.SetProjectAvatar(ctx, status.ID, avatarId)
+	if err != nil {
+		return false, err
+	}
+	recordEvent(r.Recorder, instance, corev1.EventTypeNormal, UpdatedReason, "Updated avatar of project with id %s to avatar %s", status.ID, avatarId)
+
+	// Avatars that were deleted on JSD need no cleanup
+	if previousAvatarId := status.AvatarId; len(previousAvatarId) > 0 {
+		err = r.JiraServiceDeskClient.DeleteProjectAvatar(ctx, status.ID, previousAvatarId)
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			r.Log.Info("Failed to delete replaced avatar " + previousAvatarId + " of project " + status.ID + ": " + err.Error())
+		}
+	}
+
+	status.AvatarId, status.AvatarHash = avatarId, image.hash
+	status.AvatarURL, status.AvatarETag = instance.Spec.Avatar.URL, image.etag
+	return true, nil
+}
+
+// loadAvatarImage reads the image of an avatar from its ConfigMap, Secret or URL. Images at URLs are downloaded
+// conditionally if an ETag is given
+func loadAvatarImage(ctx context.Context, apiReader client.Reader, namespace string, avatar *jiraservicedeskv1alpha1.ProjectAvatar, etag string) (avatarImage, error) {
+	var data []byte
+
+	switch {
+	case avatar.ConfigMapKeyRef != nil:
+		configMap := &corev1.ConfigMap{}
+		err := apiReader.Get(ctx, types.NamespacedName{Name: avatar.ConfigMapKeyRef.Name, Namespace: namespace}, configMap)
+		if err != nil {
+			return avatarImage{}, err
+		}
+		if binaryData, ok := configMap.BinaryData[avatar.ConfigMapKeyRef.Key]; ok {
+			data = binaryData
+		} else if textData, ok := configMap.Data[avatar.ConfigMapKeyRef.Key]; ok {
+			data = []byte(textData)
+		} else {
+			return avatarImage{}, fmt.Errorf(AvatarKeyNotFoundErr, avatar.ConfigMapKeyRef.Key, "ConfigMap", avatar.ConfigMapKeyRef.Name)
+		}
+	case avatar.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		err := apiReader.Get(ctx, types.NamespacedName{Name: avatar.SecretKeyRef.Name, Namespace: namespace}, secret)
+		if err != nil {
+			return avatarImage{}, err
+		}
+		secretData, ok := secret.Data[avatar.SecretKeyRef.Key]
+		if !ok {
+			return avatarImage{}, fmt.Errorf(AvatarKeyNotFoundErr, avatar.SecretKeyRef.Key, "Secret", avatar.SecretKeyRef.Name)
+		}
+		data = secretData
+	default:
+		downloadedData, downloadedEtag, err := downloadAvatarImage(ctx, avatar.URL, etag)
+		if err != nil {
+			return avatarImage{}, err
+		}
+		if downloadedData == nil {
+			return avatarImage{etag: etag, notModified: true}, nil
+		}
+		data, etag = downloadedData, downloadedEtag
+	}
+
+	if len(data) > AvatarMaxSize {
+		return avatarImage{}, fmt.Errorf(AvatarTooLargeErr, AvatarMaxSize)
+	}
+	contentType, err := avatarContentType(data)
+	if err != nil {
+		return avatarImage{}, err
+	}
+
+	hash := sha256.Sum256(data)
+	return avatarImage{contentType: contentType, data: data, hash: hex.EncodeToString(hash[:]), etag: etag}, nil
+}
+
+// downloadAvatarImage downloads an image and returns it with its ETag. If the image has not changed since it
+// was downloaded with the given ETag, no image is returned
+func downloadAvatarImage(ctx context.Context, rawURL string, etag string) ([]byte, string, error) {
+	avatarURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	err = checkAvatarURL(avatarURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(etag) > 0 {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	response, err := avatarHTTPClient.Do(request)
+	if err != nil {
+		return nil, "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified && len(etag) > 0 {
+		return nil, etag, nil
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, "", fmt.Errorf(AvatarDownloadErr, rawURL, response.StatusCode)
+	}
+
+	// Read one byte more than the limit, so that larger images are rejected instead of being cut off
+	data, err := ioutil.ReadAll(io.LimitReader(response.Body, AvatarMaxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, response.Header.Get("ETag"), nil
+}
+
+// checkAvatarURL only allows avatars to be downloaded over https
+func checkAvatarURL(avatarURL *url.URL) error {
+	if avatarURL.Scheme != "https" {
+		return fmt.Errorf(AvatarSchemeErr, avatarURL.Redacted())
+	}
+	return nil
+}
+
+// checkAvatarAddress rejects connections to addresses that are not public, which is checked after the host of
+// the URL was resolved so that DNS names pointing to internal addresses are rejected as well
+func checkAvatarAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicAddress(ip) {
+		return fmt.Errorf(AvatarAddressErr, host)
+	}
+	return nil
+}
+
+func isPublicAddress(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// avatarContentType returns the content type of a PNG or SVG image
+func avatarContentType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if contentType == "image/png" {
+		return contentType, nil
+	}
+	// SVG images are detected as XML or text
+	if (strings.HasPrefix(contentType, "text/xml") || strings.HasPrefix(contentType, "text/plain")) && bytes.Contains(data, []byte("<svg")) {
+		return "image/svg+xml", nil
+	}
+	return "", fmt.Errorf(AvatarFormatErr)
+}
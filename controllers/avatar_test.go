@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+var _ = Describe("Project avatars", func() {
+
+	ns, _ = os.LookupEnv("OPERATOR_NAMESPACE")
+
+	projectInput := mockData.CreateProjectInput
+
+	key := cUtil.RandSeqString(3)
+	projectInput.Spec.Name += key
+	projectInput.Spec.Key = strings.ToUpper(key)
+
+	AfterEach(func() {
+		util.TryDeleteProject(projectInput.Spec.Name, ns)
+	})
+
+	Describe("Upload the avatar of a project from a ConfigMap", func() {
+		Context("With a PNG image", func() {
+			It("should select the avatar and replace it when the image changes", func() {
+				configMap := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "avatar-" + key, Namespace: ns},
+					BinaryData: map[string][]byte{"avatar.png": mockData.AvatarPNG},
+				}
+				Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+				defer func() {
+					_ = k8sClient.Delete(ctx, configMap)
+				}()
+
+				avatarProjectInput := projectInput
+				avatarProjectInput.Spec.Avatar = &jiraservicedeskv1alpha1.ProjectAvatar{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+						Key:                  "avatar.png",
+					},
+				}
+
+				_ = util.CreateProject(avatarProjectInput, ns)
+				project := util.GetProject(avatarProjectInput.Spec.Name, ns)
+
+				Expect(project.Status.ID).ToNot(Equal(""))
+				Expect(project.Status.AvatarId).ToNot(Equal(""))
+				avatarId := project.Status.AvatarId
+
+				configMap.BinaryData = map[string][]byte{"avatar.png": mockData.AvatarSVG}
+				Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: avatarProjectInput.Spec.Name, Namespace: ns}}
+				_, err := r.Reconcile(context.Background(), req)
+				Expect(err).NotTo(HaveOccurred())
+
+				updatedProject := util.GetProject(avatarProjectInput.Spec.Name, ns)
+				Expect(updatedProject.Status.AvatarId).ToNot(Equal(avatarId))
+				Expect(updatedProject.Status.AvatarHash).ToNot(Equal(project.Status.AvatarHash))
+			})
+		})
+	})
+
+	Describe("Load the image of an avatar", func() {
+		Context("From a URL", func() {
+			It("should download the image and detect its content type", func() {
+				server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("ETag", `"avatar"`)
+					if r.Header.Get("If-None-Match") == `"avatar"` {
+						w.WriteHeader(http.StatusNotModified)
+						return
+					}
+					_, _ = w.Write(mockData.AvatarSVG)
+				}))
+				defer server.Close()
+
+				// The test server listens on a loopback address, which the avatar client rejects
+				defaultClient := avatarHTTPClient
+				avatarHTTPClient = server.Client()
+				defer func() { avatarHTTPClient = defaultClient }()
+
+				avatar := &jiraservicedeskv1alpha1.ProjectAvatar{URL: server.URL}
+				image, err := loadAvatarImage(ctx, k8sClient, ns, avatar, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(image.contentType).To(Equal("image/svg+xml"))
+				Expect(image.data).To(Equal(mockData.AvatarSVG))
+				Expect(image.etag).To(Equal(`"avatar"`))
+
+				image, err = loadAvatarImage(ctx, k8sClient, ns, avatar, image.etag)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(image.notModified).To(BeTrue())
+			})
+		})
+
+		Context("From a URL that is not a public https URL", func() {
+			It("should reject the URL", func() {
+				_, err := loadAvatarImage(ctx, k8sClient, ns, &jiraservicedeskv1alpha1.ProjectAvatar{URL: "http://example.com/avatar.png"}, "")
+				Expect(err).To(MatchError(fmt.Sprintf(AvatarSchemeErr, "http://example.com/avatar.png")))
+
+				_, err = loadAvatarImage(ctx, k8sClient, ns, &jiraservicedeskv1alpha1.ProjectAvatar{URL: "https://127.0.0.1/avatar.png"}, "")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf(AvatarAddressErr, "127.0.0.1")))
+
+				for _, address := range []string{"10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "::1", "fe80::1", "0.0.0.0"} {
+					Expect(isPublicAddress(net.ParseIP(address))).To(BeFalse(), address)
+				}
+				Expect(isPublicAddress(net.ParseIP("93.184.216.34"))).To(BeTrue())
+			})
+		})
+
+		Context("With an image that is not a PNG or SVG image", func() {
+			It("should reject the image", func() {
+				_, err := avatarContentType([]byte("GIF89a"))
+				Expect(err).To(MatchError(AvatarFormatErr))
+
+				contentType, err := avatarContentType(mockData.AvatarPNG)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contentType).To(Equal("image/png"))
+			})
+		})
+	})
+})
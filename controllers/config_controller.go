@@ -0,0 +1,158 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	jiraservicedeskconfig "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/config"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+var (
+	configLoadTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "jira_service_desk_config_load_timestamp_seconds",
+		Help: "Time at which the credentials in the config secret were last loaded",
+	})
+	configInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "jira_service_desk_config_info",
+		Help: "Hash of the credentials in the config secret that are currently in use",
+	}, []string{"hash"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(configLoadTimestamp, configInfo)
+}
+
+// ConfigStatus describes the credentials that are currently in use
+type ConfigStatus struct {
+	LoadTime time.Time `json:"loadTime"`
+	Hash     string    `json:"hash"`
+}
+
+// ConfigReconciler reloads the credentials of the operator when its config secret changes
+type ConfigReconciler struct {
+	Log       logr.Logger
+	APIReader client.Reader
+	// Name and namespace of the config secret
+	SecretName      string
+	SecretNamespace string
+	// Clients whose credentials are replaced on reload
+	JiraServiceDeskClients []jiraservicedeskclient.Client
+
+	lock   sync.RWMutex
+	status ConfigStatus
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;patch
+
+func (r *ConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("secret", req.NamespacedName)
+
+	if req.Name != r.SecretName || req.Namespace != r.SecretNamespace {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	controllerConfig, err := jiraservicedeskconfig.LoadConnectionConfig(ctx, r.APIReader, r.SecretName, r.SecretNamespace)
+	if err != nil {
+		// Keep using the loaded credentials until the secret is valid again
+		log.Error(err, "Unable to reload config from secret")
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if controllerConfig.Hash() == r.GetStatus().Hash {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	for _, jiraServiceDeskClient := range r.JiraServiceDeskClients {
+		jiraServiceDeskClient.SetSite(controllerConfig.Site())
+	}
+	r.SetLoaded(controllerConfig)
+
+	log.Info("Reloaded config from secret", "hash", controllerConfig.Hash())
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetLoaded records the config that is currently in use
+func (r *ConfigReconciler) SetLoaded(controllerConfig jiraservicedeskconfig.ControllerConfig) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.status = ConfigStatus{LoadTime: time.Now(), Hash: controllerConfig.Hash()}
+
+	configLoadTimestamp.Set(float64(r.status.LoadTime.Unix()))
+	configInfo.Reset()
+	configInfo.WithLabelValues(r.status.Hash).Set(1)
+}
+
+// GetStatus returns the load time and hash of the config that is currently in use
+func (r *ConfigReconciler) GetStatus() ConfigStatus {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.status
+}
+
+// ServeHTTP serves the status of the config as json
+func (r *ConfigReconciler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(r.GetStatus())
+}
+
+func (r *ConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The config secret lives in the namespace of the operator, which may not be watched by the manager,
+	// so it is watched through a separate cache limited to the secret
+	secretCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    mgr.GetScheme(),
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: r.SecretNamespace,
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.Secret{}: {Field: fields.OneTermEqualSelector("metadata.name", r.SecretName)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(secretCache); err != nil {
+		return err
+	}
+
+	c, err := controller.New("config", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(source.NewKindWithCache(&corev1.Secret{}, secretCache), &handler.EnqueueRequestForObject{})
+}
@@ -0,0 +1,99 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	jiraservicedeskconfig "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/config"
+)
+
+const (
+	ConnectionMismatchErr string = "%s %s is managed with a different Jira connection, references can not cross Jira sites"
+)
+
+// connectionMismatchError is returned for references to resources that are managed on another Jira site
+type connectionMismatchError struct {
+	kind string
+	name string
+}
+
+func (e *connectionMismatchError) Error() string {
+	return fmt.Sprintf(ConnectionMismatchErr, e.kind, e.name)
+}
+
+// isConnectionMismatch reports whether an error is caused by a reference to a resource on another Jira site.
+// Such references are not retried, since they only resolve when the spec is changed
+func isConnectionMismatch(err error) bool {
+	var mismatchErr *connectionMismatchError
+	return errors.As(err, &mismatchErr)
+}
+
+// checkSameConnection checks that a referenced resource is managed on the same Jira site as the resource referencing it
+func checkSameConnection(connectionRef *jiraservicedeskv1alpha1.ConnectionReference, referencedConnectionRef *jiraservicedeskv1alpha1.ConnectionReference, kind string, name string) error {
+	if connectionRef == nil && referencedConnectionRef == nil {
+		return nil
+	}
+	if connectionRef == nil || referencedConnectionRef == nil || *connectionRef != *referencedConnectionRef {
+		return &connectionMismatchError{kind: kind, name: name}
+	}
+	return nil
+}
+
+// getJiraServiceDeskClient returns a client for the Jira site of the referenced connection. Resources that do not
+// reference a connection are managed on the site configured for the operator, using the default client.
+// Clients for connections are configured like the default client
+func getJiraServiceDeskClient(ctx context.Context, apiReader client.Reader, defaultClient jiraservicedeskclient.Client, namespace string, connectionRef *jiraservicedeskv1alpha1.ConnectionReference) (jiraservicedeskclient.Client, error) {
+	if connectionRef == nil {
+		return defaultClient, nil
+	}
+
+	var secretName, secretNamespace string
+
+	if connectionRef.Kind == jiraservicedeskv1alpha1.ClusterJiraConnectionKind {
+		connection := &jiraservicedeskv1alpha1.ClusterJiraConnection{}
+
+		err := apiReader.Get(ctx, types.NamespacedName{Name: connectionRef.Name}, connection)
+		if err != nil {
+			return nil, err
+		}
+		secretName, secretNamespace = connection.Spec.SecretRef.Name, connection.Spec.SecretRef.Namespace
+	} else {
+		connection := &jiraservicedeskv1alpha1.JiraConnection{}
+
+		err := apiReader.Get(ctx, types.NamespacedName{Name: connectionRef.Name, Namespace: namespace}, connection)
+		if err != nil {
+			return nil, err
+		}
+		secretName, secretNamespace = connection.Spec.SecretRef.Name, namespace
+	}
+
+	// Credentials are read on every reconcile, so changes to the secret are picked up without a restart
+	connectionConfig, err := jiraservicedeskconfig.LoadConnectionConfig(ctx, apiReader, secretName, secretNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return defaultClient.WithSite(connectionConfig.Site()), nil
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	"github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/config"
+)
+
+var _ = Describe("Jira Connections", func() {
+
+	ns, _ = os.LookupEnv("OPERATOR_NAMESPACE")
+
+	projectInput := mockData.CreateProjectInput
+
+	key := cUtil.RandSeqString(3)
+	projectInput.Spec.Name += key
+	projectInput.Spec.Key = strings.ToUpper(key)
+
+	queueInput := mockData.SampleQueue
+	queueInput.Spec.Name += key
+
+	AfterEach(func() {
+		util.TryDeleteProject(projectInput.Spec.Name, ns)
+		qUtil.TryDeleteQueue(queueInput.Spec.Name, ns)
+	})
+
+	Describe("Create Jira service desk project on the site of a JiraConnection", func() {
+		Context("With a valid JiraConnection", func() {
+			It("should create a new project", func() {
+				connection := &jiraservicedeskv1alpha1.JiraConnection{
+					ObjectMeta: metav1.ObjectMeta{Name: "connection-" + key, Namespace: ns},
+					Spec: jiraservicedeskv1alpha1.JiraConnectionSpec{
+						SecretRef: jiraservicedeskv1alpha1.ConnectionSecretReference{Name: config.JiraServiceDeskSecretName},
+					},
+				}
+				Expect(k8sClient.Create(ctx, connection)).To(Succeed())
+				defer func() {
+					_ = k8sClient.Delete(ctx, connection)
+				}()
+
+				connectedProjectInput := projectInput
+				connectedProjectInput.Spec.ConnectionRef = &jiraservicedeskv1alpha1.ConnectionReference{
+					Kind: jiraservicedeskv1alpha1.JiraConnectionKind,
+					Name: connection.Name,
+				}
+
+				_ = util.CreateProject(connectedProjectInput, ns)
+				project := util.GetProject(connectedProjectInput.Spec.Name, ns)
+
+				Expect(project.Status.ID).ToNot(Equal(""))
+			})
+		})
+
+		Context("With a missing JiraConnection", func() {
+			It("should not create the project", func() {
+				connectedProjectInput := projectInput
+				connectedProjectInput.Spec.ConnectionRef = &jiraservicedeskv1alpha1.ConnectionReference{
+					Kind: jiraservicedeskv1alpha1.JiraConnectionKind,
+					Name: "missing-connection",
+				}
+
+				_ = util.CreateProject(connectedProjectInput, ns)
+				project := util.GetProject(connectedProjectInput.Spec.Name, ns)
+
+				Expect(project.Status.ID).To(Equal(""))
+				Expect(project.Status.Conditions[0].Type).To(Equal("ReconcileError"))
+			})
+		})
+	})
+
+	Describe("Delete Jira service desk project after its JiraConnection was deleted", func() {
+		var connection *jiraservicedeskv1alpha1.JiraConnection
+		var connectedProjectInput jiraservicedeskv1alpha1.Project
+
+		BeforeEach(func() {
+			connection = &jiraservicedeskv1alpha1.JiraConnection{
+				ObjectMeta: metav1.ObjectMeta{Name: "deleted-connection-" + key, Namespace: ns},
+				Spec: jiraservicedeskv1alpha1.JiraConnectionSpec{
+					SecretRef: jiraservicedeskv1alpha1.ConnectionSecretReference{Name: config.JiraServiceDeskSecretName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, connection)).To(Succeed())
+
+			connectedProjectInput = projectInput
+			connectedProjectInput.Spec.ConnectionRef = &jiraservicedeskv1alpha1.ConnectionReference{
+				Kind: jiraservicedeskv1alpha1.JiraConnectionKind,
+				Name: connection.Name,
+			}
+		})
+
+		Context("With Retain deletion policy", func() {
+			It("should remove the resource without the connection", func() {
+				connectedProjectInput.Spec.DeletionPolicy = jiraservicedeskv1alpha1.DeletionPolicyRetain
+
+				_ = util.CreateProject(connectedProjectInput, ns)
+				project := util.GetProject(connectedProjectInput.Spec.Name, ns)
+				Expect(project.Status.ID).ToNot(Equal(""))
+
+				Expect(k8sClient.Delete(ctx, connection)).To(Succeed())
+				util.DeleteProject(project.Name, ns)
+
+				projectObject := &jiraservicedeskv1alpha1.Project{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: project.Name, Namespace: ns}, projectObject)
+				Expect(err).To(HaveOccurred())
+
+				Expect(r.JiraServiceDeskClient.DeleteProject(ctx, project.Status.ID)).To(Succeed())
+			})
+		})
+
+		Context("With Delete deletion policy", func() {
+			It("should requeue until the connection can be resolved", func() {
+				connectedProjectInput.Spec.DeletionPolicy = jiraservicedeskv1alpha1.DeletionPolicyDelete
+
+				_ = util.CreateProject(connectedProjectInput, ns)
+				project := util.GetProject(connectedProjectInput.Spec.Name, ns)
+				Expect(project.Status.ID).ToNot(Equal(""))
+
+				Expect(k8sClient.Delete(ctx, connection)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, project)).To(Succeed())
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: project.Name, Namespace: ns}}
+				_, err := r.Reconcile(ctx, req)
+				Expect(err).To(HaveOccurred())
+
+				project = util.GetProject(connectedProjectInput.Spec.Name, ns)
+				Expect(project.Finalizers).To(ContainElement(ProjectFinalizer))
+
+				// The project is deleted on JSD once the connection is back
+				connection.ResourceVersion = ""
+				Expect(k8sClient.Create(ctx, connection)).To(Succeed())
+				defer func() {
+					_ = k8sClient.Delete(ctx, connection)
+				}()
+
+				_, err = r.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = r.JiraServiceDeskClient.GetProjectByIdentifier(ctx, project.Status.ID)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Create Jira service desk queue in a project on another Jira site", func() {
+		Context("With a Project custom resource that uses a different connection", func() {
+			It("should not create the queue", func() {
+				connection := &jiraservicedeskv1alpha1.JiraConnection{
+					ObjectMeta: metav1.ObjectMeta{Name: "queue-connection-" + key, Namespace: ns},
+					Spec: jiraservicedeskv1alpha1.JiraConnectionSpec{
+						SecretRef: jiraservicedeskv1alpha1.ConnectionSecretReference{Name: config.JiraServiceDeskSecretName},
+					},
+				}
+				Expect(k8sClient.Create(ctx, connection)).To(Succeed())
+				defer func() {
+					_ = k8sClient.Delete(ctx, connection)
+				}()
+
+				connectedQueueInput := queueInput
+				connectedQueueInput.Spec.ProjectName = mockData.SampleProjectInput.Spec.Name
+				connectedQueueInput.Spec.ProjectKey = ""
+				connectedQueueInput.Spec.ConnectionRef = &jiraservicedeskv1alpha1.ConnectionReference{
+					Kind: jiraservicedeskv1alpha1.JiraConnectionKind,
+					Name: connection.Name,
+				}
+
+				_ = qUtil.CreateQueue(connectedQueueInput, ns)
+				queue := qUtil.GetQueue(connectedQueueInput.Spec.Name, ns)
+
+				Expect(queue.Status.QueueId).To(Equal(""))
+				Expect(queue.Status.Conditions[0].Type).To(Equal("ReconcileError"))
+				Expect(queue.Status.Conditions[0].Message).To(ContainSubstring("different Jira connection"))
+			})
+		})
+	})
+})
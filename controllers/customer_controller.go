@@ -18,11 +18,16 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -33,8 +38,8 @@ import (
 )
 
 const (
-	CustomerFinalizer        string = "jiraservicedesk.stakater.com/customer"
-	CustomerAlreadyExistsErr string = "An account already exists for this email"
+	CustomerFinalizer       string = "jiraservicedesk.stakater.com/customer"
+	OrganizationNotReadyErr string = "Organization %s has not been created on JSD yet"
 )
 
 // CustomerReconciler reconciles a Customer object
@@ -43,10 +48,24 @@ type CustomerReconciler struct {
 	Log                   logr.Logger
 	Scheme                *runtime.Scheme
 	JiraServiceDeskClient jiraservicedeskclient.Client
+	// Uncached reader used to resolve the connections and credentials of other Jira sites
+	APIReader client.Reader
+	// Interval after which customers are compared with JSD again, 0 disables the resync
+	ResyncInterval time.Duration
+	// Revert changes made on JSD instead of only reporting them. Names and emails are only reverted where
+	// customers can be updated, since customer accounts on Jira Cloud are managed by their owners
+	RevertDrift bool
+	// Deletion policy used for customers that do not specify one
+	DefaultDeletionPolicy jiraservicedeskv1alpha1.DeletionPolicy
+	// Records events for the changes made on JSD, set up with the manager if not given
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=customers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=customers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=jiraconnections;clusterjiraconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=organizations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *CustomerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("customer", req.NamespacedName)
@@ -56,7 +75,7 @@ func (r *CustomerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	// Fetch the Customer instance
 	instance := &jiraservicedeskv1alpha1.Customer{}
 
-	err := r.Get(context.TODO(), req.NamespacedName, instance)
+	err := r.Get(ctx, req.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -68,112 +87,279 @@ func (r *CustomerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return reconcilerUtil.RequeueWithError(err)
 	}
 
-	// Validate Custom Resource
-	if ok, err := instance.IsValid(); !ok {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
-	}
-
-	// Resource is marked for deletion
+	// Resource is marked for deletion. Deletion is handled before the connection is resolved,
+	// so that custom resources whose connection is gone can still be deleted
 	if instance.DeletionTimestamp != nil {
 		log.Info("Deletion timestamp found for instance " + req.Name)
 		if finalizerUtil.HasFinalizer(instance, CustomerFinalizer) {
-			return r.handleDelete(req, instance)
+			return r.handleDelete(ctx, req, instance)
 		}
 		// Finalizer doesn't exist so clean up is already done
 		return reconcilerUtil.DoNotRequeue()
 	}
 
+	// Validate Custom Resource
+	if ok, err := instance.IsValid(); !ok {
+		return r.manageError(instance, err, false)
+	}
+
+	// Manage the customer on the Jira site of the referenced connection
+	jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+	r = r.withJiraServiceDeskClient(jiraServiceDeskClient)
+
 	// Add finalizer if it doesn't exist
 	if !finalizerUtil.HasFinalizer(instance, CustomerFinalizer) {
 		log.Info("Adding finalizer for instance " + req.Name)
 
 		finalizerUtil.AddFinalizer(instance, CustomerFinalizer)
 
-		err := r.Client.Update(context.TODO(), instance)
+		err := r.Client.Update(ctx, instance)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 	}
 
+	// Finish moving the customer to a new account before comparing it with JSD, since the old account may already be deleted.
+	// Migrations that failed before creating the new customer start again, in case the email was changed again
+	if instance.IsMigrating() {
+		return r.resync(r.handleMigration(ctx, req, instance))
+	}
+
 	// If CustomerId exists in status, then it's an update request
 	if len(instance.Status.CustomerId) > 0 {
 		// Get the customer from Jira Service Desk
-		existingCustomer, err := r.JiraServiceDeskClient.GetCustomerById(instance.Status.CustomerId)
+		existingCustomer, err := r.JiraServiceDeskClient.GetCustomerById(ctx, instance.Status.CustomerId)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 
-		// Check if the customer needs an update
-		if r.JiraServiceDeskClient.IsCustomerUpdated(instance, existingCustomer) {
+		// Memberships made by the operator that were removed on JSD
+		projects, organizations, err := r.getMemberships(ctx, instance)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+		var membershipDiff []string
+		if len(projects) != len(instance.Status.AssociatedProjects) {
+			membershipDiff = append(membershipDiff, "projects")
+		}
+		if len(organizations) != len(instance.Status.AssociatedOrganizations) {
+			membershipDiff = append(membershipDiff, "organizations")
+		}
 
-			// Check if this is a valid customer update
-			existingCustomerInstance := r.JiraServiceDeskClient.GetCustomerCRFromCustomer(existingCustomer)
-			if ok, err := instance.IsValidCustomerUpdate(existingCustomerInstance); !ok {
-				return reconcilerUtil.ManageError(r.Client, instance, err, false)
-			}
+		// Customer was changed on JSD since the spec was last reconciled
+		diff := r.JiraServiceDeskClient.CustomerDiff(instance, existingCustomer)
+		driftDiff := append(append([]string{}, diff...), membershipDiff...)
+		if isDrift(instance, instance.Status.ObservedGeneration, driftDiff) &&
+			(!r.RevertDrift || (len(diff) > 0 && !r.JiraServiceDeskClient.CanUpdateCustomer())) {
+			log.Info("Drift detected in customer fields: " + strings.Join(driftDiff, ", "))
+			return r.resync(manageDrift(ctx, r.Client, instance, driftDiff))
+		}
+
+		// Memberships that were removed on JSD are added again by the update
+		if len(membershipDiff) > 0 {
+			instance.Status.AssociatedProjects = projects
+			instance.Status.AssociatedOrganizations = organizations
+		}
+
+		// Name or email of the customer was changed in the spec, or drift has to be reverted
+		if len(diff) > 0 {
+			return r.resync(r.handleAccountUpdate(ctx, req, instance, diff))
+		}
 
+		// Check if the customer needs an update
+		if len(membershipDiff) > 0 || r.JiraServiceDeskClient.IsCustomerUpdated(instance, existingCustomer) {
 			// Handle customer update
-			return r.handleUpdate(req, instance)
+			return r.resync(r.handleUpdate(ctx, req, instance))
+		} else if !isInSync(instance, instance.Status.ObservedGeneration, instance.Status.Conditions) {
+			instance.Status.ObservedGeneration = instance.Generation
+			return r.resync(reconcilerUtil.ManageSuccess(r.Client, instance))
 		} else {
 			log.Info("Skipping update. No changes found")
-			return reconcilerUtil.DoNotRequeue()
+			return r.resync(reconcilerUtil.DoNotRequeue())
 		}
 	}
 
-	return r.handleCreate(req, instance)
+	// Bind the custom resource to an existing customer instead of creating a new one
+	if importId := getImportId(instance); len(importId) > 0 {
+		return r.resync(r.handleImport(ctx, req, importId, instance))
+	}
+
+	return r.resync(r.handleCreate(ctx, req, instance))
+}
+
+// withJiraServiceDeskClient returns a copy of the reconciler that uses the given client
+func (r *CustomerReconciler) withJiraServiceDeskClient(jiraServiceDeskClient jiraservicedeskclient.Client) *CustomerReconciler {
+	reconciler := *r
+	reconciler.JiraServiceDeskClient = jiraServiceDeskClient
+	return &reconciler
+}
+
+func (r *CustomerReconciler) resync(result ctrl.Result, err error) (ctrl.Result, error) {
+	return requeueAfterResyncInterval(result, err, r.ResyncInterval)
+}
+
+// manageError records the error as an event on the customer and in its status
+func (r *CustomerReconciler) manageError(instance *jiraservicedeskv1alpha1.Customer, err error, retry bool) (ctrl.Result, error) {
+	return manageErrorWithEvent(r.Client, r.Recorder, instance, err, retry)
 }
 
 func (r *CustomerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("customer-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&jiraservicedeskv1alpha1.Customer{}).
 		Complete(r)
 }
 
-func (r *CustomerReconciler) handleUpdate(req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
+func (r *CustomerReconciler) handleUpdate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
 	log := r.Log.WithValues("customer", req.NamespacedName)
 
 	log.Info("Modifying project associations for JSD Customer: " + instance.Spec.Name)
 
-	for _, specProjectKey := range instance.Spec.Projects {
-		found := false
-		for _, statusProjectKey := range instance.Status.AssociatedProjects {
-			if specProjectKey == statusProjectKey {
-				found = true
-				break
+	err := r.updateProjects(ctx, req, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	log.Info("Modifying organization associations for JSD Customer: " + instance.Spec.Name)
+
+	retry, err := r.updateOrganizations(ctx, req, instance)
+	if err != nil {
+		return r.manageError(instance, err, retry)
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
+
+	return reconcilerUtil.ManageSuccess(r.Client, instance)
+}
+
+// updateProjects adds the customer to the projects of the spec and removes it from the projects that were dropped
+func (r *CustomerReconciler) updateProjects(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) error {
+	log := r.Log.WithValues("customer", req.NamespacedName)
+
+	err := updateMemberships(instance.Spec.Projects, instance.Status.AssociatedProjects,
+		func(projectKey string) error {
+			err := r.JiraServiceDeskClient.AddCustomerToProject(ctx, instance.Status.CustomerId, projectKey)
+			if err != nil {
+				return err
 			}
-		}
-		if !found {
-			err := r.JiraServiceDeskClient.AddCustomerToProject(instance.Status.CustomerId, specProjectKey)
+			log.Info("Successfully added Jira Service Desk Customer into project: " + projectKey)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AddedToProjectReason, "Added customer %s to project %s", instance.Status.CustomerId, projectKey)
+			return nil
+		},
+		func(projectKey string) error {
+			err := r.JiraServiceDeskClient.RemoveCustomerFromProject(ctx, instance.Status.CustomerId, projectKey)
 			if err != nil {
-				return reconcilerUtil.ManageError(r.Client, instance, err, false)
+				return err
 			}
-			log.Info("Successfully added Jira Service Desk Customer into project: " + specProjectKey)
-		}
+			log.Info("Successfully removed Jira Service Desk Customer from project: " + projectKey)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, RemovedFromProjectReason, "Removed customer %s from project %s", instance.Status.CustomerId, projectKey)
+			return nil
+		})
+	if err != nil {
+		return err
 	}
 
-	for _, statusProjectKey := range instance.Status.AssociatedProjects {
-		found := false
-		for _, specProjectKey := range instance.Spec.Projects {
-			if specProjectKey == statusProjectKey {
-				found = true
-				break
+	instance.Status.AssociatedProjects = instance.Spec.Projects
+	return nil
+}
+
+// updateOrganizations adds the customer to the organizations of the spec and removes it from the organizations
+// that were dropped. Organizations that are not created on JSD yet are retried
+func (r *CustomerReconciler) updateOrganizations(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (bool, error) {
+	log := r.Log.WithValues("customer", req.NamespacedName)
+	retry := false
+
+	err := updateMemberships(instance.Spec.Organizations, instance.Status.AssociatedOrganizations,
+		func(organization string) error {
+			organizationId, err := r.getOrganizationId(ctx, instance, organization)
+			if err != nil {
+				// The organization may still be getting created, unless it is managed on another Jira site
+				retry = !isConnectionMismatch(err)
+				return err
 			}
-		}
-		if !found {
-			err := r.JiraServiceDeskClient.RemoveCustomerFromProject(instance.Status.CustomerId, statusProjectKey)
+			err = r.JiraServiceDeskClient.AddCustomerToOrganization(ctx, instance.Status.CustomerId, organizationId)
 			if err != nil {
-				return reconcilerUtil.ManageError(r.Client, instance, err, false)
+				return err
+			}
+			log.Info("Successfully added Jira Service Desk Customer into organization: " + organization)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AddedToOrganizationReason, "Added customer %s to organization %s with id %s", instance.Status.CustomerId, organization, organizationId)
+			return nil
+		},
+		func(organization string) error {
+			organizationId, err := r.getOrganizationId(ctx, instance, organization)
+			if errors.IsNotFound(err) {
+				// Deleting an organization on JSD also removes all of its memberships
+				log.Info("Organization '" + organization + "' no longer exists. So skipping removal")
+				return nil
+			} else if err != nil {
+				return err
 			}
-			log.Info("Successfully removed Jira Service Desk Customer from project: " + statusProjectKey)
+			err = r.JiraServiceDeskClient.RemoveCustomerFromOrganization(ctx, instance.Status.CustomerId, organizationId)
+			if err != nil {
+				return err
+			}
+			log.Info("Successfully removed Jira Service Desk Customer from organization: " + organization)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, RemovedFromOrganizationReason, "Removed customer %s from organization %s with id %s", instance.Status.CustomerId, organization, organizationId)
+			return nil
+		})
+	if err != nil {
+		return retry, err
+	}
+
+	instance.Status.AssociatedOrganizations = instance.Spec.Organizations
+	return false, nil
+}
+
+// getMemberships returns the projects and organizations of the status that the customer is still a member of on JSD.
+// Only memberships made by the operator are checked, since the projects and organizations of a customer can not be
+// listed. Projects and organizations that can not be found are kept, their memberships are handled by the update
+func (r *CustomerReconciler) getMemberships(ctx context.Context, instance *jiraservicedeskv1alpha1.Customer) ([]string, []string, error) {
+	var projects, organizations []string
+
+	for _, projectKey := range instance.Status.AssociatedProjects {
+		customers, err := r.JiraServiceDeskClient.GetCustomersByProjectKey(ctx, projectKey)
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			return nil, nil, err
+		}
+		// Projects that were deleted are left to the update as well
+		if err != nil || containsCustomer(customers, instance.Status.CustomerId) {
+			projects = append(projects, projectKey)
 		}
 	}
 
-	instance.Status.AssociatedProjects = instance.Spec.Projects
+	for _, organization := range instance.Status.AssociatedOrganizations {
+		organizationId, err := r.getOrganizationId(ctx, instance, organization)
+		if err != nil {
+			organizations = append(organizations, organization)
+			continue
+		}
+		customers, err := r.JiraServiceDeskClient.GetCustomersByOrganizationId(ctx, organizationId)
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			return nil, nil, err
+		}
+		if err != nil || containsCustomer(customers, instance.Status.CustomerId) {
+			organizations = append(organizations, organization)
+		}
+	}
 
-	return reconcilerUtil.ManageSuccess(r.Client, instance)
+	return projects, organizations, nil
+}
+
+func containsCustomer(customers []jiraservicedeskclient.Customer, accountId string) bool {
+	for _, customer := range customers {
+		if customer.AccountId == accountId {
+			return true
+		}
+	}
+	return false
 }
 
-func (r *CustomerReconciler) handleCreate(req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
+func (r *CustomerReconciler) handleCreate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
 	log := r.Log.WithValues("customer", req.NamespacedName)
 
 	log.Info("Creating Jira Service Desk Customer: " + instance.Spec.Name)
@@ -182,43 +368,76 @@ func (r *CustomerReconciler) handleCreate(req ctrl.Request, instance *jiraservic
 
 	// If legacy Customer flag is true than create a legacy customer, else create a normal customer
 	if instance.Spec.LegacyCustomer {
-		customerID, err = r.JiraServiceDeskClient.CreateLegacyCustomer(instance.Spec.Email, instance.Spec.Projects[0])
+		customerID, err = r.JiraServiceDeskClient.CreateLegacyCustomer(ctx, instance.Spec.Email, instance.Spec.Projects[0])
 	} else {
 		customer := r.JiraServiceDeskClient.GetCustomerFromCustomerCRForCreateCustomer(instance)
-		customerID, err = r.JiraServiceDeskClient.CreateCustomer(customer)
+		customerID, err = r.JiraServiceDeskClient.CreateCustomer(ctx, customer)
 	}
 
 	// If customer already exists, reconstruct status of the custom resource
-	if err != nil && strings.Contains(err.Error(), CustomerAlreadyExistsErr) {
-		existingCustomerID, err := r.JiraServiceDeskClient.GetCustomerIdByEmail(instance.Spec.Email)
+	if jiraservicedeskclient.IsConflict(err) {
+		existingCustomerID, err := r.JiraServiceDeskClient.GetCustomerIdByEmail(ctx, instance.Spec.Email)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 		customerID = existingCustomerID
-
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AdoptedReason, "Adopted existing customer %s with id %s", instance.Spec.Email, customerID)
 	} else if err != nil {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
+		return r.manageError(instance, err, false)
+	} else {
+		log.Info("Successfully created Jira Service Desk Customer: " + instance.Spec.Name)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, CreatedReason, "Created customer %s with id %s", instance.Spec.Email, customerID)
 	}
 
 	instance.Status.CustomerId = customerID
 
-	log.Info("Successfully created Jira Service Desk Customer: " + instance.Spec.Name)
-
 	log.Info("Adding project associations for JSD Customer: " + instance.Spec.Name)
 
-	for _, projectKey := range instance.Spec.Projects {
-		err := r.JiraServiceDeskClient.AddCustomerToProject(instance.Status.CustomerId, projectKey)
-		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
-		}
-		log.Info("Successfully added Jira Service Desk Customer into project: " + projectKey)
+	err = r.updateProjects(ctx, req, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
 	}
-	instance.Status.AssociatedProjects = instance.Spec.Projects
+
+	log.Info("Adding organization associations for JSD Customer: " + instance.Spec.Name)
+
+	retry, err := r.updateOrganizations(ctx, req, instance)
+	if err != nil {
+		return r.manageError(instance, err, retry)
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
 
 	return reconcilerUtil.ManageSuccess(r.Client, instance)
 }
 
-func (r *CustomerReconciler) handleDelete(req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
+func (r *CustomerReconciler) handleImport(ctx context.Context, req ctrl.Request, importId string, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
+	log := r.Log.WithValues("customer", req.NamespacedName)
+
+	log.Info("Importing Jira Service Desk Customer: " + importId)
+
+	existingCustomer, err := r.JiraServiceDeskClient.GetCustomerById(ctx, importId)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	// Project and organization memberships of the existing customer are not known, so they are
+	// added on the next reconcile. Memberships that are not in the spec are never removed
+	instance.Status.CustomerId = existingCustomer.AccountId
+	instance.Status.ObservedGeneration = instance.Generation
+
+	// Report the differences to the spec instead of overwriting the existing customer
+	diff := r.JiraServiceDeskClient.CustomerDiff(instance, existingCustomer)
+	if len(diff) > 0 {
+		log.Info("Imported customer differs from spec in fields: " + strings.Join(diff, ", "))
+	}
+
+	log.Info("Successfully imported Jira Service Desk Customer: " + instance.Spec.Name)
+	recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AdoptedReason, "Adopted existing customer %s with id %s", existingCustomer.Email, existingCustomer.AccountId)
+
+	return manageImport(ctx, r.Client, instance, diff)
+}
+
+func (r *CustomerReconciler) handleDelete(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
 	log := r.Log.WithValues("customer", req.NamespacedName)
 
 	if instance == nil {
@@ -228,15 +447,56 @@ func (r *CustomerReconciler) handleDelete(req ctrl.Request, instance *jiraservic
 
 	log.Info("Deleting Jira Service Desk Customer: " + instance.Spec.Name)
 
+	deletionPolicy := getDeletionPolicy(instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy)
+
 	// Check if the customer was created
-	if instance.Status.CustomerId != "" {
-		// Delete Customer
-		err := r.JiraServiceDeskClient.DeleteCustomer(instance.Status.CustomerId)
+	if instance.Status.CustomerId == "" {
+		log.Info("Customer '" + instance.Spec.Name + "' do not exists on JSD. So skipping deletion")
+	} else if deletionPolicy == jiraservicedeskv1alpha1.DeletionPolicyRetain {
+		log.Info("Customer '" + instance.Spec.Name + "' has deletion policy " + string(deletionPolicy) + ". So skipping deletion")
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, RetainedReason, "Kept customer %s with id %s on deletion policy %s", instance.Spec.Email, instance.Status.CustomerId, deletionPolicy)
+	} else {
+		// Requeue until the connection can be resolved, the customer would be left behind on JSD otherwise
+		jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, true)
+		}
+		r = r.withJiraServiceDeskClient(jiraServiceDeskClient)
+
+		if deletionPolicy == jiraservicedeskv1alpha1.DeletionPolicyOrphan {
+			log.Info("Removing project and organization associations for JSD Customer: " + instance.Spec.Name)
+
+			for _, organizationName := range instance.Status.AssociatedOrganizations {
+				organizationId, err := r.getOrganizationId(ctx, instance, organizationName)
+				if err != nil {
+					// Organizations that were deleted no longer contain the customer
+					if errors.IsNotFound(err) {
+						continue
+					}
+					return r.manageError(instance, err, false)
+				}
+				err = r.JiraServiceDeskClient.RemoveCustomerFromOrganization(ctx, instance.Status.CustomerId, organizationId)
+				if err != nil {
+					return r.manageError(instance, err, false)
+				}
+			}
+
+			for _, projectKey := range instance.Status.AssociatedProjects {
+				err := r.JiraServiceDeskClient.RemoveCustomerFromProject(ctx, instance.Status.CustomerId, projectKey)
+				if err != nil {
+					return r.manageError(instance, err, false)
+				}
+			}
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, OrphanedReason, "Removed customer %s with id %s from its projects and organizations", instance.Spec.Email, instance.Status.CustomerId)
+		} else {
+			// Delete Customer
+			err := r.JiraServiceDeskClient.DeleteCustomer(ctx, instance.Status.CustomerId)
+			// Objects that were already deleted on JSD need no cleanup
+			if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+				return r.manageError(instance, err, false)
+			}
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, DeletedReason, "Deleted customer %s with id %s", instance.Spec.Email, instance.Status.CustomerId)
 		}
-	} else {
-		log.Info("Customer '" + instance.Spec.Name + "' do not exists on JSD. So skipping deletion")
 	}
 
 	// Delete Finalizer
@@ -245,10 +505,32 @@ func (r *CustomerReconciler) handleDelete(req ctrl.Request, instance *jiraservic
 	log.Info("Finalizer removed for customer: " + instance.Spec.Name)
 
 	// Update instance
-	err := r.Client.Update(context.TODO(), instance)
+	err := r.Client.Update(ctx, instance)
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
+		return r.manageError(instance, err, false)
 	}
 
 	return reconcilerUtil.DoNotRequeue()
 }
+
+// getOrganizationId resolves the JSD organization id of an Organization custom resource in the namespace of the
+// customer, which has to be managed with the same connection as the customer
+func (r *CustomerReconciler) getOrganizationId(ctx context.Context, instance *jiraservicedeskv1alpha1.Customer, name string) (string, error) {
+	organization := &jiraservicedeskv1alpha1.Organization{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, organization)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkSameConnection(instance.Spec.ConnectionRef, organization.Spec.ConnectionRef, "Organization", name)
+	if err != nil {
+		return "", err
+	}
+
+	if len(organization.Status.OrganizationId) == 0 {
+		return "", fmt.Errorf(OrganizationNotReadyErr, name)
+	}
+
+	return organization.Status.OrganizationId, nil
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"os"
 	"strings"
 	"time"
@@ -8,7 +9,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 	mockData "github.com/stakater/jira-service-desk-operator/mock"
@@ -92,6 +95,132 @@ var _ = Describe("Customer Controller", func() {
 		})
 	})
 
+	Describe("Modifying customer organization memberships", func() {
+		Context("With a valid Organization", func() {
+			It("Should add the customer to the organization and remove it again", func() {
+				organizationInput := mockData.SampleOrganization
+				organizationInput.Spec.Name += cUtil.RandSeqString(3)
+				organizationInput.Spec.Projects = []string{strings.ToUpper(customerKey)}
+
+				_ = oUtil.CreateOrganization(organizationInput, ns)
+				defer oUtil.TryDeleteOrganization(organizationInput.Spec.Name, ns)
+
+				organization := oUtil.GetOrganization(organizationInput.Spec.Name, ns)
+				Expect(organization.Status.OrganizationId).ToNot(Equal(""))
+
+				_ = cUtil.CreateCustomer(customerInput, ns)
+				time.Sleep(5 * time.Second)
+
+				customer := cUtil.GetCustomer(customerInput.Spec.Name, ns)
+				Expect(customer.Status.CustomerId).ToNot(Equal(""))
+
+				customer.Spec.Organizations = []string{organization.Name}
+
+				_ = cUtil.UpdateCustomer(customer, ns)
+				updatedCustomer := cUtil.GetCustomer(customer.Spec.Name, ns)
+
+				Expect(updatedCustomer.Status.AssociatedOrganizations).To(Equal(customer.Spec.Organizations))
+
+				updatedCustomer.Spec.Organizations = nil
+
+				_ = cUtil.UpdateCustomer(updatedCustomer, ns)
+				updatedCustomer = cUtil.GetCustomer(customer.Spec.Name, ns)
+
+				Expect(updatedCustomer.Status.AssociatedOrganizations).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Changing the email of Jira Service Desk customer", func() {
+		Context("On Jira Cloud", func() {
+			It("should move the customer to a new account with the new email", func() {
+				_ = cUtil.CreateCustomer(customerInput, ns)
+				time.Sleep(5 * time.Second)
+
+				customer := cUtil.GetCustomer(customerInput.Spec.Name, ns)
+				Expect(customer.Status.CustomerId).ToNot(Equal(""))
+				oldCustomerId := customer.Status.CustomerId
+
+				customer.Spec.Email = "moved" + customerInput.Spec.Email
+
+				_ = cUtil.UpdateCustomer(customer, ns)
+				updatedCustomer := cUtil.GetCustomer(customer.Spec.Name, ns)
+
+				Expect(updatedCustomer.Status.CustomerId).ToNot(Equal(oldCustomerId))
+				Expect(updatedCustomer.Status.Migration.Phase).To(Equal(v1alpha1.CustomerMigrationCompleted))
+				Expect(updatedCustomer.Status.Migration.FromCustomerId).To(Equal(oldCustomerId))
+
+				existingCustomer, err := cr.JiraServiceDeskClient.GetCustomerById(ctx, updatedCustomer.Status.CustomerId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(existingCustomer.Email).To(Equal(customer.Spec.Email))
+			})
+		})
+	})
+
+	Describe("Changing the name of Jira Service Desk customer", func() {
+		Context("On Jira Cloud", func() {
+			It("should keep the account and still update the projects of the customer", func() {
+				_ = cUtil.CreateCustomer(customerInput, ns)
+				time.Sleep(5 * time.Second)
+
+				customer := cUtil.GetCustomer(customerInput.Spec.Name, ns)
+				Expect(customer.Status.CustomerId).ToNot(Equal(""))
+
+				customer.Spec.Name = "Renamed " + customerInput.Spec.Name
+				customer.Spec.Projects = []string{strings.ToUpper(customerKey)}
+
+				// The name of the custom resource stays the same
+				Expect(k8sClient.Update(ctx, customer)).To(Succeed())
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: customer.Name, Namespace: ns}}
+				_, err := cr.Reconcile(context.Background(), req)
+				Expect(err).NotTo(HaveOccurred())
+
+				updatedCustomer := cUtil.GetCustomer(customer.Name, ns)
+
+				Expect(updatedCustomer.Status.CustomerId).To(Equal(customer.Status.CustomerId))
+				Expect(updatedCustomer.Status.AssociatedProjects).To(Equal(customer.Spec.Projects))
+			})
+		})
+	})
+
+	Describe("Removing Jira Service Desk customer from a project on JSD", func() {
+		Context("With drift detection", func() {
+			It("should report the removed membership and add it again when drift is reverted", func() {
+				driftInput := customerInput
+				driftInput.Spec.Projects = []string{strings.ToUpper(customerKey)}
+
+				_ = cUtil.CreateCustomer(driftInput, ns)
+				customer := cUtil.GetCustomer(driftInput.Spec.Name, ns)
+				Expect(customer.Status.CustomerId).ToNot(Equal(""))
+
+				err := cr.JiraServiceDeskClient.RemoveCustomerFromProject(ctx, customer.Status.CustomerId, customer.Spec.Projects[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: customer.Name, Namespace: ns}}
+				_, err = cr.Reconcile(context.Background(), req)
+				Expect(err).NotTo(HaveOccurred())
+
+				driftedCustomer := cUtil.GetCustomer(customer.Name, ns)
+				drifted := meta.FindStatusCondition(driftedCustomer.Status.Conditions, DriftedCondition)
+				Expect(drifted).ToNot(BeNil())
+				Expect(drifted.Message).To(ContainSubstring("projects"))
+
+				cr.RevertDrift = true
+				defer func() { cr.RevertDrift = false }()
+
+				_, err = cr.Reconcile(context.Background(), req)
+				Expect(err).NotTo(HaveOccurred())
+
+				customers, err := cr.JiraServiceDeskClient.GetCustomersByProjectKey(ctx, customer.Spec.Projects[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(containsCustomer(customers, customer.Status.CustomerId)).To(BeTrue())
+
+				revertedCustomer := cUtil.GetCustomer(customer.Name, ns)
+				Expect(meta.IsStatusConditionTrue(revertedCustomer.Status.Conditions, DriftedCondition)).To(BeFalse())
+			})
+		})
+	})
+
 	Describe("Delete Jira Service Desk customer", func() {
 		Context("With valid Customer AccountId", func() {
 			It("should delete the customer", func() {
@@ -0,0 +1,179 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+const (
+	CustomerNameNotUpdatableMsg string = "Name of customer %s can not be changed on this Jira site without changing its email"
+)
+
+// handleAccountUpdate changes the name and email of the customer on JSD. Jira sites that can not update
+// accounts get a new customer with the new email instead, which replaces the old one
+func (r *CustomerReconciler) handleAccountUpdate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer, diff []string) (ctrl.Result, error) {
+	log := r.Log.WithValues("customer", req.NamespacedName)
+
+	if r.JiraServiceDeskClient.CanUpdateCustomer() {
+		log.Info("Updating Jira Service Desk Customer fields: " + strings.Join(diff, ", "))
+
+		customer := r.JiraServiceDeskClient.GetCustomerFromCustomerCRForCreateCustomer(instance)
+		err := r.JiraServiceDeskClient.UpdateCustomer(ctx, instance.Status.CustomerId, customer)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, UpdatedReason, "Updated fields %s of customer %s", strings.Join(diff, ", "), instance.Status.CustomerId)
+
+		return r.handleUpdate(ctx, req, instance)
+	}
+
+	// Customers are identified by their email, so a new customer can not be created for a new name only. The name
+	// may also have been changed by the owner of the account, so it is only reported and the memberships are still updated
+	if !hasField(diff, "email") {
+		log.Info(fmt.Sprintf(CustomerNameNotUpdatableMsg, instance.Status.CustomerId))
+		recordEvent(r.Recorder, instance, corev1.EventTypeWarning, NameNotUpdatedReason, CustomerNameNotUpdatableMsg, instance.Status.CustomerId)
+		return r.handleUpdate(ctx, req, instance)
+	}
+
+	log.Info("Moving Jira Service Desk Customer " + instance.Status.CustomerId + " to a new account for email " + instance.Spec.Email)
+	instance.Status.Migration = &jiraservicedeskv1alpha1.CustomerMigration{
+		FromCustomerId: instance.Status.CustomerId,
+		Email:          instance.Spec.Email,
+	}
+
+	return r.handleMigration(ctx, req, instance)
+}
+
+// handleMigration moves the customer to a new account, continuing after the last step recorded in the status.
+// Every step is recorded before the next one starts, so that failed migrations are resumed on the next reconcile
+func (r *CustomerReconciler) handleMigration(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Customer) (ctrl.Result, error) {
+	log := r.Log.WithValues("customer", req.NamespacedName)
+	migration := instance.Status.Migration
+
+	if len(migration.Phase) == 0 {
+		var customerId string
+		var err error
+
+		// Create the new customer the same way as the old one
+		if instance.Spec.LegacyCustomer {
+			customerId, err = r.JiraServiceDeskClient.CreateLegacyCustomer(ctx, migration.Email, instance.Spec.Projects[0])
+		} else {
+			customerId, err = r.JiraServiceDeskClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: migration.Email, DisplayName: instance.Spec.Name})
+		}
+
+		// The customer with the new email may already exist, e.g. if the status could not be updated after creating it
+		if jiraservicedeskclient.IsConflict(err) {
+			customerId, err = r.JiraServiceDeskClient.GetCustomerIdByEmail(ctx, migration.Email)
+		}
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+
+		migration.ToCustomerId = customerId
+		if err := r.completeMigrationStep(ctx, instance, jiraservicedeskv1alpha1.CustomerMigrationCreated); err != nil {
+			return r.manageError(instance, err, false)
+		}
+		log.Info("Successfully created Jira Service Desk Customer: " + customerId)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, CreatedReason, "Created customer %s with id %s to replace customer %s", migration.Email, customerId, migration.FromCustomerId)
+	}
+
+	if migration.Phase == jiraservicedeskv1alpha1.CustomerMigrationCreated {
+		for _, projectKey := range instance.Status.AssociatedProjects {
+			err := r.JiraServiceDeskClient.AddCustomerToProject(ctx, migration.ToCustomerId, projectKey)
+			if err != nil {
+				return r.manageError(instance, err, false)
+			}
+			err = r.JiraServiceDeskClient.RemoveCustomerFromProject(ctx, migration.FromCustomerId, projectKey)
+			if err != nil {
+				return r.manageError(instance, err, false)
+			}
+			log.Info("Successfully moved Jira Service Desk Customer in project: " + projectKey)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AddedToProjectReason, "Added customer %s to project %s in place of customer %s", migration.ToCustomerId, projectKey, migration.FromCustomerId)
+		}
+
+		if err := r.completeMigrationStep(ctx, instance, jiraservicedeskv1alpha1.CustomerMigrationProjectsMoved); err != nil {
+			return r.manageError(instance, err, false)
+		}
+	}
+
+	if migration.Phase == jiraservicedeskv1alpha1.CustomerMigrationProjectsMoved {
+		for _, organization := range instance.Status.AssociatedOrganizations {
+			organizationId, err := r.getOrganizationId(ctx, instance, organization)
+			if errors.IsNotFound(err) {
+				// Deleting an organization on JSD also removes all of its memberships
+				log.Info("Organization '" + organization + "' no longer exists. So skipping move")
+				continue
+			} else if err != nil {
+				return r.manageError(instance, err, !isConnectionMismatch(err))
+			}
+			err = r.JiraServiceDeskClient.AddCustomerToOrganization(ctx, migration.ToCustomerId, organizationId)
+			if err != nil {
+				return r.manageError(instance, err, false)
+			}
+			err = r.JiraServiceDeskClient.RemoveCustomerFromOrganization(ctx, migration.FromCustomerId, organizationId)
+			if err != nil {
+				return r.manageError(instance, err, false)
+			}
+			log.Info("Successfully moved Jira Service Desk Customer in organization: " + organization)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AddedToOrganizationReason, "Added customer %s to organization %s with id %s in place of customer %s", migration.ToCustomerId, organization, organizationId, migration.FromCustomerId)
+		}
+
+		if err := r.completeMigrationStep(ctx, instance, jiraservicedeskv1alpha1.CustomerMigrationOrganizationsMoved); err != nil {
+			return r.manageError(instance, err, false)
+		}
+	}
+
+	if migration.Phase == jiraservicedeskv1alpha1.CustomerMigrationOrganizationsMoved {
+		// The old customer no longer has memberships, so only its account is left to clean up
+		deletionPolicy := getDeletionPolicy(instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy)
+		if deletionPolicy == jiraservicedeskv1alpha1.DeletionPolicyDelete {
+			err := r.JiraServiceDeskClient.DeleteCustomer(ctx, migration.FromCustomerId)
+			if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+				return r.manageError(instance, err, false)
+			}
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, DeletedReason, "Deleted customer %s that was replaced by customer %s", migration.FromCustomerId, migration.ToCustomerId)
+		} else {
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, RetainedReason, "Kept customer %s that was replaced by customer %s on deletion policy %s", migration.FromCustomerId, migration.ToCustomerId, deletionPolicy)
+		}
+
+		instance.Status.CustomerId = migration.ToCustomerId
+		if err := r.completeMigrationStep(ctx, instance, jiraservicedeskv1alpha1.CustomerMigrationCompleted); err != nil {
+			return r.manageError(instance, err, false)
+		}
+		log.Info("Successfully moved Jira Service Desk Customer " + migration.FromCustomerId + " to " + migration.ToCustomerId)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, MigratedReason, "Moved customer %s to customer %s with email %s", migration.FromCustomerId, migration.ToCustomerId, migration.Email)
+	}
+
+	// Apply the other changes of the spec to the new customer
+	return r.handleUpdate(ctx, req, instance)
+}
+
+// completeMigrationStep records the last completed step of the migration in the status
+func (r *CustomerReconciler) completeMigrationStep(ctx context.Context, instance *jiraservicedeskv1alpha1.Customer, phase jiraservicedeskv1alpha1.CustomerMigrationPhase) error {
+	instance.Status.Migration.Phase = phase
+	return r.Status().Update(ctx, instance)
+}
@@ -0,0 +1,86 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+const (
+	DriftedCondition    string = "Drifted"
+	DriftDetectedReason string = "DriftDetected"
+	DriftDetectedMsg    string = "Fields changed on Jira Service Desk: "
+)
+
+// isDrift reports whether the differences found on JSD were made outside of the operator,
+// i.e. the spec has not changed since it was last reconciled successfully
+func isDrift(obj metav1.Object, observedGeneration int64, diff []string) bool {
+	return len(diff) > 0 && observedGeneration > 0 && observedGeneration == obj.GetGeneration()
+}
+
+// isInSync reports whether the status already records the current spec as reconciled without drift. Failed
+// authentication is cleared as well, once the credentials are accepted again
+func isInSync(obj metav1.Object, observedGeneration int64, conditions []metav1.Condition) bool {
+	return observedGeneration == obj.GetGeneration() && !meta.IsStatusConditionTrue(conditions, DriftedCondition) &&
+		!meta.IsStatusConditionTrue(conditions, AuthenticationFailedCondition)
+}
+
+// manageDrift adds the Drifted condition, listing the drifted fields, to the status of the resource
+func manageDrift(ctx context.Context, c client.Client, obj reconcilerUtil.Resource, diff []string) (ctrl.Result, error) {
+	if reconcileStatusAware, updateStatus := (obj).(reconcilerUtil.ConditionsStatusAware); updateStatus {
+		conditions := reconcileStatusAware.GetReconcileStatus()
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               DriftedCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             DriftDetectedReason,
+			Message:            DriftDetectedMsg + strings.Join(diff, ", "),
+			ObservedGeneration: obj.GetGeneration(),
+		})
+		reconcileStatusAware.SetReconcileStatus(conditions)
+
+		err := c.Status().Update(ctx, obj)
+		if err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// hasField reports whether a field is among the fields that differ from the spec
+func hasField(diff []string, field string) bool {
+	return containsKey(diff, field)
+}
+
+// requeueAfterResyncInterval requeues a reconciled resource after the resync interval so that
+// changes made on JSD are noticed. A zero interval disables the periodic resync
+func requeueAfterResyncInterval(result ctrl.Result, err error, resyncInterval time.Duration) (ctrl.Result, error) {
+	if err != nil || resyncInterval <= 0 || result.Requeue || result.RequeueAfter > 0 {
+		return result, err
+	}
+
+	return reconcilerUtil.RequeueAfter(resyncInterval)
+}
@@ -0,0 +1,109 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+// Reasons of the events recorded on custom resources for changes made on JSD
+const (
+	CreatedReason                 string = "Created"
+	UpdatedReason                 string = "Updated"
+	AdoptedReason                 string = "Adopted"
+	DeletedReason                 string = "Deleted"
+	RetainedReason                string = "Retained"
+	OrphanedReason                string = "Orphaned"
+	PermissionsUpdatedReason      string = "PermissionsUpdated"
+	AddedToProjectReason          string = "AddedToProject"
+	RemovedFromProjectReason      string = "RemovedFromProject"
+	AddedToOrganizationReason     string = "AddedToOrganization"
+	RemovedFromOrganizationReason string = "RemovedFromOrganization"
+	MigratedReason                string = "Migrated"
+	NameNotUpdatedReason          string = "NameNotUpdated"
+	ReconcileFailedReason         string = "ReconcileFailed"
+	AuthenticationFailedReason    string = "AuthenticationFailed"
+)
+
+// Condition reported instead of ReconcileError when Jira rejects the credentials of the connection,
+// with the Unauthorized or Forbidden status of the response as reason
+const (
+	AuthenticationFailedCondition string = "AuthenticationFailed"
+	UnauthorizedReason            string = "Unauthorized"
+	ForbiddenReason               string = "Forbidden"
+)
+
+// recordEvent records an event on a custom resource. Reconcilers that were not set up with a manager
+// have no event recorder, in which case nothing is recorded
+func recordEvent(recorder record.EventRecorder, obj runtime.Object, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// manageErrorWithEvent records the error as a warning event on the custom resource before adding it to the status.
+// Requests that were rate limited or failed on the Jira site are retried, and rejected credentials are reported
+// with the AuthenticationFailed condition
+func manageErrorWithEvent(c client.Client, recorder record.EventRecorder, obj reconcilerUtil.Resource, err error, retry bool) (ctrl.Result, error) {
+	if jiraservicedeskclient.IsAuth(err) {
+		recordEvent(recorder, obj, corev1.EventTypeWarning, AuthenticationFailedReason, "%s", err.Error())
+		return manageAuthError(c, obj, err)
+	}
+
+	recordEvent(recorder, obj, corev1.EventTypeWarning, ReconcileFailedReason, "%s", err.Error())
+	if jiraservicedeskclient.IsRateLimited(err) || jiraservicedeskclient.IsServerError(err) {
+		retry = true
+	}
+	return reconcilerUtil.ManageError(c, obj, err, retry)
+}
+
+// manageAuthError replaces the conditions of the resource with the AuthenticationFailed condition. The resource is
+// not requeued, since retrying does not help until the credentials are fixed
+func manageAuthError(c client.Client, obj reconcilerUtil.Resource, err error) (ctrl.Result, error) {
+	if reconcileStatusAware, updateStatus := (obj).(reconcilerUtil.ConditionsStatusAware); updateStatus {
+		reason := UnauthorizedReason
+		if apiErr, _ := jiraservicedeskclient.AsAPIError(err); apiErr.StatusCode == http.StatusForbidden {
+			reason = ForbiddenReason
+		}
+		reconcileStatusAware.SetReconcileStatus([]metav1.Condition{{
+			Type:               AuthenticationFailedCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			Message:            err.Error(),
+			LastTransitionTime: metav1.Now(),
+		}})
+
+		updateErr := c.Status().Update(context.Background(), obj)
+		if updateErr != nil {
+			return reconcilerUtil.RequeueWithError(updateErr)
+		}
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+var _ = Describe("Events", func() {
+
+	Describe("Record failures as events", func() {
+		Context("With an event recorder", func() {
+			It("should record a warning event and update the status", func() {
+				project := &jiraservicedeskv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default"}}
+				c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(project).Build()
+				recorder := record.NewFakeRecorder(1)
+
+				_, err := manageErrorWithEvent(c, recorder, project, fmt.Errorf("Project key is 100%% taken"), false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(recorder.Events).To(Receive(Equal("Warning ReconcileFailed Project key is 100% taken")))
+				Expect(project.Status.Conditions).ToNot(BeEmpty())
+			})
+		})
+
+		Context("Without an event recorder", func() {
+			It("should only update the status", func() {
+				project := &jiraservicedeskv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default"}}
+				c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(project).Build()
+
+				_, err := manageErrorWithEvent(c, nil, project, fmt.Errorf("Project key is taken"), false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(project.Status.Conditions).ToNot(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Handle failed requests to the Jira site", func() {
+		var recorder *record.FakeRecorder
+
+		// reconcileProject creates a project on a Jira site that responds to every request with the given status
+		reconcileProject := func(statusCode int) (*jiraservicedeskv1alpha1.Project, ctrl.Result, error) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(statusCode)
+			}))
+			defer server.Close()
+
+			project := &jiraservicedeskv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default"},
+				Spec:       mockData.CreateProjectInput.Spec,
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(project).Build()
+			recorder = record.NewFakeRecorder(1)
+
+			reconciler := &ProjectReconciler{
+				Client: c,
+				Log:    ctrl.Log.WithName("controllers").WithName("Project"),
+				Scheme: scheme.Scheme,
+				JiraServiceDeskClient: jiraservicedeskclient.NewClient("token", server.URL, "sample@test.com",
+					jiraservicedeskclient.WithRetryPolicy(jiraservicedeskclient.RetryPolicy{})),
+				APIReader: c,
+				Recorder:  recorder,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: project.Name, Namespace: project.Namespace}}
+			result, err := reconciler.Reconcile(ctx, req)
+
+			Expect(c.Get(ctx, req.NamespacedName, project)).To(Succeed())
+			return project, result, err
+		}
+
+		Context("When the requests are rate limited", func() {
+			It("should requeue the project", func() {
+				project, _, err := reconcileProject(http.StatusTooManyRequests)
+				Expect(jiraservicedeskclient.IsRateLimited(err)).To(BeTrue())
+
+				Expect(meta.IsStatusConditionTrue(project.Status.Conditions, "ReconcileError")).To(BeTrue())
+				Expect(recorder.Events).To(Receive(HavePrefix("Warning ReconcileFailed")))
+			})
+		})
+
+		Context("When the Jira site is unavailable", func() {
+			It("should requeue the project", func() {
+				project, _, err := reconcileProject(http.StatusServiceUnavailable)
+				Expect(jiraservicedeskclient.IsServerError(err)).To(BeTrue())
+
+				Expect(meta.IsStatusConditionTrue(project.Status.Conditions, "ReconcileError")).To(BeTrue())
+			})
+		})
+
+		Context("When the credentials are rejected", func() {
+			It("should report the failed authentication without requeueing", func() {
+				project, result, err := reconcileProject(http.StatusUnauthorized)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				condition := meta.FindStatusCondition(project.Status.Conditions, AuthenticationFailedCondition)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Reason).To(Equal(UnauthorizedReason))
+				Expect(meta.FindStatusCondition(project.Status.Conditions, "ReconcileError")).To(BeNil())
+				Expect(recorder.Events).To(Receive(HavePrefix("Warning AuthenticationFailed")))
+			})
+
+			It("should report missing permissions as forbidden", func() {
+				project, _, err := reconcileProject(http.StatusForbidden)
+				Expect(err).NotTo(HaveOccurred())
+
+				condition := meta.FindStatusCondition(project.Status.Conditions, AuthenticationFailedCondition)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Reason).To(Equal(ForbiddenReason))
+				Expect(condition.Message).To(ContainSubstring("403"))
+			})
+		})
+	})
+})
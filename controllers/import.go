@@ -0,0 +1,43 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+// getImportId returns the id of the existing object on JSD that the custom resource should be bound to, if any
+func getImportId(obj metav1.Object) string {
+	return obj.GetAnnotations()[jiraservicedeskv1alpha1.ImportAnnotation]
+}
+
+// manageImport records a custom resource that was bound to an existing object on JSD as reconciled.
+// Fields in which the object differs from the spec are reported in the Drifted condition instead of being updated
+func manageImport(ctx context.Context, c client.Client, obj reconcilerUtil.Resource, diff []string) (ctrl.Result, error) {
+	if len(diff) > 0 {
+		return manageDrift(ctx, c, obj, diff)
+	}
+
+	return reconcilerUtil.ManageSuccess(c, obj)
+}
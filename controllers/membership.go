@@ -0,0 +1,49 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+// updateMemberships adds the keys that are desired but not current and removes the keys that are
+// current but no longer desired. The update stops at the first membership that can not be changed
+func updateMemberships(desired []string, current []string, add func(key string) error, remove func(key string) error) error {
+	for _, key := range desired {
+		if !containsKey(current, key) {
+			if err := add(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range current {
+		if !containsKey(desired, key) {
+			if err := remove(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// containsKey reports whether a key is in the list of keys
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+// Time after which listing the resources for a scrape is given up
+const collectTimeout = 10 * time.Second
+
+var (
+	managedResourcesDesc = prometheus.NewDesc("jira_service_desk_managed_resources",
+		"Projects and customers managed by the operator", []string{"kind"}, nil)
+
+	managedResourceConditionsDesc = prometheus.NewDesc("jira_service_desk_managed_resource_conditions",
+		"Projects and customers managed by the operator by type and status of their conditions", []string{"kind", "condition", "status"}, nil)
+)
+
+// ResourceCollector exports gauges for the projects and customers managed by the operator. The resources are
+// counted from the cache of the manager whenever metrics are scraped, so the gauges can not go stale
+type ResourceCollector struct {
+	Client client.Reader
+	Log    logr.Logger
+}
+
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+	ch <- managedResourceConditionsDesc
+}
+
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	projects := &jiraservicedeskv1alpha1.ProjectList{}
+	if err := c.Client.List(ctx, projects); err != nil {
+		// Metrics are scraped before the cache has started, so failures are not errors
+		c.Log.V(1).Info("Unable to list projects for metrics", "error", err.Error())
+	} else {
+		var conditions [][]metav1.Condition
+		for _, project := range projects.Items {
+			conditions = append(conditions, project.Status.Conditions)
+		}
+		collectResources(ch, "Project", conditions)
+	}
+
+	customers := &jiraservicedeskv1alpha1.CustomerList{}
+	if err := c.Client.List(ctx, customers); err != nil {
+		c.Log.V(1).Info("Unable to list customers for metrics", "error", err.Error())
+	} else {
+		var conditions [][]metav1.Condition
+		for _, customer := range customers.Items {
+			conditions = append(conditions, customer.Status.Conditions)
+		}
+		collectResources(ch, "Customer", conditions)
+	}
+}
+
+// collectResources exports the number of resources of a kind, and the number of resources by condition
+func collectResources(ch chan<- prometheus.Metric, kind string, resourceConditions [][]metav1.Condition) {
+	ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(len(resourceConditions)), kind)
+
+	type conditionKey struct {
+		condition string
+		status    metav1.ConditionStatus
+	}
+	counts := map[conditionKey]int{}
+	for _, conditions := range resourceConditions {
+		for _, condition := range conditions {
+			counts[conditionKey{condition.Type, condition.Status}]++
+		}
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(managedResourceConditionsDesc, prometheus.GaugeValue, float64(count),
+			kind, key.condition, string(key.status))
+	}
+}
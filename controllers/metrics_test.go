@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+var _ = Describe("Resource metrics", func() {
+
+	Describe("Collect managed projects and customers", func() {
+		Context("With projects in different conditions", func() {
+			It("should count the projects and customers by condition", func() {
+				newProject := func(name string, status metav1.ConditionStatus) *jiraservicedeskv1alpha1.Project {
+					project := &jiraservicedeskv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+					project.Status.Conditions = []metav1.Condition{{Type: "ReconcileSuccess", Status: status}}
+					return project
+				}
+				reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+					newProject("synced", metav1.ConditionTrue),
+					newProject("failed", metav1.ConditionFalse),
+					newProject("another-synced", metav1.ConditionTrue),
+				).Build()
+
+				registry := prometheus.NewPedanticRegistry()
+				Expect(registry.Register(&ResourceCollector{Client: reader, Log: log})).To(Succeed())
+
+				count, err := testutil.GatherAndCount(registry, "jira_service_desk_managed_resources", "jira_service_desk_managed_resource_conditions")
+				Expect(err).NotTo(HaveOccurred())
+				// One gauge per kind, and one per condition status of projects
+				Expect(count).To(Equal(4))
+
+				metricFamilies, err := registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				values := map[string]float64{}
+				for _, metricFamily := range metricFamilies {
+					for _, metric := range metricFamily.GetMetric() {
+						key := metricFamily.GetName()
+						for _, label := range metric.GetLabel() {
+							key += "," + label.GetValue()
+						}
+						values[key] = metric.GetGauge().GetValue()
+					}
+				}
+				Expect(values).To(Equal(map[string]float64{
+					"jira_service_desk_managed_resources,Project":                                  3,
+					"jira_service_desk_managed_resources,Customer":                                 0,
+					"jira_service_desk_managed_resource_conditions,ReconcileSuccess,Project,True":  2,
+					"jira_service_desk_managed_resource_conditions,ReconcileSuccess,Project,False": 1,
+				}))
+			})
+		})
+	})
+})
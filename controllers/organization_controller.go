@@ -0,0 +1,310 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+const (
+	OrganizationFinalizer string = "jiraservicedesk.stakater.com/organization"
+)
+
+// OrganizationReconciler reconciles a Organization object
+type OrganizationReconciler struct {
+	client.Client
+	Log                   logr.Logger
+	Scheme                *runtime.Scheme
+	JiraServiceDeskClient jiraservicedeskclient.Client
+	// Uncached reader used to resolve the connections and credentials of other Jira sites
+	APIReader client.Reader
+	// Interval after which organizations are compared with JSD again, 0 disables the resync
+	ResyncInterval time.Duration
+	// Revert changes made to organizations on JSD instead of only reporting them
+	RevertDrift bool
+	// Records events for the changes made on JSD, set up with the manager if not given
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=organizations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=organizations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=jiraconnections;clusterjiraconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *OrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("organization", req.NamespacedName)
+
+	log.Info("Reconciling Organization")
+
+	// Fetch the Organization instance
+	instance := &jiraservicedeskv1alpha1.Organization{}
+
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Error reading the object - requeue the request.
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Resource is marked for deletion. Deletion is handled before the connection is resolved,
+	// so that custom resources whose connection is gone can still be deleted
+	if instance.DeletionTimestamp != nil {
+		log.Info("Deletion timestamp found for instance " + req.Name)
+		if finalizerUtil.HasFinalizer(instance, OrganizationFinalizer) {
+			return r.handleDelete(ctx, req, instance)
+		}
+		// Finalizer doesn't exist so clean up is already done
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Validate Custom Resource
+	if ok, err := instance.IsValid(); !ok {
+		return r.manageError(instance, err, false)
+	}
+
+	// Manage the organization on the Jira site of the referenced connection
+	jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+	r = r.withJiraServiceDeskClient(jiraServiceDeskClient)
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(instance, OrganizationFinalizer) {
+		log.Info("Adding finalizer for instance " + req.Name)
+
+		finalizerUtil.AddFinalizer(instance, OrganizationFinalizer)
+
+		err := r.Client.Update(ctx, instance)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+	}
+
+	// If OrganizationId exists in status, then it's an update request
+	if len(instance.Status.OrganizationId) > 0 {
+		// Get the organization from Jira Service Desk
+		existingOrganization, err := r.JiraServiceDeskClient.GetOrganizationById(ctx, instance.Status.OrganizationId)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+
+		// Compare retrieved organization with current spec
+		diff := r.JiraServiceDeskClient.OrganizationDiff(instance, existingOrganization)
+
+		// Organization was changed on JSD since the spec was last reconciled
+		if isDrift(instance, instance.Status.ObservedGeneration, diff) && !r.RevertDrift {
+			log.Info("Drift detected in organization fields: " + strings.Join(diff, ", "))
+			return r.resync(manageDrift(ctx, r.Client, instance, diff))
+		}
+
+		if len(diff) > 0 || !reflect.DeepEqual(instance.Spec.Projects, instance.Status.AssociatedProjects) {
+			// Update if there are changes in the declared spec or drift has to be reverted
+			return r.resync(r.handleUpdate(ctx, req, instance, diff))
+		} else if !isInSync(instance, instance.Status.ObservedGeneration, instance.Status.Conditions) {
+			instance.Status.ObservedGeneration = instance.Generation
+			return r.resync(reconcilerUtil.ManageSuccess(r.Client, instance))
+		} else {
+			log.Info("Skipping update. No changes found")
+			return r.resync(reconcilerUtil.DoNotRequeue())
+		}
+	}
+
+	return r.resync(r.handleCreate(ctx, req, instance))
+}
+
+// withJiraServiceDeskClient returns a copy of the reconciler that uses the given client
+func (r *OrganizationReconciler) withJiraServiceDeskClient(jiraServiceDeskClient jiraservicedeskclient.Client) *OrganizationReconciler {
+	reconciler := *r
+	reconciler.JiraServiceDeskClient = jiraServiceDeskClient
+	return &reconciler
+}
+
+func (r *OrganizationReconciler) resync(result ctrl.Result, err error) (ctrl.Result, error) {
+	return requeueAfterResyncInterval(result, err, r.ResyncInterval)
+}
+
+// manageError records the error as an event on the organization and in its status
+func (r *OrganizationReconciler) manageError(instance *jiraservicedeskv1alpha1.Organization, err error, retry bool) (ctrl.Result, error) {
+	return manageErrorWithEvent(r.Client, r.Recorder, instance, err, retry)
+}
+
+func (r *OrganizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("organization-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&jiraservicedeskv1alpha1.Organization{}).
+		Complete(r)
+}
+
+func (r *OrganizationReconciler) handleCreate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Organization) (ctrl.Result, error) {
+	log := r.Log.WithValues("organization", req.NamespacedName)
+
+	log.Info("Creating Jira Service Desk Organization: " + instance.Spec.Name)
+
+	organization := r.JiraServiceDeskClient.GetOrganizationFromOrganizationCR(instance)
+	organizationId, err := r.JiraServiceDeskClient.CreateOrganization(ctx, organization)
+
+	// If organization already exists, reconstruct status of the custom resource
+	if jiraservicedeskclient.IsConflict(err) {
+		existingOrganizationId, err := r.JiraServiceDeskClient.GetOrganizationIdByName(ctx, instance.Spec.Name)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+		log.Info("Successfully reconstructed status for Jira Service Desk Organization " + instance.Spec.Name)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AdoptedReason, "Adopted existing organization %s with id %s", instance.Spec.Name, existingOrganizationId)
+
+		organizationId = existingOrganizationId
+	} else if err != nil {
+		return r.manageError(instance, err, false)
+	} else {
+		log.Info("Successfully created Jira Service Desk Organization: " + instance.Spec.Name)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, CreatedReason, "Created organization %s with id %s", instance.Spec.Name, organizationId)
+	}
+
+	instance.Status.OrganizationId = organizationId
+
+	log.Info("Adding project associations for JSD Organization: " + instance.Spec.Name)
+
+	err = r.updateProjects(ctx, req, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
+	return reconcilerUtil.ManageSuccess(r.Client, instance)
+}
+
+func (r *OrganizationReconciler) handleUpdate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Organization, diff []string) (ctrl.Result, error) {
+	log := r.Log.WithValues("organization", req.NamespacedName)
+
+	if hasField(diff, "name") {
+		log.Info("Renaming Jira Service Desk Organization to " + instance.Spec.Name)
+
+		organization := r.JiraServiceDeskClient.GetOrganizationFromOrganizationCR(instance)
+		err := r.JiraServiceDeskClient.UpdateOrganization(ctx, instance.Status.OrganizationId, organization)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, UpdatedReason, "Updated fields %s of organization %s with id %s", strings.Join(diff, ", "), instance.Spec.Name, instance.Status.OrganizationId)
+	}
+
+	log.Info("Modifying project associations for JSD Organization: " + instance.Spec.Name)
+
+	err := r.updateProjects(ctx, req, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
+	return reconcilerUtil.ManageSuccess(r.Client, instance)
+}
+
+// updateProjects adds the organization to the projects of the spec and removes it from the projects that were dropped
+func (r *OrganizationReconciler) updateProjects(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Organization) error {
+	log := r.Log.WithValues("organization", req.NamespacedName)
+
+	err := updateMemberships(instance.Spec.Projects, instance.Status.AssociatedProjects,
+		func(projectKey string) error {
+			err := r.JiraServiceDeskClient.AddOrganizationToProject(ctx, instance.Status.OrganizationId, projectKey)
+			if err != nil {
+				return err
+			}
+			log.Info("Successfully added Jira Service Desk Organization into project: " + projectKey)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AddedToProjectReason, "Added organization %s to project %s", instance.Status.OrganizationId, projectKey)
+			return nil
+		},
+		func(projectKey string) error {
+			err := r.JiraServiceDeskClient.RemoveOrganizationFromProject(ctx, instance.Status.OrganizationId, projectKey)
+			if err != nil {
+				return err
+			}
+			log.Info("Successfully removed Jira Service Desk Organization from project: " + projectKey)
+			recordEvent(r.Recorder, instance, corev1.EventTypeNormal, RemovedFromProjectReason, "Removed organization %s from project %s", instance.Status.OrganizationId, projectKey)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	instance.Status.AssociatedProjects = instance.Spec.Projects
+	return nil
+}
+
+func (r *OrganizationReconciler) handleDelete(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Organization) (ctrl.Result, error) {
+	log := r.Log.WithValues("organization", req.NamespacedName)
+
+	if instance == nil {
+		// Instance not found, nothing to do
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	log.Info("Deleting Jira Service Desk Organization: " + instance.Spec.Name)
+
+	// Check if the organization was created
+	if instance.Status.OrganizationId != "" {
+		// Requeue until the connection can be resolved, the organization would be left behind on JSD otherwise
+		jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+		if err != nil {
+			return r.manageError(instance, err, true)
+		}
+		// Deleting the organization also removes it from all the service desks
+		err = jiraServiceDeskClient.DeleteOrganization(ctx, instance.Status.OrganizationId)
+		// Objects that were already deleted on JSD need no cleanup
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			return r.manageError(instance, err, false)
+		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, DeletedReason, "Deleted organization %s with id %s", instance.Spec.Name, instance.Status.OrganizationId)
+	} else {
+		log.Info("Organization '" + instance.Spec.Name + "' do not exists on JSD. So skipping deletion")
+	}
+
+	// Delete Finalizer
+	finalizerUtil.DeleteFinalizer(instance, OrganizationFinalizer)
+
+	log.Info("Finalizer removed for organization: " + instance.Spec.Name)
+
+	// Update instance
+	err := r.Client.Update(ctx, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+var _ = Describe("Organization Controller", func() {
+
+	ns, _ = os.LookupEnv("OPERATOR_NAMESPACE")
+
+	organizationInput := mockData.SampleOrganization
+	// Randomize organization name
+	str := oUtil.RandSeqString(3)
+	organizationInput.Spec.Name += str
+
+	AfterEach(func() {
+		oUtil.TryDeleteOrganization(organizationInput.Spec.Name, ns)
+	})
+
+	Describe("Create new Jira Service Desk organization", func() {
+		Context("With valid fields", func() {
+			It("should create a new organization", func() {
+				organizationInput.Spec.Projects = []string{strings.ToUpper(projectKey)}
+
+				_ = oUtil.CreateOrganization(organizationInput, ns)
+				organization := oUtil.GetOrganization(organizationInput.Spec.Name, ns)
+
+				Expect(organization.Status.OrganizationId).ToNot(Equal(""))
+				Expect(organization.Status.AssociatedProjects).To(Equal(organizationInput.Spec.Projects))
+			})
+		})
+	})
+
+	Describe("Modifying organization", func() {
+		Context("With a new name and project", func() {
+			It("should rename the organization and move it to the new project", func() {
+				organizationInput.Spec.Projects = []string{strings.ToUpper(projectKey)}
+
+				_ = oUtil.CreateOrganization(organizationInput, ns)
+				organization := oUtil.GetOrganization(organizationInput.Spec.Name, ns)
+				Expect(organization.Status.OrganizationId).ToNot(Equal(""))
+
+				organization.Spec.Name = organizationInput.Spec.Name + "-renamed"
+				organization.Spec.Projects = []string{strings.ToUpper(customerKey)}
+
+				_ = oUtil.UpdateOrganization(organization, ns)
+				updatedOrganization := oUtil.GetOrganization(organizationInput.Spec.Name, ns)
+
+				Expect(updatedOrganization.Status.AssociatedProjects).To(Equal(organization.Spec.Projects))
+			})
+		})
+	})
+
+	Describe("Delete Jira Service Desk organization", func() {
+		Context("With valid Organization Id", func() {
+			It("should delete the organization", func() {
+				_ = oUtil.CreateOrganization(organizationInput, ns)
+
+				organization := oUtil.GetOrganization(organizationInput.Spec.Name, ns)
+				Expect(organization.Status.OrganizationId).NotTo(BeEmpty())
+
+				oUtil.DeleteOrganization(organization.Name, ns)
+
+				organizationObject := &v1alpha1.Organization{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: organizationInput.Spec.Name, Namespace: ns}, organizationObject)
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
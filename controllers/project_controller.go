@@ -18,11 +18,16 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,8 +40,9 @@ import (
 const (
 	// TODO: Check if this is required in our case
 	// 	defaultRequeueTime        = 60 * time.Second
-	ProjectFinalizer        string = "jiraservicedesk.stakater.com/project"
-	ProjectAlreadyExistsErr string = "A project with that name already exists."
+	ProjectFinalizer       string = "jiraservicedesk.stakater.com/project"
+	ProjectNotReadyErr     string = "Project %s has not been created on JSD yet"
+	ProjectLeadNotFoundErr string = "Project lead %s does not exist on JSD"
 )
 
 // ProjectReconciler reconciles a Project object
@@ -45,14 +51,26 @@ type ProjectReconciler struct {
 	Scheme                *runtime.Scheme
 	Log                   logr.Logger
 	JiraServiceDeskClient jiraservicedeskclient.Client
+	// Uncached reader used to resolve the connections and credentials of other Jira sites, and the images of avatars
+	APIReader client.Reader
+	// Interval after which projects are compared with JSD again, 0 disables the resync
+	ResyncInterval time.Duration
+	// Revert changes made to projects on JSD instead of only reporting them
+	RevertDrift bool
+	// Deletion policy used for projects that do not specify one
+	DefaultDeletionPolicy jiraservicedeskv1alpha1.DeletionPolicy
+	// Records events for the changes made on JSD, set up with the manager if not given
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=projects,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=projects/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=jiraconnections;clusterjiraconnections,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
 	log := r.Log.WithValues("project", req.NamespacedName)
 
 	log.Info("Reconciling Project")
@@ -60,7 +78,7 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// Fetch the Project instance
 	instance := &jiraservicedeskv1alpha1.Project{}
 
-	err := r.Get(context.TODO(), req.NamespacedName, instance)
+	err := r.Get(ctx, req.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -72,99 +90,197 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcilerUtil.RequeueWithError(err)
 	}
 
-	// Validate Custom Resource
-	if ok, err := instance.IsValid(); !ok {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
-	}
-
-	// Resource is marked for deletion
+	// Resource is marked for deletion. Deletion is handled before the connection is resolved,
+	// so that custom resources whose connection is gone can still be deleted
 	if instance.DeletionTimestamp != nil {
 		log.Info("Deletion timestamp found for instance " + req.Name)
 		if finalizerUtil.HasFinalizer(instance, ProjectFinalizer) {
-			return r.handleDelete(req, instance)
+			return r.handleDelete(ctx, req, instance)
 		}
 		// Finalizer doesn't exist so clean up is already done
 		return reconcilerUtil.DoNotRequeue()
 	}
 
+	// Validate Custom Resource
+	if ok, err := instance.IsValid(); !ok {
+		return r.manageError(instance, err, false)
+	}
+
+	// Manage the project on the Jira site of the referenced connection
+	jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+	r = r.withJiraServiceDeskClient(jiraServiceDeskClient)
+
 	// Add finalizer if it doesn't exist
 	if !finalizerUtil.HasFinalizer(instance, ProjectFinalizer) {
 		log.Info("Adding finalizer for instance " + req.Name)
 
 		finalizerUtil.AddFinalizer(instance, ProjectFinalizer)
 
-		err := r.Client.Update(context.TODO(), instance)
+		err := r.Client.Update(ctx, instance)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 	}
 
 	// Check if the Project already exists
 	if len(instance.Status.ID) > 0 {
-		existingProject, err := r.JiraServiceDeskClient.GetProjectByIdentifier(instance.Status.ID)
+		updatedProject := r.JiraServiceDeskClient.GetProjectFromProjectCR(instance)
+		existingProject, err := r.JiraServiceDeskClient.GetDeclaredProject(ctx, instance.Status.ID, updatedProject)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 		// Project already exists
 		if len(existingProject.Id) > 0 {
-			updatedProject := r.JiraServiceDeskClient.GetProjectFromProjectCR(instance)
+			avatarUpdated, err := r.reconcileAvatar(ctx, instance)
+			if err != nil {
+				return r.manageError(instance, err, false)
+			}
+
 			// Compare retrieved project with current spec
-			if !r.JiraServiceDeskClient.ProjectEqual(existingProject, updatedProject) {
-				// Update if there are changes in the declared spec
-				return r.handleUpdate(req, existingProject, instance)
+			diff := r.JiraServiceDeskClient.ProjectDiff(existingProject, updatedProject)
+
+			// Project was changed on JSD since the spec was last reconciled
+			if isDrift(instance, instance.Status.ObservedGeneration, diff) && !r.RevertDrift {
+				log.Info("Drift detected in project fields: " + strings.Join(diff, ", "))
+				return r.resync(manageDrift(ctx, r.Client, instance, diff))
+			}
+
+			if len(diff) > 0 {
+				// Update if there are changes in the declared spec or drift has to be reverted
+				return r.resync(r.handleUpdate(ctx, req, existingProject, instance, diff))
+			} else if avatarUpdated || !isInSync(instance, instance.Status.ObservedGeneration, instance.Status.Conditions) {
+				instance.Status.ObservedGeneration = instance.Generation
+				return r.resync(reconcilerUtil.ManageSuccess(r.Client, instance))
 			} else {
 				log.Info("Skipping update. No changes found")
-				return reconcilerUtil.DoNotRequeue()
+				return r.resync(reconcilerUtil.DoNotRequeue())
 			}
 		}
 	}
 
-	return r.handleCreate(req, instance)
+	// Bind the custom resource to an existing project instead of creating a new one
+	if importId := getImportId(instance); len(importId) > 0 {
+		return r.resync(r.handleImport(ctx, req, importId, instance))
+	}
+
+	return r.resync(r.handleCreate(ctx, req, instance))
+}
+
+// withJiraServiceDeskClient returns a copy of the reconciler that uses the given client
+func (r *ProjectReconciler) withJiraServiceDeskClient(jiraServiceDeskClient jiraservicedeskclient.Client) *ProjectReconciler {
+	reconciler := *r
+	reconciler.JiraServiceDeskClient = jiraServiceDeskClient
+	return &reconciler
+}
+
+func (r *ProjectReconciler) resync(result ctrl.Result, err error) (ctrl.Result, error) {
+	return requeueAfterResyncInterval(result, err, r.ResyncInterval)
+}
+
+// manageError records the error as an event on the project and in its status
+func (r *ProjectReconciler) manageError(instance *jiraservicedeskv1alpha1.Project, err error, retry bool) (ctrl.Result, error) {
+	return manageErrorWithEvent(r.Client, r.Recorder, instance, err, retry)
 }
 
 func (r *ProjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("project-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&jiraservicedeskv1alpha1.Project{}).
 		Complete(r)
 }
 
-func (r *ProjectReconciler) handleCreate(req ctrl.Request, instance *jiraservicedeskv1alpha1.Project) (ctrl.Result, error) {
+func (r *ProjectReconciler) handleCreate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Project) (ctrl.Result, error) {
 	log := r.Log.WithValues("project", req.NamespacedName)
 
 	log.Info("Creating Jira Service Desk Project: " + instance.Spec.Name)
 
 	project := r.JiraServiceDeskClient.GetProjectFromProjectCR(instance)
-	projectId, err := r.JiraServiceDeskClient.CreateProject(project)
+	projectId, err := r.JiraServiceDeskClient.CreateProject(ctx, project)
 
 	// If project already exists then reconstruct status
-	if err != nil && strings.Contains(err.Error(), ProjectAlreadyExistsErr) {
-		existingProject, err := r.JiraServiceDeskClient.GetProjectByIdentifier(instance.Spec.Key)
+	if jiraservicedeskclient.IsConflict(err) {
+		// Only the id of the existing project is needed
+		existingProject, err := r.JiraServiceDeskClient.GetDeclaredProject(ctx, instance.Spec.Key, jiraservicedeskclient.Project{})
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 		log.Info("Successfully reconstructed status for Jira Service Desk Project " + instance.Spec.Name)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AdoptedReason, "Adopted existing project %s with id %s", existingProject.Key, existingProject.Id)
 
 		projectId = existingProject.Id
 	} else if err != nil {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
+		return r.manageError(instance, err, false)
+	} else {
+		log.Info("Successfully created Jira Service Desk Project: " + instance.Spec.Name)
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, CreatedReason, "Created project %s with id %s", project.Key, projectId)
 	}
 
-	log.Info("Successfully created Jira Service Desk Project: " + instance.Spec.Name)
-
-	if !instance.Spec.OpenAccess {
-		err = r.JiraServiceDeskClient.UpdateProjectAccessPermissions(instance.Spec.OpenAccess, project.Key)
+	// Only service desks have customer access
+	customerAccess := project.CustomerAccess
+	if customerAccess == nil && !instance.Spec.OpenAccess && instance.Spec.ProjectTypeKey == jiraservicedeskv1alpha1.ServiceDeskProjectTypeKey {
+		// The deprecated openAccess field only restricts the access of customers, other settings are left as they are
+		existingCustomerAccess, err := r.JiraServiceDeskClient.GetProjectAccessPermissions(ctx, project.Key)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+		existingCustomerAccess.ServiceDeskOpenAccess, existingCustomerAccess.ServiceDeskPublicSignup = false, false
+		customerAccess = &existingCustomerAccess
+	}
+	if customerAccess != nil {
+		err = r.JiraServiceDeskClient.UpdateProjectAccessPermissions(ctx, *customerAccess, project.Key)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, false)
 		}
 
 		log.Info("Successfully updated the Access Permissions to customer")
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, PermissionsUpdatedReason, "Updated customer access of project %s with id %s", project.Key, projectId)
 	}
 
 	instance.Status.ID = projectId
+
+	_, err = r.reconcileAvatar(ctx, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
 	return reconcilerUtil.ManageSuccess(r.Client, instance)
 }
 
-func (r *ProjectReconciler) handleDelete(req ctrl.Request, instance *jiraservicedeskv1alpha1.Project) (ctrl.Result, error) {
+func (r *ProjectReconciler) handleImport(ctx context.Context, req ctrl.Request, importId string, instance *jiraservicedeskv1alpha1.Project) (ctrl.Result, error) {
+	log := r.Log.WithValues("project", req.NamespacedName)
+
+	log.Info("Importing Jira Service Desk Project: " + importId)
+
+	project := r.JiraServiceDeskClient.GetProjectFromProjectCR(instance)
+	existingProject, err := r.JiraServiceDeskClient.GetDeclaredProject(ctx, importId, project)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	instance.Status.ID = existingProject.Id
+	instance.Status.ObservedGeneration = instance.Generation
+	// The spec has no id, the custom resource is bound to the id of the existing project
+	project.Id = existingProject.Id
+
+	// Report the differences to the spec instead of overwriting the existing project
+	diff := r.JiraServiceDeskClient.ProjectDiff(existingProject, project)
+	if len(diff) > 0 {
+		log.Info("Imported project differs from spec in fields: " + strings.Join(diff, ", "))
+	}
+
+	log.Info("Successfully imported Jira Service Desk Project: " + existingProject.Key)
+	recordEvent(r.Recorder, instance, corev1.EventTypeNormal, AdoptedReason, "Adopted existing project %s with id %s", existingProject.Key, existingProject.Id)
+
+	return manageImport(ctx, r.Client, instance, diff)
+}
+
+func (r *ProjectReconciler) handleDelete(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.Project) (ctrl.Result, error) {
 	log := r.Log.WithValues("project", req.NamespacedName)
 
 	if instance == nil {
@@ -175,11 +291,21 @@ func (r *ProjectReconciler) handleDelete(req ctrl.Request, instance *jiraservice
 	log.Info("Deleting Jira Service Desk Project: " + instance.Spec.Name)
 
 	// Check if the project was created
-	if instance.Status.ID != "" {
-		err := r.JiraServiceDeskClient.DeleteProject(instance.Status.ID)
+	if deletionPolicy := getDeletionPolicy(instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy); deletionPolicy != jiraservicedeskv1alpha1.DeletionPolicyDelete {
+		log.Info("Project '" + instance.Spec.Name + "' has deletion policy " + string(deletionPolicy) + ". So skipping deletion")
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, RetainedReason, "Kept project %s with id %s on deletion policy %s", instance.Spec.Key, instance.Status.ID, deletionPolicy)
+	} else if instance.Status.ID != "" {
+		// Requeue until the connection can be resolved, the project would be left behind on JSD otherwise
+		jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, instance, err, false)
+			return r.manageError(instance, err, true)
+		}
+		err = jiraServiceDeskClient.DeleteProject(ctx, instance.Status.ID)
+		// Objects that were already deleted on JSD need no cleanup
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			return r.manageError(instance, err, false)
 		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, DeletedReason, "Deleted project %s with id %s", instance.Spec.Key, instance.Status.ID)
 	} else {
 		log.Info("Project '" + instance.Spec.Name + "' do not exists on JSD. So skipping deletion")
 	}
@@ -190,30 +316,99 @@ func (r *ProjectReconciler) handleDelete(req ctrl.Request, instance *jiraservice
 	log.Info("Finalizer removed for project: " + instance.Spec.Name)
 
 	// Update instance
-	err := r.Client.Update(context.TODO(), instance)
+	err := r.Client.Update(ctx, instance)
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
+		return r.manageError(instance, err, false)
 	}
 
 	return reconcilerUtil.DoNotRequeue()
 }
 
-func (r *ProjectReconciler) handleUpdate(req ctrl.Request, existingProject jiraservicedeskclient.Project, instance *jiraservicedeskv1alpha1.Project) (ctrl.Result, error) {
+func (r *ProjectReconciler) handleUpdate(ctx context.Context, req ctrl.Request, existingProject jiraservicedeskclient.Project, instance *jiraservicedeskv1alpha1.Project, diff []string) (ctrl.Result, error) {
 	log := r.Log.WithValues("project", req.NamespacedName)
 
 	log.Info("Updating Jira Service Desk Project: " + instance.Spec.Name)
 
 	existingProjectInstance := r.JiraServiceDeskClient.GetProjectCRFromProject(existingProject)
+	// The template of projects is not read back on every flavour, in which case it is only compared by ProjectDiff
+	if !hasField(diff, "projectTemplateKey") {
+		existingProjectInstance.Spec.ProjectTemplateKey = instance.Spec.ProjectTemplateKey
+	}
 	if ok, err := instance.IsValidUpdate(existingProjectInstance); !ok {
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
+		return r.manageError(instance, err, false)
+	}
+
+	// Check the new lead before changing the project, so that a typo does not leave the project without a lead
+	if hasField(diff, "leadAccountId") {
+		exists, err := r.JiraServiceDeskClient.ProjectLeadExists(ctx, instance.Spec.LeadAccountId)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+		if !exists {
+			return r.manageError(instance, fmt.Errorf(ProjectLeadNotFoundErr, instance.Spec.LeadAccountId), false)
+		}
 	}
 
 	updatedProject := r.JiraServiceDeskClient.GetProjectForUpdateRequest(existingProject, instance)
-	err := r.JiraServiceDeskClient.UpdateProject(updatedProject, existingProject.Id)
-	if err != nil {
-		log.Error(err, "Failed to update status of Project")
-		return reconcilerUtil.ManageError(r.Client, instance, err, false)
+	if updatedProject != (jiraservicedeskclient.Project{}) {
+		err := r.JiraServiceDeskClient.UpdateProject(ctx, updatedProject, existingProject.Id)
+		if err != nil {
+			log.Error(err, "Failed to update status of Project")
+			return r.manageError(instance, err, false)
+		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, UpdatedReason, "Updated fields %s of project %s with id %s", strings.Join(diff, ", "), instance.Spec.Key, existingProject.Id)
+	}
+
+	// Customer access is not part of the project, it is updated through the request security settings of the service desk
+	if customerAccess := r.JiraServiceDeskClient.GetProjectFromProjectCR(instance).CustomerAccess; customerAccess != nil && hasField(diff, "customerAccess") {
+		err := r.JiraServiceDeskClient.UpdateProjectAccessPermissions(ctx, *customerAccess, instance.Spec.Key)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+
+		log.Info("Successfully updated the Access Permissions to customer")
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, PermissionsUpdatedReason, "Updated customer access of project %s with id %s", instance.Spec.Key, existingProject.Id)
 	}
 
+	instance.Status.ObservedGeneration = instance.Generation
 	return reconcilerUtil.ManageSuccess(r.Client, instance)
 }
+
+// getProjectKey resolves the key of a project that is referenced either by the name of its
+// Project custom resource in the given namespace or directly by its key. Project custom resources
+// have to be managed with the given connection
+func getProjectKey(ctx context.Context, c client.Client, namespace string, projectName string, projectKey string, connectionRef *jiraservicedeskv1alpha1.ConnectionReference) (string, error) {
+	if len(projectKey) > 0 {
+		return projectKey, nil
+	}
+
+	project := &jiraservicedeskv1alpha1.Project{}
+
+	err := c.Get(ctx, types.NamespacedName{Name: projectName, Namespace: namespace}, project)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkSameConnection(connectionRef, project.Spec.ConnectionRef, "Project", projectName)
+	if err != nil {
+		return "", err
+	}
+
+	if len(project.Status.ID) == 0 {
+		return "", fmt.Errorf(ProjectNotReadyErr, projectName)
+	}
+
+	return project.Spec.Key, nil
+}
+
+// getDeletionPolicy returns the deletion policy of a custom resource, falling back to the default
+// deletion policy of the operator
+func getDeletionPolicy(deletionPolicy jiraservicedeskv1alpha1.DeletionPolicy, defaultDeletionPolicy jiraservicedeskv1alpha1.DeletionPolicy) jiraservicedeskv1alpha1.DeletionPolicy {
+	if len(deletionPolicy) > 0 {
+		return deletionPolicy
+	}
+	if len(defaultDeletionPolicy) > 0 {
+		return defaultDeletionPolicy
+	}
+	return jiraservicedeskv1alpha1.DeletionPolicyDelete
+}
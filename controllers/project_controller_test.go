@@ -9,6 +9,9 @@ import (
 	. "github.com/onsi/gomega"
 	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	c "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	"github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -60,6 +63,83 @@ var _ = Describe("Project Controller", func() {
 			})
 		})
 
+		Describe("Deleting jira service desk project resource with Retain deletion policy", func() {
+			Context("With valid project Id", func() {
+				It("should remove resource and keep the project on JSD", func() {
+					retainedProjectInput := projectInput
+					retainedProjectInput.Spec.DeletionPolicy = jiraservicedeskv1alpha1.DeletionPolicyRetain
+
+					_ = util.CreateProject(retainedProjectInput, ns)
+
+					project := util.GetProject(retainedProjectInput.Spec.Name, ns)
+					Expect(project.Status.ID).NotTo(BeEmpty())
+
+					util.DeleteProject(project.Name, ns)
+
+					projectObject := &jiraservicedeskv1alpha1.Project{}
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: retainedProjectInput.Spec.Name, Namespace: ns}, projectObject)
+					Expect(err).To(HaveOccurred())
+
+					existingProject, err := r.JiraServiceDeskClient.GetProjectByIdentifier(ctx, project.Status.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(existingProject.Id).To(Equal(project.Status.ID))
+
+					Expect(r.JiraServiceDeskClient.DeleteProject(ctx, project.Status.ID)).To(Succeed())
+				})
+			})
+		})
+
+		Describe("Importing existing jira service desk project", func() {
+			Context("With import annotation", func() {
+				It("should bind resource to the project and report differences", func() {
+					retainedProjectInput := projectInput
+					retainedProjectInput.Spec.DeletionPolicy = jiraservicedeskv1alpha1.DeletionPolicyRetain
+
+					_ = util.CreateProject(retainedProjectInput, ns)
+					project := util.GetProject(retainedProjectInput.Spec.Name, ns)
+					Expect(project.Status.ID).NotTo(BeEmpty())
+					util.DeleteProject(project.Name, ns)
+
+					importedProjectInput := projectInput
+					importedProjectInput.Annotations = map[string]string{jiraservicedeskv1alpha1.ImportAnnotation: project.Status.ID}
+					importedProjectInput.Spec.Description = "Changed description"
+
+					_ = util.CreateProject(importedProjectInput, ns)
+					importedProject := util.GetProject(importedProjectInput.Spec.Name, ns)
+
+					Expect(importedProject.Status.ID).To(Equal(project.Status.ID))
+					drifted := meta.FindStatusCondition(importedProject.Status.Conditions, DriftedCondition)
+					Expect(drifted).NotTo(BeNil())
+					Expect(drifted.Message).To(Equal(DriftDetectedMsg + "description"))
+
+					existingProject, err := r.JiraServiceDeskClient.GetProjectByIdentifier(ctx, project.Status.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(existingProject.Description).To(Equal(projectInput.Spec.Description))
+				})
+			})
+
+			Context("With import annotation and an identical spec", func() {
+				It("should bind resource to the project without reporting drift", func() {
+					retainedProjectInput := projectInput
+					retainedProjectInput.Spec.DeletionPolicy = jiraservicedeskv1alpha1.DeletionPolicyRetain
+
+					_ = util.CreateProject(retainedProjectInput, ns)
+					project := util.GetProject(retainedProjectInput.Spec.Name, ns)
+					Expect(project.Status.ID).NotTo(BeEmpty())
+					util.DeleteProject(project.Name, ns)
+
+					importedProjectInput := projectInput
+					importedProjectInput.Annotations = map[string]string{jiraservicedeskv1alpha1.ImportAnnotation: project.Status.ID}
+
+					_ = util.CreateProject(importedProjectInput, ns)
+					importedProject := util.GetProject(importedProjectInput.Spec.Name, ns)
+
+					Expect(importedProject.Status.ID).To(Equal(project.Status.ID))
+					Expect(meta.IsStatusConditionTrue(importedProject.Status.Conditions, DriftedCondition)).To(BeFalse())
+				})
+			})
+		})
+
 		Describe("Updating jira service desk resource", func() {
 			Context("With mutable fields ", func() {
 
@@ -87,6 +167,64 @@ var _ = Describe("Project Controller", func() {
 					Expect(updatedProject.Spec.Key).To(Equal(mockData.UpdateMutableProjectFields.Key))
 				})
 
+				It("should update the customer access of the project", func() {
+					_ = util.CreateProject(projectInput, ns)
+					project := util.GetProject(projectInput.Spec.Name, ns)
+
+					project.Spec.CustomerAccess = &jiraservicedeskv1alpha1.CustomerAccess{OpenAccess: true, AutocompleteEnabled: true}
+
+					err := k8sClient.Update(ctx, project)
+					if err != nil {
+						Fail(err.Error())
+					}
+
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: projectInput.Spec.Name, Namespace: ns}}
+					_, err = r.Reconcile(context.Background(), req)
+					if err != nil {
+						Fail(err.Error())
+					}
+
+					customerAccess, err := r.JiraServiceDeskClient.GetProjectAccessPermissions(ctx, project.Spec.Key)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(customerAccess.ServiceDeskOpenAccess).To(BeTrue())
+					Expect(customerAccess.AutocompleteEnabled).To(BeTrue())
+					Expect(customerAccess.ServiceDeskPublicSignup).To(BeFalse())
+				})
+
+				It("should update the project on a Data Center site, which does not return the template of projects", func() {
+					dataCenterReconciler := r.withJiraServiceDeskClient(c.NewSiteClient(c.Site{
+						BaseURL: fake.NewServer().Start(ctx),
+						Auth:    c.BasicAuth{Email: "operator@fake.test", APIToken: "fake-token"},
+						Flavour: c.FlavourDataCenter,
+					}))
+
+					Expect(k8sClient.Create(ctx, util.CreateProjectObject(projectInput, ns))).To(Succeed())
+					req := reconcile.Request{NamespacedName: types.NamespacedName{Name: projectInput.Spec.Name, Namespace: ns}}
+					_, err := dataCenterReconciler.Reconcile(context.Background(), req)
+					Expect(err).NotTo(HaveOccurred())
+
+					project := util.GetProject(projectInput.Spec.Name, ns)
+					Expect(project.Status.ID).NotTo(BeEmpty())
+
+					project.Spec.Description = "Changed description"
+					Expect(k8sClient.Update(ctx, project)).To(Succeed())
+
+					_, err = dataCenterReconciler.Reconcile(context.Background(), req)
+					Expect(err).NotTo(HaveOccurred())
+
+					updatedProject := util.GetProject(projectInput.Spec.Name, ns)
+					Expect(meta.IsStatusConditionTrue(updatedProject.Status.Conditions, "ReconcileSuccess")).To(BeTrue())
+
+					existingProject, err := dataCenterReconciler.JiraServiceDeskClient.GetProjectByIdentifier(ctx, project.Status.ID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(existingProject.Description).To(Equal("Changed description"))
+
+					// The project only exists on the Data Center site
+					Expect(k8sClient.Delete(ctx, updatedProject)).To(Succeed())
+					_, err = dataCenterReconciler.Reconcile(context.Background(), req)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
 			})
 			Context("With immutable fields ", func() {
 
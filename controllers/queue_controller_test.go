@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+var _ = Describe("Queue Controller", func() {
+
+	ns, _ = os.LookupEnv("OPERATOR_NAMESPACE")
+
+	queueInput := mockData.SampleQueue
+	// Randomize queue name
+	str := qUtil.RandSeqString(3)
+	queueInput.Spec.Name += str
+
+	AfterEach(func() {
+		qUtil.TryDeleteQueue(queueInput.Spec.Name, ns)
+	})
+
+	Describe("Create new Jira Service Desk queue", func() {
+		Context("With a Project custom resource reference", func() {
+			It("should create a new queue in the project", func() {
+				queueInput.Spec.ProjectName = mockData.SampleProjectInput.Spec.Name
+				queueInput.Spec.ProjectKey = ""
+
+				_ = qUtil.CreateQueue(queueInput, ns)
+				queue := qUtil.GetQueue(queueInput.Spec.Name, ns)
+
+				Expect(queue.Status.QueueId).ToNot(Equal(""))
+				Expect(queue.Status.ProjectKey).To(Equal(strings.ToUpper(projectKey)))
+			})
+		})
+	})
+
+	Describe("Modifying queue", func() {
+		Context("With a new JQL and ordering", func() {
+			It("should update the queue", func() {
+				queueInput.Spec.ProjectName = ""
+				queueInput.Spec.ProjectKey = strings.ToUpper(projectKey)
+
+				_ = qUtil.CreateQueue(queueInput, ns)
+				queue := qUtil.GetQueue(queueInput.Spec.Name, ns)
+				Expect(queue.Status.QueueId).ToNot(Equal(""))
+
+				queue.Spec.JQL = "resolution = EMPTY AND status = \"Waiting for customer\""
+				queue.Spec.OrderBy = &v1alpha1.QueueOrderBy{Field: "updated", Direction: "ASC"}
+
+				_ = qUtil.UpdateQueue(queue, ns)
+				updatedQueue := qUtil.GetQueue(queueInput.Spec.Name, ns)
+
+				existingQueue, err := qr.JiraServiceDeskClient.GetQueueById(ctx, updatedQueue.Status.ProjectKey, updatedQueue.Status.QueueId)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(qr.JiraServiceDeskClient.IsQueueUpdated(updatedQueue, existingQueue)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Delete Jira Service Desk queue", func() {
+		Context("With valid Queue Id", func() {
+			It("should delete the queue", func() {
+				queueInput.Spec.ProjectName = ""
+				queueInput.Spec.ProjectKey = strings.ToUpper(projectKey)
+
+				_ = qUtil.CreateQueue(queueInput, ns)
+
+				queue := qUtil.GetQueue(queueInput.Spec.Name, ns)
+				Expect(queue.Status.QueueId).NotTo(BeEmpty())
+
+				qUtil.DeleteQueue(queue.Name, ns)
+
+				queueObject := &v1alpha1.Queue{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: queueInput.Spec.Name, Namespace: ns}, queueObject)
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
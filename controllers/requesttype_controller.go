@@ -0,0 +1,239 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+const (
+	RequestTypeFinalizer     string = "jiraservicedesk.stakater.com/requesttype"
+	RequestTypeNotUpdatedErr string = "Fields %s of request type %s can not be updated on JSD, recreate the RequestType to change them"
+)
+
+// RequestTypeReconciler reconciles a RequestType object
+type RequestTypeReconciler struct {
+	client.Client
+	Log                   logr.Logger
+	Scheme                *runtime.Scheme
+	JiraServiceDeskClient jiraservicedeskclient.Client
+	// Uncached reader used to resolve the connections and credentials of other Jira sites
+	APIReader client.Reader
+	// Interval after which request types are compared with JSD again, 0 disables the resync
+	ResyncInterval time.Duration
+	// Records events for the changes made on JSD, set up with the manager if not given
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=requesttypes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=requesttypes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=jiraconnections;clusterjiraconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *RequestTypeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("requesttype", req.NamespacedName)
+
+	log.Info("Reconciling RequestType")
+
+	// Fetch the RequestType instance
+	instance := &jiraservicedeskv1alpha1.RequestType{}
+
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Error reading the object - requeue the request.
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Resource is marked for deletion. Deletion is handled before the connection is resolved,
+	// so that custom resources whose connection is gone can still be deleted
+	if instance.DeletionTimestamp != nil {
+		log.Info("Deletion timestamp found for instance " + req.Name)
+		if finalizerUtil.HasFinalizer(instance, RequestTypeFinalizer) {
+			return r.handleDelete(ctx, req, instance)
+		}
+		// Finalizer doesn't exist so clean up is already done
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Validate Custom Resource
+	if ok, err := instance.IsValid(); !ok {
+		return r.manageError(instance, err, false)
+	}
+
+	// Manage the request type on the Jira site of the referenced connection
+	jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+	r = r.withJiraServiceDeskClient(jiraServiceDeskClient)
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(instance, RequestTypeFinalizer) {
+		log.Info("Adding finalizer for instance " + req.Name)
+
+		finalizerUtil.AddFinalizer(instance, RequestTypeFinalizer)
+
+		err := r.Client.Update(ctx, instance)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+	}
+
+	// If RequestTypeId exists in status, then the request type has already been created
+	if len(instance.Status.RequestTypeId) > 0 {
+		existingRequestType, err := r.JiraServiceDeskClient.GetRequestTypeById(ctx, instance.Status.ProjectKey, instance.Status.RequestTypeId)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+
+		// Request types can not be updated on Jira Service Desk, so differences to the spec are only reported
+		diff := r.JiraServiceDeskClient.RequestTypeDiff(instance, existingRequestType)
+
+		// Request type was changed on JSD since the spec was last reconciled
+		if isDrift(instance, instance.Status.ObservedGeneration, diff) {
+			log.Info("Drift detected in request type fields: " + strings.Join(diff, ", "))
+			return r.resync(manageDrift(ctx, r.Client, instance, diff))
+		}
+
+		if len(diff) > 0 {
+			return r.resync(r.manageError(instance, fmt.Errorf(RequestTypeNotUpdatedErr, strings.Join(diff, ", "), instance.Status.RequestTypeId), false))
+		} else if !isInSync(instance, instance.Status.ObservedGeneration, instance.Status.Conditions) {
+			instance.Status.ObservedGeneration = instance.Generation
+			return r.resync(reconcilerUtil.ManageSuccess(r.Client, instance))
+		} else {
+			log.Info("Skipping update. No changes found")
+			return r.resync(reconcilerUtil.DoNotRequeue())
+		}
+	}
+
+	return r.resync(r.handleCreate(ctx, req, instance))
+}
+
+// withJiraServiceDeskClient returns a copy of the reconciler that uses the given client
+func (r *RequestTypeReconciler) withJiraServiceDeskClient(jiraServiceDeskClient jiraservicedeskclient.Client) *RequestTypeReconciler {
+	reconciler := *r
+	reconciler.JiraServiceDeskClient = jiraServiceDeskClient
+	return &reconciler
+}
+
+func (r *RequestTypeReconciler) resync(result ctrl.Result, err error) (ctrl.Result, error) {
+	return requeueAfterResyncInterval(result, err, r.ResyncInterval)
+}
+
+// manageError records the error as an event on the request type and in its status
+func (r *RequestTypeReconciler) manageError(instance *jiraservicedeskv1alpha1.RequestType, err error, retry bool) (ctrl.Result, error) {
+	return manageErrorWithEvent(r.Client, r.Recorder, instance, err, retry)
+}
+
+func (r *RequestTypeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("requesttype-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&jiraservicedeskv1alpha1.RequestType{}).
+		Complete(r)
+}
+
+func (r *RequestTypeReconciler) handleCreate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.RequestType) (ctrl.Result, error) {
+	log := r.Log.WithValues("requesttype", req.NamespacedName)
+
+	projectKey, err := getProjectKey(ctx, r.Client, req.Namespace, instance.Spec.ProjectName, instance.Spec.ProjectKey, instance.Spec.ConnectionRef)
+	if err != nil {
+		// The project may still be getting created, so retry unless it is managed on another Jira site
+		return r.manageError(instance, err, !isConnectionMismatch(err))
+	}
+
+	log.Info("Creating Jira Service Desk RequestType: " + instance.Spec.Name + " in project: " + projectKey)
+
+	requestType := r.JiraServiceDeskClient.GetRequestTypeFromRequestTypeCR(instance)
+	requestTypeId, err := r.JiraServiceDeskClient.CreateRequestType(ctx, projectKey, requestType)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	instance.Status.RequestTypeId = requestTypeId
+	instance.Status.ProjectKey = projectKey
+
+	log.Info("Successfully created Jira Service Desk RequestType: " + instance.Spec.Name)
+	recordEvent(r.Recorder, instance, corev1.EventTypeNormal, CreatedReason, "Created request type %s with id %s in project %s", instance.Spec.Name, requestTypeId, projectKey)
+
+	instance.Status.ObservedGeneration = instance.Generation
+	return reconcilerUtil.ManageSuccess(r.Client, instance)
+}
+
+func (r *RequestTypeReconciler) handleDelete(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.RequestType) (ctrl.Result, error) {
+	log := r.Log.WithValues("requesttype", req.NamespacedName)
+
+	if instance == nil {
+		// Instance not found, nothing to do
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	log.Info("Deleting Jira Service Desk RequestType: " + instance.Spec.Name)
+
+	// Check if the request type was created
+	if instance.Status.RequestTypeId != "" {
+		// Requeue until the connection can be resolved, the request type would be left behind on JSD otherwise
+		jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+		if err != nil {
+			return r.manageError(instance, err, true)
+		}
+		err = jiraServiceDeskClient.DeleteRequestType(ctx, instance.Status.ProjectKey, instance.Status.RequestTypeId)
+		// Objects that were already deleted on JSD need no cleanup
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			return r.manageError(instance, err, false)
+		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, DeletedReason, "Deleted request type %s with id %s from project %s", instance.Spec.Name, instance.Status.RequestTypeId, instance.Status.ProjectKey)
+	} else {
+		log.Info("RequestType '" + instance.Spec.Name + "' do not exists on JSD. So skipping deletion")
+	}
+
+	// Delete Finalizer
+	finalizerUtil.DeleteFinalizer(instance, RequestTypeFinalizer)
+
+	log.Info("Finalizer removed for requesttype: " + instance.Spec.Name)
+
+	// Update instance
+	err := r.Client.Update(ctx, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+var _ = Describe("RequestType Controller", func() {
+
+	ns, _ = os.LookupEnv("OPERATOR_NAMESPACE")
+
+	requestTypeInput := mockData.SampleRequestType
+	// Randomize request type name
+	str := rtUtil.RandSeqString(3)
+	requestTypeInput.Spec.Name += str
+
+	AfterEach(func() {
+		rtUtil.TryDeleteRequestType(requestTypeInput.Spec.Name, ns)
+	})
+
+	Describe("Create new Jira Service Desk request type", func() {
+		Context("With a Project custom resource reference", func() {
+			It("should create a new request type in the project", func() {
+				requestTypeInput.Spec.ProjectName = mockData.SampleProjectInput.Spec.Name
+				requestTypeInput.Spec.ProjectKey = ""
+
+				_ = rtUtil.CreateRequestType(requestTypeInput, ns)
+				requestType := rtUtil.GetRequestType(requestTypeInput.Spec.Name, ns)
+
+				Expect(requestType.Status.RequestTypeId).ToNot(Equal(""))
+				Expect(requestType.Status.ProjectKey).To(Equal(strings.ToUpper(projectKey)))
+			})
+		})
+
+		Context("With a project key", func() {
+			It("should create a new request type in the project", func() {
+				requestTypeInput.Spec.ProjectName = ""
+				requestTypeInput.Spec.ProjectKey = strings.ToUpper(customerKey)
+
+				_ = rtUtil.CreateRequestType(requestTypeInput, ns)
+				requestType := rtUtil.GetRequestType(requestTypeInput.Spec.Name, ns)
+
+				Expect(requestType.Status.RequestTypeId).ToNot(Equal(""))
+				Expect(requestType.Status.ProjectKey).To(Equal(strings.ToUpper(customerKey)))
+			})
+		})
+	})
+
+	Describe("Update Jira Service Desk request type", func() {
+		Context("With a changed spec", func() {
+			It("should report that the request type can not be updated", func() {
+				requestTypeInput.Spec.ProjectName = ""
+				requestTypeInput.Spec.ProjectKey = strings.ToUpper(projectKey)
+
+				_ = rtUtil.CreateRequestType(requestTypeInput, ns)
+				requestType := rtUtil.GetRequestType(requestTypeInput.Spec.Name, ns)
+				Expect(requestType.Status.RequestTypeId).NotTo(BeEmpty())
+
+				requestType.Spec.HelpText = "Describe the problem"
+				_ = rtUtil.UpdateRequestType(requestType, ns)
+
+				updatedRequestType := rtUtil.GetRequestType(requestTypeInput.Spec.Name, ns)
+				condition := meta.FindStatusCondition(updatedRequestType.Status.Conditions, "ReconcileError")
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Message).To(ContainSubstring("helpText"))
+			})
+		})
+	})
+
+	Describe("Delete Jira Service Desk request type", func() {
+		Context("With valid RequestType Id", func() {
+			It("should delete the request type", func() {
+				requestTypeInput.Spec.ProjectName = ""
+				requestTypeInput.Spec.ProjectKey = strings.ToUpper(projectKey)
+
+				_ = rtUtil.CreateRequestType(requestTypeInput, ns)
+
+				requestType := rtUtil.GetRequestType(requestTypeInput.Spec.Name, ns)
+				Expect(requestType.Status.RequestTypeId).NotTo(BeEmpty())
+
+				rtUtil.DeleteRequestType(requestType.Name, ns)
+
+				requestTypeObject := &v1alpha1.RequestType{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: requestTypeInput.Spec.Name, Namespace: ns}, requestTypeObject)
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
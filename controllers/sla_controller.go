@@ -0,0 +1,259 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+)
+
+const (
+	SLAFinalizer string = "jiraservicedesk.stakater.com/sla"
+)
+
+// SLAReconciler reconciles an SLA object
+type SLAReconciler struct {
+	client.Client
+	Log                   logr.Logger
+	Scheme                *runtime.Scheme
+	JiraServiceDeskClient jiraservicedeskclient.Client
+	// Uncached reader used to resolve the connections and credentials of other Jira sites
+	APIReader client.Reader
+	// Interval after which SLAs are compared with JSD again, 0 disables the resync
+	ResyncInterval time.Duration
+	// Revert changes made to SLAs on JSD instead of only reporting them
+	RevertDrift bool
+	// Records events for the changes made on JSD, set up with the manager if not given
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=slas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=slas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=jiraservicedesk.stakater.com,resources=jiraconnections;clusterjiraconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *SLAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("sla", req.NamespacedName)
+
+	log.Info("Reconciling SLA")
+
+	// Fetch the SLA instance
+	instance := &jiraservicedeskv1alpha1.SLA{}
+
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Error reading the object - requeue the request.
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Resource is marked for deletion. Deletion is handled before the connection is resolved,
+	// so that custom resources whose connection is gone can still be deleted
+	if instance.DeletionTimestamp != nil {
+		log.Info("Deletion timestamp found for instance " + req.Name)
+		if finalizerUtil.HasFinalizer(instance, SLAFinalizer) {
+			return r.handleDelete(ctx, req, instance)
+		}
+		// Finalizer doesn't exist so clean up is already done
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Validate Custom Resource
+	if ok, err := instance.IsValid(); !ok {
+		return r.manageError(instance, err, false)
+	}
+
+	// Manage the SLA on the Jira site of the referenced connection
+	jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+	r = r.withJiraServiceDeskClient(jiraServiceDeskClient)
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(instance, SLAFinalizer) {
+		log.Info("Adding finalizer for instance " + req.Name)
+
+		finalizerUtil.AddFinalizer(instance, SLAFinalizer)
+
+		err := r.Client.Update(ctx, instance)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+	}
+
+	// If SLAId exists in status, then it's an update request
+	if len(instance.Status.SLAId) > 0 {
+		// Get the SLA from Jira Service Desk
+		existingSLA, err := r.JiraServiceDeskClient.GetSLAById(ctx, instance.Status.ProjectKey, instance.Status.SLAId)
+		if err != nil {
+			return r.manageError(instance, err, false)
+		}
+
+		// Compare retrieved SLA with current spec
+		diff := r.JiraServiceDeskClient.SLADiff(instance, existingSLA)
+
+		// SLA was changed on JSD since the spec was last reconciled
+		if isDrift(instance, instance.Status.ObservedGeneration, diff) && !r.RevertDrift {
+			log.Info("Drift detected in SLA fields: " + strings.Join(diff, ", "))
+			return r.resync(manageDrift(ctx, r.Client, instance, diff))
+		}
+
+		if len(diff) > 0 {
+			// Update if there are changes in the declared spec or drift has to be reverted
+			return r.resync(r.handleUpdate(ctx, req, instance, diff))
+		} else if !isInSync(instance, instance.Status.ObservedGeneration, instance.Status.Conditions) {
+			instance.Status.ObservedGeneration = instance.Generation
+			return r.resync(reconcilerUtil.ManageSuccess(r.Client, instance))
+		} else {
+			log.Info("Skipping update. No changes found")
+			return r.resync(reconcilerUtil.DoNotRequeue())
+		}
+	}
+
+	return r.resync(r.handleCreate(ctx, req, instance))
+}
+
+// withJiraServiceDeskClient returns a copy of the reconciler that uses the given client
+func (r *SLAReconciler) withJiraServiceDeskClient(jiraServiceDeskClient jiraservicedeskclient.Client) *SLAReconciler {
+	reconciler := *r
+	reconciler.JiraServiceDeskClient = jiraServiceDeskClient
+	return &reconciler
+}
+
+func (r *SLAReconciler) resync(result ctrl.Result, err error) (ctrl.Result, error) {
+	return requeueAfterResyncInterval(result, err, r.ResyncInterval)
+}
+
+// manageError records the error as an event on the SLA and in its status
+func (r *SLAReconciler) manageError(instance *jiraservicedeskv1alpha1.SLA, err error, retry bool) (ctrl.Result, error) {
+	return manageErrorWithEvent(r.Client, r.Recorder, instance, err, retry)
+}
+
+func (r *SLAReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("sla-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&jiraservicedeskv1alpha1.SLA{}).
+		Complete(r)
+}
+
+func (r *SLAReconciler) handleCreate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.SLA) (ctrl.Result, error) {
+	log := r.Log.WithValues("sla", req.NamespacedName)
+
+	projectKey, err := getProjectKey(ctx, r.Client, req.Namespace, instance.Spec.ProjectName, instance.Spec.ProjectKey, instance.Spec.ConnectionRef)
+	if err != nil {
+		// The project may still be getting created, so retry unless it is managed on another Jira site
+		return r.manageError(instance, err, !isConnectionMismatch(err))
+	}
+
+	log.Info("Creating Jira Service Desk SLA: " + instance.Spec.Name + " in project: " + projectKey)
+
+	sla := r.JiraServiceDeskClient.GetSLAFromSLACR(instance)
+	slaId, err := r.JiraServiceDeskClient.CreateSLA(ctx, projectKey, sla)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	instance.Status.SLAId = slaId
+	instance.Status.ProjectKey = projectKey
+
+	log.Info("Successfully created Jira Service Desk SLA: " + instance.Spec.Name)
+	recordEvent(r.Recorder, instance, corev1.EventTypeNormal, CreatedReason, "Created SLA %s with id %s in project %s", instance.Spec.Name, slaId, projectKey)
+
+	instance.Status.ObservedGeneration = instance.Generation
+	return reconcilerUtil.ManageSuccess(r.Client, instance)
+}
+
+func (r *SLAReconciler) handleUpdate(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.SLA, diff []string) (ctrl.Result, error) {
+	log := r.Log.WithValues("sla", req.NamespacedName)
+
+	log.Info("Updating Jira Service Desk SLA: " + instance.Spec.Name)
+
+	sla := r.JiraServiceDeskClient.GetSLAFromSLACR(instance)
+	err := r.JiraServiceDeskClient.UpdateSLA(ctx, instance.Status.ProjectKey, instance.Status.SLAId, sla)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	log.Info("Successfully updated Jira Service Desk SLA: " + instance.Spec.Name)
+	recordEvent(r.Recorder, instance, corev1.EventTypeNormal, UpdatedReason, "Updated fields %s of SLA %s with id %s", strings.Join(diff, ", "), instance.Spec.Name, instance.Status.SLAId)
+
+	instance.Status.ObservedGeneration = instance.Generation
+	return reconcilerUtil.ManageSuccess(r.Client, instance)
+}
+
+func (r *SLAReconciler) handleDelete(ctx context.Context, req ctrl.Request, instance *jiraservicedeskv1alpha1.SLA) (ctrl.Result, error) {
+	log := r.Log.WithValues("sla", req.NamespacedName)
+
+	if instance == nil {
+		// Instance not found, nothing to do
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	log.Info("Deleting Jira Service Desk SLA: " + instance.Spec.Name)
+
+	// Check if the SLA was created
+	if instance.Status.SLAId != "" {
+		// Requeue until the connection can be resolved, the SLA would be left behind on JSD otherwise
+		jiraServiceDeskClient, err := getJiraServiceDeskClient(ctx, r.APIReader, r.JiraServiceDeskClient, instance.Namespace, instance.Spec.ConnectionRef)
+		if err != nil {
+			return r.manageError(instance, err, true)
+		}
+		err = jiraServiceDeskClient.DeleteSLA(ctx, instance.Status.ProjectKey, instance.Status.SLAId)
+		// Objects that were already deleted on JSD need no cleanup
+		if err != nil && !jiraservicedeskclient.IsNotFound(err) {
+			return r.manageError(instance, err, false)
+		}
+		recordEvent(r.Recorder, instance, corev1.EventTypeNormal, DeletedReason, "Deleted SLA %s with id %s from project %s", instance.Spec.Name, instance.Status.SLAId, instance.Status.ProjectKey)
+	} else {
+		log.Info("SLA '" + instance.Spec.Name + "' do not exists on JSD. So skipping deletion")
+	}
+
+	// Delete Finalizer
+	finalizerUtil.DeleteFinalizer(instance, SLAFinalizer)
+
+	log.Info("Finalizer removed for SLA: " + instance.Spec.Name)
+
+	// Update instance
+	err := r.Client.Update(ctx, instance)
+	if err != nil {
+		return r.manageError(instance, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
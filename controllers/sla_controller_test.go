@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("SLA Controller", func() {
+
+	ns, _ = os.LookupEnv("OPERATOR_NAMESPACE")
+
+	slaInput := *mockData.SampleSLA.DeepCopy()
+	// Randomize SLA name
+	str := sUtil.RandSeqString(3)
+	slaInput.Spec.Name += str
+
+	AfterEach(func() {
+		sUtil.TryDeleteSLA(slaInput.Spec.Name, ns)
+	})
+
+	Describe("Create new Jira Service Desk SLA", func() {
+		Context("With a Project custom resource reference", func() {
+			It("should create a new SLA in the project", func() {
+				slaInput.Spec.ProjectName = mockData.SampleProjectInput.Spec.Name
+				slaInput.Spec.ProjectKey = ""
+
+				_ = sUtil.CreateSLA(slaInput, ns)
+				sla := sUtil.GetSLA(slaInput.Spec.Name, ns)
+
+				Expect(sla.Status.SLAId).ToNot(Equal(""))
+				Expect(sla.Status.ProjectKey).To(Equal(strings.ToUpper(projectKey)))
+			})
+		})
+	})
+
+	Describe("Modifying SLA", func() {
+		Context("With a new goal", func() {
+			It("should update the SLA goals", func() {
+				slaInput.Spec.ProjectName = ""
+				slaInput.Spec.ProjectKey = strings.ToUpper(projectKey)
+
+				_ = sUtil.CreateSLA(slaInput, ns)
+				sla := sUtil.GetSLA(slaInput.Spec.Name, ns)
+				Expect(sla.Status.SLAId).ToNot(Equal(""))
+
+				sla.Spec.Goals = append([]v1alpha1.SLAGoal{
+					{JQL: "priority = High", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+				}, sla.Spec.Goals...)
+
+				_ = sUtil.UpdateSLA(sla, ns)
+				updatedSLA := sUtil.GetSLA(slaInput.Spec.Name, ns)
+
+				existingSLA, err := sr.JiraServiceDeskClient.GetSLAById(ctx, updatedSLA.Status.ProjectKey, updatedSLA.Status.SLAId)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sr.JiraServiceDeskClient.IsSLAUpdated(updatedSLA, existingSLA)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Delete Jira Service Desk SLA", func() {
+		Context("With valid SLA Id", func() {
+			It("should delete the SLA", func() {
+				slaInput.Spec.ProjectName = ""
+				slaInput.Spec.ProjectKey = strings.ToUpper(projectKey)
+
+				_ = sUtil.CreateSLA(slaInput, ns)
+
+				sla := sUtil.GetSLA(slaInput.Spec.Name, ns)
+				Expect(sla.Status.SLAId).NotTo(BeEmpty())
+
+				sUtil.DeleteSLA(sla.Name, ns)
+
+				slaObject := &v1alpha1.SLA{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: slaInput.Spec.Name, Namespace: ns}, slaObject)
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
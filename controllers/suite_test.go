@@ -26,6 +26,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +41,7 @@ import (
 	controllerUtil "github.com/stakater/jira-service-desk-operator/controllers/util"
 	c "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
 	"github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/config"
+	"github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/fake"
 	secretsUtil "github.com/stakater/operator-utils/util/secrets"
 	// +kubebuilder:scaffold:imports
 )
@@ -57,6 +61,15 @@ var ns string
 var cr *CustomerReconciler
 var cUtil *controllerUtil.TestUtil
 
+var or *OrganizationReconciler
+var oUtil *controllerUtil.TestUtil
+var rtr *RequestTypeReconciler
+var rtUtil *controllerUtil.TestUtil
+var qr *QueueReconciler
+var qUtil *controllerUtil.TestUtil
+var sr *SLAReconciler
+var sUtil *controllerUtil.TestUtil
+
 var log = logf.Log.WithName("config")
 var customerKey = cUtil.RandSeqString(3)
 var projectKey = cUtil.RandSeqString(3)
@@ -99,6 +112,11 @@ var _ = BeforeSuite(func(done Done) {
 	// Retrieve operator namespace
 	ns, _ := os.LookupEnv("OPERATOR_NAMESPACE")
 
+	// Tests run against an in-memory fake of Jira Service Desk, unless a real site is configured in the config secret
+	if os.Getenv("USE_JIRA_SITE") != "true" {
+		createFakeConfigSecret(ns)
+	}
+
 	apiToken, err := secretsUtil.LoadSecretDataUsingClient(k8sClient, config.JiraServiceDeskSecretName, ns, config.JiraServiceDeskAPITokenSecretKey)
 	Expect(err).ToNot(HaveOccurred())
 	Expect(apiToken).ToNot(BeNil())
@@ -116,6 +134,7 @@ var _ = BeforeSuite(func(done Done) {
 		Scheme:                scheme.Scheme,
 		Log:                   log.WithName("Reconciler"),
 		JiraServiceDeskClient: c.NewClient(apiToken, apiBaseUrl, email),
+		APIReader:             k8sClient,
 	}
 	Expect(r).ToNot((BeNil()))
 
@@ -127,12 +146,61 @@ var _ = BeforeSuite(func(done Done) {
 		Scheme:                scheme.Scheme,
 		Log:                   log.WithName("Reconciler"),
 		JiraServiceDeskClient: c.NewClient(apiToken, apiBaseUrl, email),
+		APIReader:             k8sClient,
 	}
 	Expect(cr).ToNot((BeNil()))
 
 	cUtil = controllerUtil.New(ctx, k8sClient, cr)
 	Expect(util).ToNot(BeNil())
 
+	or = &OrganizationReconciler{
+		Client:                k8sClient,
+		Scheme:                scheme.Scheme,
+		Log:                   log.WithName("Reconciler"),
+		JiraServiceDeskClient: c.NewClient(apiToken, apiBaseUrl, email),
+		APIReader:             k8sClient,
+	}
+	Expect(or).ToNot((BeNil()))
+
+	oUtil = controllerUtil.New(ctx, k8sClient, or)
+	Expect(oUtil).ToNot(BeNil())
+
+	rtr = &RequestTypeReconciler{
+		Client:                k8sClient,
+		Scheme:                scheme.Scheme,
+		Log:                   log.WithName("Reconciler"),
+		JiraServiceDeskClient: c.NewClient(apiToken, apiBaseUrl, email),
+		APIReader:             k8sClient,
+	}
+	Expect(rtr).ToNot((BeNil()))
+
+	rtUtil = controllerUtil.New(ctx, k8sClient, rtr)
+	Expect(rtUtil).ToNot(BeNil())
+
+	qr = &QueueReconciler{
+		Client:                k8sClient,
+		Scheme:                scheme.Scheme,
+		Log:                   log.WithName("Reconciler"),
+		JiraServiceDeskClient: c.NewClient(apiToken, apiBaseUrl, email),
+		APIReader:             k8sClient,
+	}
+	Expect(qr).ToNot((BeNil()))
+
+	qUtil = controllerUtil.New(ctx, k8sClient, qr)
+	Expect(qUtil).ToNot(BeNil())
+
+	sr = &SLAReconciler{
+		Client:                k8sClient,
+		Scheme:                scheme.Scheme,
+		Log:                   log.WithName("Reconciler"),
+		JiraServiceDeskClient: c.NewClient(apiToken, apiBaseUrl, email),
+		APIReader:             k8sClient,
+	}
+	Expect(sr).ToNot((BeNil()))
+
+	sUtil = controllerUtil.New(ctx, k8sClient, sr)
+	Expect(sUtil).ToNot(BeNil())
+
 	mockData.CustomerTestProjectInput.Spec.Name += customerKey
 	mockData.CustomerTestProjectInput.Spec.Key = strings.ToUpper(customerKey)
 
@@ -146,7 +214,32 @@ var _ = BeforeSuite(func(done Done) {
 	close(done)
 }, 60)
 
+// createFakeConfigSecret starts a fake Jira Service Desk and creates the config secret of the operator for it
+func createFakeConfigSecret(namespace string) {
+	baseURL := fake.NewServer().Start(ctx)
+
+	err := k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	if !apierrors.IsAlreadyExists(err) {
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: config.JiraServiceDeskSecretName, Namespace: namespace},
+		StringData: map[string]string{
+			config.JiraServiceDeskAPIBaseURLSecretKey: baseURL,
+			config.JiraServiceDeskAPITokenSecretKey:   "fake-token",
+			config.JiraServiceDeskEmailSecretKey:      "operator@fake.test",
+		},
+	}
+	Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+}
+
 var _ = AfterSuite(func() {
+	// Request types, queues and SLAs have to be removed before the projects they belong to
+	rtUtil.DeleteAllRequestTypes(ns)
+	qUtil.DeleteAllQueues(ns)
+	sUtil.DeleteAllSLAs(ns)
+
 	// Delete the projects created for customer tests
 	util.DeleteProject(mockData.CustomerTestProjectInput.Spec.Name, ns)
 	util.DeleteProject(mockData.SampleProjectInput.Spec.Name, ns)
@@ -154,6 +247,7 @@ var _ = AfterSuite(func() {
 	// Cleanup - Delete all remnent resources
 	util.DeleteAllProjects(ns)
 	util.DeleteAllCustomers(ns)
+	oUtil.DeleteAllOrganizations(ns)
 
 	By("tearing down the test environment")
 	err := testEnv.Stop()
@@ -2,14 +2,13 @@ package util
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"time"
 
 	"github.com/onsi/ginkgo"
-	ginko "github.com/onsi/ginkgo"
-	mockdata "github.com/stakater/jira-service-desk-operator/mock"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -82,8 +81,9 @@ func (t *TestUtil) DeleteNamespace(name string) {
 func (t *TestUtil) CreateProjectObject(project jiraservicedeskv1alpha1.Project, namespace string) *jiraservicedeskv1alpha1.Project {
 	return &jiraservicedeskv1alpha1.Project{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      project.Spec.Name,
-			Namespace: namespace,
+			Name:        project.Spec.Name,
+			Namespace:   namespace,
+			Annotations: project.Annotations,
 		},
 		Spec: jiraservicedeskv1alpha1.ProjectSpec{
 			Name:                project.Spec.Name,
@@ -95,10 +95,13 @@ func (t *TestUtil) CreateProjectObject(project jiraservicedeskv1alpha1.Project,
 			LeadAccountId:       project.Spec.LeadAccountId,
 			URL:                 project.Spec.URL,
 			AvatarId:            project.Spec.AvatarId,
+			Avatar:              project.Spec.Avatar,
 			IssueSecurityScheme: project.Spec.IssueSecurityScheme,
 			PermissionScheme:    project.Spec.PermissionScheme,
 			NotificationScheme:  project.Spec.NotificationScheme,
 			CategoryId:          project.Spec.CategoryId,
+			DeletionPolicy:      project.Spec.DeletionPolicy,
+			ConnectionRef:       project.Spec.ConnectionRef,
 		},
 	}
 }
@@ -111,213 +114,370 @@ func (t *TestUtil) CreateCustomerObject(customer jiraservicedeskv1alpha1.Custome
 			Namespace: namespace,
 		},
 		Spec: jiraservicedeskv1alpha1.CustomerSpec{
-			Name:     customer.Spec.Name,
-			Email:    customer.Spec.Email,
-			Projects: customer.Spec.Projects,
+			Name:          customer.Spec.Name,
+			Email:         customer.Spec.Email,
+			Projects:      customer.Spec.Projects,
+			Organizations: customer.Spec.Organizations,
 		},
 	}
 }
 
-// CreateProject creates and submits a Project object to the kubernetes server
-func (t *TestUtil) CreateProject(project jiraservicedeskv1alpha1.Project, namespace string) *jiraservicedeskv1alpha1.Project {
-	projectObject := t.CreateProjectObject(project, namespace)
+// CreateOrganizationObject creates a jira organization custom resource object
+func (t *TestUtil) CreateOrganizationObject(organization jiraservicedeskv1alpha1.Organization, namespace string) *jiraservicedeskv1alpha1.Organization {
+	return &jiraservicedeskv1alpha1.Organization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      organization.Spec.Name,
+			Namespace: namespace,
+		},
+		Spec: jiraservicedeskv1alpha1.OrganizationSpec{
+			Name:          organization.Spec.Name,
+			Projects:      organization.Spec.Projects,
+			ConnectionRef: organization.Spec.ConnectionRef,
+		},
+	}
+}
+
+// CreateRequestTypeObject creates a jira request type custom resource object
+func (t *TestUtil) CreateRequestTypeObject(requestType jiraservicedeskv1alpha1.RequestType, namespace string) *jiraservicedeskv1alpha1.RequestType {
+	return &jiraservicedeskv1alpha1.RequestType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      requestType.Spec.Name,
+			Namespace: namespace,
+		},
+		Spec: jiraservicedeskv1alpha1.RequestTypeSpec{
+			ProjectName:   requestType.Spec.ProjectName,
+			ProjectKey:    requestType.Spec.ProjectKey,
+			Name:          requestType.Spec.Name,
+			Description:   requestType.Spec.Description,
+			HelpText:      requestType.Spec.HelpText,
+			IssueTypeId:   requestType.Spec.IssueTypeId,
+			GroupIds:      requestType.Spec.GroupIds,
+			ConnectionRef: requestType.Spec.ConnectionRef,
+		},
+	}
+}
+
+// CreateQueueObject creates a jira queue custom resource object
+func (t *TestUtil) CreateQueueObject(queue jiraservicedeskv1alpha1.Queue, namespace string) *jiraservicedeskv1alpha1.Queue {
+	return &jiraservicedeskv1alpha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      queue.Spec.Name,
+			Namespace: namespace,
+		},
+		Spec: jiraservicedeskv1alpha1.QueueSpec{
+			ProjectName:   queue.Spec.ProjectName,
+			ProjectKey:    queue.Spec.ProjectKey,
+			Name:          queue.Spec.Name,
+			JQL:           queue.Spec.JQL,
+			Columns:       queue.Spec.Columns,
+			OrderBy:       queue.Spec.OrderBy,
+			ConnectionRef: queue.Spec.ConnectionRef,
+		},
+	}
+}
+
+// CreateSLAObject creates a jira SLA custom resource object
+func (t *TestUtil) CreateSLAObject(sla jiraservicedeskv1alpha1.SLA, namespace string) *jiraservicedeskv1alpha1.SLA {
+	return &jiraservicedeskv1alpha1.SLA{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sla.Spec.Name,
+			Namespace: namespace,
+		},
+		Spec: *sla.Spec.DeepCopy(),
+	}
+}
+
+// reconcile runs the reconciler of the TestUtil for the named object
+func (t *TestUtil) reconcile(name string, namespace string) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
 
-	err := t.k8sClient.Create(t.ctx, projectObject)
+	_, err := t.r.Reconcile(context.Background(), req)
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
+}
 
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: project.Spec.Name, Namespace: namespace}}
-
-	_, err = t.r.Reconcile(context.Background(), req)
+// createObject submits a new object to the kubernetes server and reconciles it
+func (t *TestUtil) createObject(obj client.Object) {
+	err := t.k8sClient.Create(t.ctx, obj)
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
 
-	return projectObject
+	t.reconcile(obj.GetName(), obj.GetNamespace())
 }
 
-// CreateCustomer creates and submits a new Customer object to the kubernetes server
-func (t *TestUtil) CreateCustomer(customer jiraservicedeskv1alpha1.Customer, namespace string) *jiraservicedeskv1alpha1.Customer {
-	customerObject := t.CreateCustomerObject(customer, namespace)
+// updateObject submits an updated object to the kubernetes server and reconciles it
+func (t *TestUtil) updateObject(obj client.Object, namespace string) {
+	err := t.k8sClient.Update(t.ctx, obj)
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
 
-	err := t.k8sClient.Create(t.ctx, customerObject)
+	t.reconcile(obj.GetName(), namespace)
+}
+
+// getObject fetches an object from kubernetes
+func (t *TestUtil) getObject(name string, namespace string, obj client.Object) {
+	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj)
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
+}
 
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: customer.Spec.Name, Namespace: namespace}}
+// deleteObject deletes an object and reconciles it, so that the finalizer of the operator is run
+func (t *TestUtil) deleteObject(name string, namespace string, obj client.Object) {
+	t.getObject(name, namespace, obj)
 
-	_, err = t.r.Reconcile(context.Background(), req)
+	err := t.k8sClient.Delete(t.ctx, obj)
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
 
-	return customerObject
+	t.reconcile(name, namespace)
 }
 
-// UpdateCustomer submits an updatedCustomer to the kubernetes server
-func (t *TestUtil) UpdateCustomer(customerObject *jiraservicedeskv1alpha1.Customer, namespace string) *jiraservicedeskv1alpha1.Customer {
+// tryDeleteObject tries to delete an object if it exists, does not fail on any error
+func (t *TestUtil) tryDeleteObject(name string, namespace string, obj client.Object) {
+	_ = t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj)
+	_ = t.k8sClient.Delete(t.ctx, obj)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	_, _ = t.r.Reconcile(context.Background(), req)
+}
 
-	err := t.k8sClient.Update(t.ctx, customerObject)
+// deleteAllObjects removes the finalizers of all the listed objects in the namespace and deletes them
+func (t *TestUtil) deleteAllObjects(namespace string, list client.ObjectList) {
+	err := t.k8sClient.List(t.ctx, list, &client.ListOptions{Namespace: namespace})
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
 
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: customerObject.Spec.Name, Namespace: namespace}}
-
-	_, err = t.r.Reconcile(context.Background(), req)
+	objects, err := meta.ExtractList(list)
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
 
+	for _, object := range objects {
+		obj := object.(client.Object)
+		obj.SetFinalizers([]string{})
+
+		err := t.k8sClient.Update(t.ctx, obj)
+		if apierrors.IsConflict(err) {
+			// The object was changed since it was listed, so remove the finalizers from its latest version
+			err = t.k8sClient.Get(t.ctx, client.ObjectKeyFromObject(obj), obj)
+			if err == nil {
+				obj.SetFinalizers([]string{})
+				err = t.k8sClient.Update(t.ctx, obj)
+			}
+		}
+		if err != nil {
+			ginkgo.Fail(err.Error())
+		}
+
+		t.tryDeleteObject(obj.GetName(), namespace, obj)
+	}
+}
+
+// CreateProject creates and submits a new Project object to the kubernetes server
+func (t *TestUtil) CreateProject(project jiraservicedeskv1alpha1.Project, namespace string) *jiraservicedeskv1alpha1.Project {
+	projectObject := t.CreateProjectObject(project, namespace)
+	t.createObject(projectObject)
+	return projectObject
+}
+
+// CreateCustomer creates and submits a new Customer object to the kubernetes server
+func (t *TestUtil) CreateCustomer(customer jiraservicedeskv1alpha1.Customer, namespace string) *jiraservicedeskv1alpha1.Customer {
+	customerObject := t.CreateCustomerObject(customer, namespace)
+	t.createObject(customerObject)
+	return customerObject
+}
+
+// CreateOrganization creates and submits a new Organization object to the kubernetes server
+func (t *TestUtil) CreateOrganization(organization jiraservicedeskv1alpha1.Organization, namespace string) *jiraservicedeskv1alpha1.Organization {
+	organizationObject := t.CreateOrganizationObject(organization, namespace)
+	t.createObject(organizationObject)
+	return organizationObject
+}
+
+// CreateRequestType creates and submits a new RequestType object to the kubernetes server
+func (t *TestUtil) CreateRequestType(requestType jiraservicedeskv1alpha1.RequestType, namespace string) *jiraservicedeskv1alpha1.RequestType {
+	requestTypeObject := t.CreateRequestTypeObject(requestType, namespace)
+	t.createObject(requestTypeObject)
+	return requestTypeObject
+}
+
+// CreateQueue creates and submits a new Queue object to the kubernetes server
+func (t *TestUtil) CreateQueue(queue jiraservicedeskv1alpha1.Queue, namespace string) *jiraservicedeskv1alpha1.Queue {
+	queueObject := t.CreateQueueObject(queue, namespace)
+	t.createObject(queueObject)
+	return queueObject
+}
+
+// CreateSLA creates and submits a new SLA object to the kubernetes server
+func (t *TestUtil) CreateSLA(sla jiraservicedeskv1alpha1.SLA, namespace string) *jiraservicedeskv1alpha1.SLA {
+	slaObject := t.CreateSLAObject(sla, namespace)
+	t.createObject(slaObject)
+	return slaObject
+}
+
+// UpdateCustomer submits an updated Customer to the kubernetes server
+func (t *TestUtil) UpdateCustomer(customerObject *jiraservicedeskv1alpha1.Customer, namespace string) *jiraservicedeskv1alpha1.Customer {
+	t.updateObject(customerObject, namespace)
 	return customerObject
 }
 
+// UpdateOrganization submits an updated Organization to the kubernetes server
+func (t *TestUtil) UpdateOrganization(organizationObject *jiraservicedeskv1alpha1.Organization, namespace string) *jiraservicedeskv1alpha1.Organization {
+	t.updateObject(organizationObject, namespace)
+	return organizationObject
+}
+
+// UpdateQueue submits an updated Queue to the kubernetes server
+func (t *TestUtil) UpdateQueue(queueObject *jiraservicedeskv1alpha1.Queue, namespace string) *jiraservicedeskv1alpha1.Queue {
+	t.updateObject(queueObject, namespace)
+	return queueObject
+}
+
+// UpdateSLA submits an updated SLA to the kubernetes server
+func (t *TestUtil) UpdateSLA(slaObject *jiraservicedeskv1alpha1.SLA, namespace string) *jiraservicedeskv1alpha1.SLA {
+	t.updateObject(slaObject, namespace)
+	return slaObject
+}
+
+// UpdateRequestType submits an updated RequestType to the kubernetes server
+func (t *TestUtil) UpdateRequestType(requestTypeObject *jiraservicedeskv1alpha1.RequestType, namespace string) *jiraservicedeskv1alpha1.RequestType {
+	t.updateObject(requestTypeObject, namespace)
+	return requestTypeObject
+}
+
 // GetProject fetches a project object from kubernetes
 func (t *TestUtil) GetProject(name string, namespace string) *jiraservicedeskv1alpha1.Project {
 	projectObject := &jiraservicedeskv1alpha1.Project{}
-	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, projectObject)
-
-	if err != nil {
-		ginko.Fail(err.Error())
-	}
-
+	t.getObject(name, namespace, projectObject)
 	return projectObject
 }
 
 // GetCustomer fetches a customer object from kubernetes
 func (t *TestUtil) GetCustomer(name string, namespace string) *jiraservicedeskv1alpha1.Customer {
 	customerObject := &jiraservicedeskv1alpha1.Customer{}
-
-	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, customerObject)
-	if err != nil {
-		ginkgo.Fail(err.Error())
-	}
-
+	t.getObject(name, namespace, customerObject)
 	return customerObject
 }
 
-// DeleteProject deletes the project resource
-func (t *TestUtil) DeleteProject(name string, namespace string) {
-	projectObject := &jiraservicedeskv1alpha1.Project{}
+// GetOrganization fetches an organization object from kubernetes
+func (t *TestUtil) GetOrganization(name string, namespace string) *jiraservicedeskv1alpha1.Organization {
+	organizationObject := &jiraservicedeskv1alpha1.Organization{}
+	t.getObject(name, namespace, organizationObject)
+	return organizationObject
+}
 
-	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, projectObject)
-	if err != nil {
-		ginko.Fail(err.Error())
-	}
+// GetRequestType fetches a request type object from kubernetes
+func (t *TestUtil) GetRequestType(name string, namespace string) *jiraservicedeskv1alpha1.RequestType {
+	requestTypeObject := &jiraservicedeskv1alpha1.RequestType{}
+	t.getObject(name, namespace, requestTypeObject)
+	return requestTypeObject
+}
 
-	err = t.k8sClient.Delete(t.ctx, projectObject)
-	if err != nil {
-		ginko.Fail(err.Error())
-	}
+// GetQueue fetches a queue object from kubernetes
+func (t *TestUtil) GetQueue(name string, namespace string) *jiraservicedeskv1alpha1.Queue {
+	queueObject := &jiraservicedeskv1alpha1.Queue{}
+	t.getObject(name, namespace, queueObject)
+	return queueObject
+}
 
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
-	_, err = t.r.Reconcile(context.Background(), req)
-	if err != nil {
-		ginko.Fail(err.Error())
-	}
+// GetSLA fetches an SLA object from kubernetes
+func (t *TestUtil) GetSLA(name string, namespace string) *jiraservicedeskv1alpha1.SLA {
+	slaObject := &jiraservicedeskv1alpha1.SLA{}
+	t.getObject(name, namespace, slaObject)
+	return slaObject
+}
+
+// DeleteProject deletes the project resource
+func (t *TestUtil) DeleteProject(name string, namespace string) {
+	t.deleteObject(name, namespace, &jiraservicedeskv1alpha1.Project{})
 }
 
 // DeleteCustomer deletes the customer resource
 func (t *TestUtil) DeleteCustomer(name string, namespace string) {
-	customerObject := &jiraservicedeskv1alpha1.Customer{}
+	t.deleteObject(name, namespace, &jiraservicedeskv1alpha1.Customer{})
+}
 
-	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, customerObject)
-	if err != nil {
-		ginkgo.Fail(err.Error())
-	}
+// DeleteOrganization deletes the organization resource
+func (t *TestUtil) DeleteOrganization(name string, namespace string) {
+	t.deleteObject(name, namespace, &jiraservicedeskv1alpha1.Organization{})
+}
 
-	err = t.k8sClient.Delete(t.ctx, customerObject)
-	if err != nil {
-		ginkgo.Fail(err.Error())
-	}
+// DeleteRequestType deletes the request type resource
+func (t *TestUtil) DeleteRequestType(name string, namespace string) {
+	t.deleteObject(name, namespace, &jiraservicedeskv1alpha1.RequestType{})
+}
 
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
-	_, err = t.r.Reconcile(context.Background(), req)
-	if err != nil {
-		ginkgo.Fail(err.Error())
-	}
+// DeleteQueue deletes the queue resource
+func (t *TestUtil) DeleteQueue(name string, namespace string) {
+	t.deleteObject(name, namespace, &jiraservicedeskv1alpha1.Queue{})
+}
+
+// DeleteSLA deletes the SLA resource
+func (t *TestUtil) DeleteSLA(name string, namespace string) {
+	t.deleteObject(name, namespace, &jiraservicedeskv1alpha1.SLA{})
 }
 
 // TryDeleteProject - Tries to delete Project if it exists, does not fail on any error
 func (t *TestUtil) TryDeleteProject(name string, namespace string) {
-	projectObject := &jiraservicedeskv1alpha1.Project{}
-	_ = t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, projectObject)
-	_ = t.k8sClient.Delete(t.ctx, projectObject)
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
-	_, _ = t.r.Reconcile(context.Background(), req)
+	t.tryDeleteObject(name, namespace, &jiraservicedeskv1alpha1.Project{})
 }
 
 // TryDeleteCustomer - Tries to delete Customer if it exists, does not fail on any error
 func (t *TestUtil) TryDeleteCustomer(name string, namespace string) {
-	customerObject := &jiraservicedeskv1alpha1.Customer{}
-	_ = t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, customerObject)
-	_ = t.k8sClient.Delete(t.ctx, customerObject)
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
-	_, _ = t.r.Reconcile(context.Background(), req)
+	t.tryDeleteObject(name, namespace, &jiraservicedeskv1alpha1.Customer{})
 }
 
-// DeleteAllProjects delete all the projects in the namespace
-func (t *TestUtil) DeleteAllProjects(namespace string) {
-	// Specify namespace in list Options
-	listOptions := &client.ListOptions{Namespace: namespace}
+// TryDeleteOrganization - Tries to delete Organization if it exists, does not fail on any error
+func (t *TestUtil) TryDeleteOrganization(name string, namespace string) {
+	t.tryDeleteObject(name, namespace, &jiraservicedeskv1alpha1.Organization{})
+}
 
-	// List projects in a specified namespace
-	projectList := &jiraservicedeskv1alpha1.ProjectList{}
-	err := t.k8sClient.List(context.TODO(), projectList, listOptions)
-	if err != nil {
-		ginkgo.Fail(err.Error())
-	}
+// TryDeleteRequestType - Tries to delete RequestType if it exists, does not fail on any error
+func (t *TestUtil) TryDeleteRequestType(name string, namespace string) {
+	t.tryDeleteObject(name, namespace, &jiraservicedeskv1alpha1.RequestType{})
+}
 
-	for _, project := range projectList.Items {
-		project.Finalizers = []string{}
+// TryDeleteQueue - Tries to delete Queue if it exists, does not fail on any error
+func (t *TestUtil) TryDeleteQueue(name string, namespace string) {
+	t.tryDeleteObject(name, namespace, &jiraservicedeskv1alpha1.Queue{})
+}
 
-		err := t.k8sClient.Update(t.ctx, &project)
-		if err != nil {
-			if err.Error() == fmt.Sprintf(mockdata.ProjectObjectModifiedError, project.Name) {
-				currentProject := t.GetProject(project.Name, namespace)
-				currentProject.Finalizers = []string{}
-				if err != nil {
-					ginkgo.Fail(err.Error())
-				}
-			} else {
-				ginkgo.Fail(err.Error())
-			}
-		}
+// TryDeleteSLA - Tries to delete SLA if it exists, does not fail on any error
+func (t *TestUtil) TryDeleteSLA(name string, namespace string) {
+	t.tryDeleteObject(name, namespace, &jiraservicedeskv1alpha1.SLA{})
+}
 
-		t.TryDeleteProject(project.Name, namespace)
-	}
+// DeleteAllProjects delete all the projects in the namespace
+func (t *TestUtil) DeleteAllProjects(namespace string) {
+	t.deleteAllObjects(namespace, &jiraservicedeskv1alpha1.ProjectList{})
 }
 
 // DeleteAllCustomers delete all the customers in the namespace
 func (t *TestUtil) DeleteAllCustomers(namespace string) {
-	// Specify namespace in list Options
-	listOptions := &client.ListOptions{Namespace: namespace}
+	t.deleteAllObjects(namespace, &jiraservicedeskv1alpha1.CustomerList{})
+}
 
-	// List customers in a specified namespace
-	customerList := &jiraservicedeskv1alpha1.CustomerList{}
-	err := t.k8sClient.List(context.TODO(), customerList, listOptions)
-	if err != nil {
-		ginkgo.Fail(err.Error())
-	}
+// DeleteAllOrganizations delete all the organizations in the namespace
+func (t *TestUtil) DeleteAllOrganizations(namespace string) {
+	t.deleteAllObjects(namespace, &jiraservicedeskv1alpha1.OrganizationList{})
+}
 
-	for _, customer := range customerList.Items {
-		customer.Finalizers = []string{}
+// DeleteAllRequestTypes delete all the request types in the namespace
+func (t *TestUtil) DeleteAllRequestTypes(namespace string) {
+	t.deleteAllObjects(namespace, &jiraservicedeskv1alpha1.RequestTypeList{})
+}
 
-		err := t.k8sClient.Update(t.ctx, &customer)
-		if err != nil {
-			if err.Error() == fmt.Sprintf(mockdata.CustomerObjectModifiedError, customer.Name) {
-				currentCustomer := t.GetCustomer(customer.Name, namespace)
-				currentCustomer.Finalizers = []string{}
-				if err != nil {
-					ginkgo.Fail(err.Error())
-				}
-			} else {
-				ginkgo.Fail(err.Error())
-			}
-		}
+// DeleteAllQueues delete all the queues in the namespace
+func (t *TestUtil) DeleteAllQueues(namespace string) {
+	t.deleteAllObjects(namespace, &jiraservicedeskv1alpha1.QueueList{})
+}
 
-		t.TryDeleteCustomer(customer.Name, namespace)
-	}
+// DeleteAllSLAs delete all the SLAs in the namespace
+func (t *TestUtil) DeleteAllSLAs(namespace string) {
+	t.deleteAllObjects(namespace, &jiraservicedeskv1alpha1.SLAList{})
 }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -34,11 +35,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 	"github.com/stakater/jira-service-desk-operator/controllers"
 	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
 	jiraservicedeskconfig "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/config"
+	"github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/fake"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -58,11 +61,41 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var resyncInterval time.Duration
+	var revertDrift bool
+	var defaultDeletionPolicy string
+	var retryPolicy jiraservicedeskclient.RetryPolicy
+	var rateLimit jiraservicedeskclient.RateLimit
+	var jiraApiTimeout time.Duration
+	var fakeJira bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 0,
+		"Interval after which resources are compared with Jira Service Desk again to detect drift. "+
+			"A value of 0 disables the periodic resync.")
+	flag.BoolVar(&revertDrift, "revert-drift", false,
+		"Revert changes made to projects, customers, organizations, queues and SLAs on Jira Service Desk instead of only reporting them in the Drifted condition.")
+	flag.StringVar(&defaultDeletionPolicy, "default-deletion-policy", string(jiraservicedeskv1alpha1.DeletionPolicyDelete),
+		"Deletion policy used for projects and customers that do not specify one. One of Delete, Retain or Orphan.")
+	flag.IntVar(&retryPolicy.MaxRetries, "max-retries", jiraservicedeskclient.DefaultRetryPolicy.MaxRetries,
+		"Maximum number of retries of requests to Jira Service Desk that are rate limited or fail with a server error. "+
+			"A value of 0 disables retries.")
+	flag.DurationVar(&retryPolicy.MinBackoff, "min-retry-backoff", jiraservicedeskclient.DefaultRetryPolicy.MinBackoff,
+		"Backoff before the first retry of a request to Jira Service Desk, which is doubled on every further retry.")
+	flag.DurationVar(&retryPolicy.MaxBackoff, "max-retry-backoff", jiraservicedeskclient.DefaultRetryPolicy.MaxBackoff,
+		"Maximum backoff between retries of a request to Jira Service Desk. "+
+			"Requests asking for a longer Retry-After are not retried.")
+	flag.Float64Var(&rateLimit.QPS, "jira-api-qps", 0,
+		"Maximum requests per second sent to a Jira site, shared by all reconcilers. A value of 0 disables the rate limit.")
+	flag.IntVar(&rateLimit.Burst, "jira-api-burst", 0,
+		"Maximum number of requests sent to a Jira site at once, if the rate limit is enabled. Values below 1 allow a single request.")
+	flag.DurationVar(&jiraApiTimeout, "jira-api-timeout", jiraservicedeskclient.DefaultTimeout,
+		"Timeout of a call to Jira Service Desk, including its retries. A value of 0 disables the timeout.")
+	flag.BoolVar(&fakeJira, "fake-jira", false,
+		"Run against an in-memory fake of Jira Service Desk instead of the site in the config secret, for local development.")
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
@@ -72,6 +105,11 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if !jiraservicedeskv1alpha1.DeletionPolicy(defaultDeletionPolicy).IsValid() {
+		setupLog.Error(fmt.Errorf("invalid deletion policy %s", defaultDeletionPolicy), "unable to parse flags")
+		os.Exit(1)
+	}
+
 	watchNamespace, err := getWatchNamespace()
 	if err != nil {
 		setupLog.Error(err, "unable to get WatchNamespace, "+
@@ -105,18 +143,61 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Load config for controller from secret
-	controllerConfig, err := jiraservicedeskconfig.LoadControllerConfig(mgr.GetAPIReader())
-	if err != nil {
-		setupLog.Error(err, "unable to load controller config")
-		os.Exit(1)
+	ctx := ctrl.SetupSignalHandler()
+
+	clientOptions := []jiraservicedeskclient.Option{
+		jiraservicedeskclient.WithRetryPolicy(retryPolicy), jiraservicedeskclient.WithRateLimit(rateLimit),
+		jiraservicedeskclient.WithTimeout(jiraApiTimeout),
+	}
+
+	var jiraServiceDeskClient jiraservicedeskclient.Client
+	if fakeJira {
+		// The fake site accepts any credentials and loses its state when the manager stops
+		baseURL := fake.NewServer().Start(ctx)
+		setupLog.Info("using fake Jira Service Desk", "url", baseURL)
+
+		jiraServiceDeskClient = jiraservicedeskclient.NewClientWithAuth(baseURL, jiraservicedeskclient.BasicAuth{}, clientOptions...)
+	} else {
+		// Rotated OAuth 2.0 refresh tokens have to survive restarts of the operator
+		jiraservicedeskconfig.SetSecretWriter(mgr.GetClient())
+
+		// Load config for controller from secret
+		controllerConfig, err := jiraservicedeskconfig.LoadControllerConfig(ctx, mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "unable to load controller config")
+			os.Exit(1)
+		}
+
+		// All reconcilers share the client, so that rotated credentials are swapped in one place
+		jiraServiceDeskClient = jiraservicedeskclient.NewSiteClient(controllerConfig.Site(), clientOptions...)
+
+		configReconciler := &controllers.ConfigReconciler{
+			Log:                    ctrl.Log.WithName("controllers").WithName("Config"),
+			APIReader:              mgr.GetAPIReader(),
+			SecretName:             jiraservicedeskconfig.JiraServiceDeskSecretName,
+			SecretNamespace:        jiraservicedeskconfig.GetOperatorNamespace(),
+			JiraServiceDeskClients: []jiraservicedeskclient.Client{jiraServiceDeskClient},
+		}
+		configReconciler.SetLoaded(controllerConfig)
+		if err = configReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Config")
+			os.Exit(1)
+		}
+		if err = mgr.AddMetricsExtraHandler("/config", configReconciler); err != nil {
+			setupLog.Error(err, "unable to set up config status handler")
+			os.Exit(1)
+		}
 	}
 
 	if err = (&controllers.ProjectReconciler{
 		Client:                mgr.GetClient(),
 		Scheme:                mgr.GetScheme(),
 		Log:                   ctrl.Log.WithName("controllers").WithName("Project"),
-		JiraServiceDeskClient: jiraservicedeskclient.NewClient(controllerConfig.ApiToken, controllerConfig.ApiBaseUrl, controllerConfig.Email),
+		JiraServiceDeskClient: jiraServiceDeskClient,
+		APIReader:             mgr.GetAPIReader(),
+		ResyncInterval:        resyncInterval,
+		RevertDrift:           revertDrift,
+		DefaultDeletionPolicy: jiraservicedeskv1alpha1.DeletionPolicy(defaultDeletionPolicy),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Project")
 		os.Exit(1)
@@ -126,12 +207,67 @@ func main() {
 		Client:                mgr.GetClient(),
 		Log:                   ctrl.Log.WithName("controllers").WithName("Customer"),
 		Scheme:                mgr.GetScheme(),
-		JiraServiceDeskClient: jiraservicedeskclient.NewClient(controllerConfig.ApiToken, controllerConfig.ApiBaseUrl, controllerConfig.Email),
+		JiraServiceDeskClient: jiraServiceDeskClient,
+		APIReader:             mgr.GetAPIReader(),
+		ResyncInterval:        resyncInterval,
+		RevertDrift:           revertDrift,
+		DefaultDeletionPolicy: jiraservicedeskv1alpha1.DeletionPolicy(defaultDeletionPolicy),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Customer")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.OrganizationReconciler{
+		Client:                mgr.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("Organization"),
+		Scheme:                mgr.GetScheme(),
+		JiraServiceDeskClient: jiraServiceDeskClient,
+		APIReader:             mgr.GetAPIReader(),
+		ResyncInterval:        resyncInterval,
+		RevertDrift:           revertDrift,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Organization")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.RequestTypeReconciler{
+		Client:                mgr.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("RequestType"),
+		Scheme:                mgr.GetScheme(),
+		JiraServiceDeskClient: jiraServiceDeskClient,
+		APIReader:             mgr.GetAPIReader(),
+		ResyncInterval:        resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RequestType")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.QueueReconciler{
+		Client:                mgr.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("Queue"),
+		Scheme:                mgr.GetScheme(),
+		JiraServiceDeskClient: jiraServiceDeskClient,
+		APIReader:             mgr.GetAPIReader(),
+		ResyncInterval:        resyncInterval,
+		RevertDrift:           revertDrift,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Queue")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.SLAReconciler{
+		Client:                mgr.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("SLA"),
+		Scheme:                mgr.GetScheme(),
+		JiraServiceDeskClient: jiraServiceDeskClient,
+		APIReader:             mgr.GetAPIReader(),
+		ResyncInterval:        resyncInterval,
+		RevertDrift:           revertDrift,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SLA")
+		os.Exit(1)
+	}
+
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&jiraservicedeskv1alpha1.Project{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Project")
@@ -141,8 +277,29 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Customer")
 			os.Exit(1)
 		}
+		if err = (&jiraservicedeskv1alpha1.Organization{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Organization")
+			os.Exit(1)
+		}
+		if err = (&jiraservicedeskv1alpha1.RequestType{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RequestType")
+			os.Exit(1)
+		}
+		if err = (&jiraservicedeskv1alpha1.Queue{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Queue")
+			os.Exit(1)
+		}
+		if err = (&jiraservicedeskv1alpha1.SLA{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SLA")
+			os.Exit(1)
+		}
 	}
 
+	metrics.Registry.MustRegister(&controllers.ResourceCollector{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("metrics"),
+	})
+
 	// Add health endpoints
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -155,7 +312,7 @@ func main() {
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
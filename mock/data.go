@@ -2,8 +2,10 @@ package mock
 
 import (
 	"strconv"
+	"time"
 
 	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const BaseURL = "https://sample.atlassian.net"
@@ -23,9 +25,6 @@ var AddCustomerFailedErrorMsg = "Rest request to add Customer failed with status
 var RemoveCustomerFailedErrorMsg = "Rest request to remove Customer failed with status: 400"
 var DeleteCustomerFailedErrorMsg = "Rest request to delete Customer failed with status: 400"
 
-var ProjectObjectModifiedError = "Operation cannot be fulfilled on projects.jiraservicedesk.stakater.com \"%s\": the object has been modified; please apply your changes to the latest version and try again"
-var CustomerObjectModifiedError = "Operation cannot be fulfilled on customers.jiraservicedesk.stakater.com \"%s\": the object has been modified; please apply your changes to the latest version and try again"
-
 var AddCustomerSuccessResponse = map[string]interface{}{
 	"accountIds": []string{CustomerAccountId},
 }
@@ -141,6 +140,7 @@ var UpdateImmutableProjectFields = struct {
 }
 
 var GetProjectByIdResponseJSON = map[string]string{
+	"id":             ProjectID,
 	"description":    "Sample Project",
 	"name":           "Sample",
 	"assigneeType":   "UNASSIGNED",
@@ -165,6 +165,36 @@ var GetProjectByIdExpectedResponse = struct {
 	"https://www.sample.com",
 }
 
+var GetServiceDeskProjectByIdResponseJSON = map[string]string{
+	"id":             ProjectID,
+	"name":           "Sample",
+	"projectTypeKey": "service_desk",
+	"key":            "SAMPLE",
+}
+
+var PermissionSchemeResponseJSON = map[string]interface{}{
+	"id":   10100,
+	"name": "Restricted Permission Scheme",
+}
+
+var NotificationSchemeResponseJSON = map[string]interface{}{
+	"id":   10000,
+	"name": "Default Notification Scheme",
+}
+
+var AssignPermissionSchemeRequestJSON = map[string]int{
+	"id": 10100,
+}
+
+var CustomerAccessJSON = map[string]bool{
+	"autocompleteEnabled":     true,
+	"manageEnabled":           false,
+	"serviceDeskOpenAccess":   true,
+	"serviceDeskPublicSignup": true,
+}
+
+var UpdateProjectAccessPermissionsFailedErrorMsg = "Rest request to update project permissions failed with status: 404"
+
 var UpdateProjectInput = struct {
 	Id   string
 	Name string
@@ -221,3 +251,225 @@ var AddProjectKey string = "ADD"
 var CustomerEndPoint string = "/customer"
 
 var RemoveProjectKey string = "REMOVE"
+
+var OrganizationID = "12"
+var OrganizationIDInt, _ = strconv.Atoi(OrganizationID)
+
+var GetOrganizationFailedErrorMsg = "Rest request to get organization failed with status: 404"
+var CreateOrganizationFailedErrorMsg = "Rest request to create organization failed with status: 400 and response: "
+var UpdateOrganizationFailedErrorMsg = "Rest request to update organization failed with status: 404 and response: "
+var DeleteOrganizationFailedErrorMsg = "Rest request to delete organization failed with status: 404"
+var AddOrganizationFailedErrorMsg = "Rest request to add organization failed with status: 400"
+var RemoveOrganizationFailedErrorMsg = "Rest request to remove organization failed with status: 400"
+var AddCustomerToOrganizationFailedErrorMsg = "Rest request to add Customer to organization failed with status: 400"
+var RemoveCustomerFromOrganizationFailedErrorMsg = "Rest request to remove Customer from organization failed with status: 400"
+
+var OrganizationEndPoint string = "/organization"
+var OrganizationUserEndPoint string = "/user"
+
+var SampleOrganization = jiraservicedeskv1alpha1.Organization{
+	Spec: jiraservicedeskv1alpha1.OrganizationSpec{
+		Name: "organization",
+		Projects: []string{
+			"SAMPLE",
+		},
+	},
+}
+
+var GetOrganizationResponseJSON = map[string]string{
+	"id":   OrganizationID,
+	"name": "Sample Organization",
+}
+
+var CreateOrganizationInputJSON = map[string]string{
+	"name": "Sample Organization",
+}
+
+var UpdateOrganizationInputJSON = map[string]string{
+	"name": "Renamed Organization",
+}
+
+var ListOrganizationsResponseJSON = map[string]interface{}{
+	"size":       2,
+	"start":      0,
+	"limit":      50,
+	"isLastPage": true,
+	"values": []map[string]string{
+		{"id": "11", "name": "Other Organization"},
+		{"id": OrganizationID, "name": "Sample Organization"},
+	},
+}
+
+var AddOrganizationRequestJSON = map[string]interface{}{
+	"organizationId": OrganizationIDInt,
+}
+
+var RequestTypeID = "25"
+var RequestTypeProjectKey = "SAMPLE"
+
+var GetRequestTypeFailedErrorMsg = "Rest request to get request type failed with status: 404"
+var CreateRequestTypeFailedErrorMsg = "Rest request to create request type failed with status: 400 and response: "
+var DeleteRequestTypeFailedErrorMsg = "Rest request to delete request type failed with status: 404"
+
+var RequestTypeEndPoint string = "/requesttype"
+
+var SampleRequestType = jiraservicedeskv1alpha1.RequestType{
+	Spec: jiraservicedeskv1alpha1.RequestTypeSpec{
+		Name:        "requesttype",
+		Description: "Report a problem with the sample service",
+		HelpText:    "Describe what you were doing when the problem occurred",
+		IssueTypeId: "10001",
+	},
+}
+
+var GetRequestTypeResponseJSON = map[string]interface{}{
+	"id":            RequestTypeID,
+	"name":          "Sample Request Type",
+	"description":   "Sample description",
+	"helpText":      "Sample help text",
+	"issueTypeId":   "10001",
+	"serviceDeskId": "1",
+	"groupIds":      []string{"2"},
+}
+
+var CreateRequestTypeInputJSON = map[string]interface{}{
+	"name":        "Sample Request Type",
+	"description": "Sample description",
+	"helpText":    "Sample help text",
+	"issueTypeId": "10001",
+	"groupIds":    []string{"2"},
+}
+
+var QueueID = "7"
+var QueueIDInt, _ = strconv.Atoi(QueueID)
+var QueueProjectKey = "SAMPLE"
+
+var GetQueueFailedErrorMsg = "Rest request to get queue failed with status: 404"
+var CreateQueueFailedErrorMsg = "Rest request to create queue failed with status: 400 and response: "
+var UpdateQueueFailedErrorMsg = "Rest request to update queue failed with status: 400 and response: "
+var DeleteQueueFailedErrorMsg = "Rest request to delete queue failed with status: 404"
+
+var QueueEndPoint string = "/queue"
+var InternalQueuesEndPoint string = "/queues"
+
+var SampleQueue = jiraservicedeskv1alpha1.Queue{
+	Spec: jiraservicedeskv1alpha1.QueueSpec{
+		Name:    "queue",
+		JQL:     "resolution = EMPTY AND assignee IS EMPTY",
+		Columns: []string{"issuekey", "summary", "status", "created"},
+		OrderBy: &jiraservicedeskv1alpha1.QueueOrderBy{
+			Field:     "created",
+			Direction: "DESC",
+		},
+	},
+}
+
+var GetQueueResponseJSON = map[string]interface{}{
+	"id":     QueueID,
+	"name":   "Sample Queue",
+	"jql":    "resolution = EMPTY ORDER BY created DESC",
+	"fields": []string{"issuekey", "summary"},
+}
+
+var CreateQueueInputJSON = map[string]interface{}{
+	"name":    "Sample Queue",
+	"jql":     "resolution = EMPTY ORDER BY created DESC",
+	"columns": []string{"issuekey", "summary"},
+}
+
+var CreateQueueResponseJSON = map[string]interface{}{
+	"id":   QueueIDInt,
+	"name": "Sample Queue",
+}
+
+var DeleteQueueRequestJSON = map[string]interface{}{
+	"deleted": []int{QueueIDInt},
+}
+
+var SLAID = "3"
+var SLAIDInt, _ = strconv.Atoi(SLAID)
+var SLAProjectKey = "SAMPLE"
+
+var GetSLAFailedErrorMsg = "Rest request to get SLA failed with status: 404"
+var CreateSLAFailedErrorMsg = "Rest request to create SLA failed with status: 400 and response: "
+var UpdateSLAFailedErrorMsg = "Rest request to update SLA failed with status: 400 and response: "
+var DeleteSLAFailedErrorMsg = "Rest request to delete SLA failed with status: 404"
+
+var SLAMetricsEndPoint string = "/sla/metrics"
+
+var SampleSLA = jiraservicedeskv1alpha1.SLA{
+	Spec: jiraservicedeskv1alpha1.SLASpec{
+		Name: "sla",
+		StartConditions: []jiraservicedeskv1alpha1.SLACondition{
+			{FactoryKey: "issue-created-sla-condition-factory", ConditionId: "issue-created-hit-condition"},
+		},
+		PauseConditions: []jiraservicedeskv1alpha1.SLACondition{
+			{FactoryKey: "status-sla-condition-factory", ConditionId: "10002"},
+		},
+		StopConditions: []jiraservicedeskv1alpha1.SLACondition{
+			{FactoryKey: "comment-sla-condition-factory", ConditionId: "comment-for-reporter-hit-condition"},
+		},
+		Goals: []jiraservicedeskv1alpha1.SLAGoal{
+			{JQL: "priority = Highest", Duration: metav1.Duration{Duration: time.Hour}},
+			{Duration: metav1.Duration{Duration: 8 * time.Hour}},
+		},
+	},
+}
+
+var SLARequestJSON = map[string]interface{}{
+	"name": "Time to first response",
+	"definition": map[string]interface{}{
+		"start": []map[string]string{{"factoryKey": "issue-created-sla-condition-factory", "conditionId": "issue-created-hit-condition"}},
+		"pause": []map[string]string{},
+		"stop":  []map[string]string{{"factoryKey": "comment-sla-condition-factory", "conditionId": "comment-for-reporter-hit-condition"}},
+	},
+	"goals": []map[string]interface{}{
+		{"jqlQuery": "priority = Highest", "duration": 3600000, "defaultGoal": false},
+		{"jqlQuery": "", "duration": 28800000, "defaultGoal": true},
+	},
+}
+
+var GetSLAResponseJSON = map[string]interface{}{
+	"id":         SLAIDInt,
+	"name":       "Time to first response",
+	"definition": SLARequestJSON["definition"],
+	"goals":      SLARequestJSON["goals"],
+}
+
+var ListServiceDesksResponseJSON = map[string]interface{}{
+	"size":       2,
+	"start":      0,
+	"limit":      50,
+	"isLastPage": true,
+	"values": []map[string]string{
+		{"id": "1", "projectId": "10001", "projectName": "Sample Project", "projectKey": "SAMPLE"},
+		{"id": "2", "projectId": "10002", "projectName": "Other Project", "projectKey": "OTHER"},
+	},
+}
+
+var ListCustomersResponseJSON = map[string]interface{}{
+	"size":       1,
+	"start":      0,
+	"limit":      50,
+	"isLastPage": true,
+	"values":     []map[string]string{GetCustomerResponseJSON},
+}
+
+var GetServiceDesksFailedErrorMsg = "Rest request to get service desks failed with status: 401"
+var GetCustomersFailedErrorMsg = "Rest request to get customers failed with status: 404"
+
+var AvatarID = "10010"
+
+// AvatarPNG starts with the signature of PNG images, which is how their content type is detected
+var AvatarPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+var AvatarSVG = []byte(`<?xml version="1.0" encoding="UTF-8"?><svg xmlns="http://www.w3.org/2000/svg" width="48" height="48"><circle cx="24" cy="24" r="24"/></svg>`)
+
+var UploadProjectAvatarResponseJSON = map[string]interface{}{
+	"id":             AvatarID,
+	"isSystemAvatar": false,
+	"isSelected":     false,
+	"isDeletable":    true,
+}
+
+var UploadProjectAvatarFailedErrorMsg = "Rest request to upload project avatar failed with status: 400 and response: "
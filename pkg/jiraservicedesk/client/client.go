@@ -2,11 +2,14 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync/atomic"
+	"time"
 
 	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -16,67 +19,205 @@ var Log = logf.Log.WithName("jiraServiceDeskClient")
 
 type Client interface {
 	// Methods for Project
-	GetProjectByIdentifier(identifier string) (Project, error)
+	GetProjectByIdentifier(ctx context.Context, identifier string) (Project, error)
+	GetDeclaredProject(ctx context.Context, identifier string, declaredProject Project) (Project, error)
 	GetProjectFromProjectCR(project *jiraservicedeskv1alpha1.Project) Project
 	GetProjectCRFromProject(project Project) jiraservicedeskv1alpha1.Project
-	CreateProject(project Project) (string, error)
-	DeleteProject(id string) error
-	UpdateProject(updatedProject Project, id string) error
+	CreateProject(ctx context.Context, project Project) (string, error)
+	DeleteProject(ctx context.Context, id string) error
+	UpdateProject(ctx context.Context, updatedProject Project, id string) error
 	ProjectEqual(oldProject Project, newProject Project) bool
+	ProjectDiff(oldProject Project, newProject Project) []string
 	GetProjectForUpdateRequest(existingProject Project, newProject *jiraservicedeskv1alpha1.Project) Project
-	UpdateProjectAccessPermissions(status bool, key string) error
-	GetCustomerById(customerAccountId string) (Customer, error)
-	GetCustomerIdByEmail(emailAddress string) (string, error)
-	CreateCustomer(customer Customer) (string, error)
-	CreateLegacyCustomer(email string, projectKey string) (string, error)
+	ProjectLeadExists(ctx context.Context, leadAccountId string) (bool, error)
+	GetProjectAccessPermissions(ctx context.Context, key string) (CustomerAccess, error)
+	UpdateProjectAccessPermissions(ctx context.Context, customerAccess CustomerAccess, key string) error
+	UploadProjectAvatar(ctx context.Context, projectIdOrKey string, contentType string, image []byte) (string, error)
+	SetProjectAvatar(ctx context.Context, projectIdOrKey string, avatarId string) error
+	DeleteProjectAvatar(ctx context.Context, projectIdOrKey string, avatarId string) error
+	GetServiceDeskProjectKeys(ctx context.Context) ([]string, error)
+	GetCustomerById(ctx context.Context, customerAccountId string) (Customer, error)
+	GetCustomerIdByEmail(ctx context.Context, emailAddress string) (string, error)
+	GetCustomersByProjectKey(ctx context.Context, projectKey string) ([]Customer, error)
+	GetCustomersByOrganizationId(ctx context.Context, organizationId string) ([]Customer, error)
+	CreateCustomer(ctx context.Context, customer Customer) (string, error)
+	CreateLegacyCustomer(ctx context.Context, email string, projectKey string) (string, error)
+	CanUpdateCustomer() bool
+	UpdateCustomer(ctx context.Context, customerAccountId string, customer Customer) error
 	IsCustomerUpdated(customer *jiraservicedeskv1alpha1.Customer, existingCustomer Customer) bool
-	AddCustomerToProject(customerAccountId string, projectKey string) error
-	RemoveCustomerFromProject(customerAccountId string, projectKey string) error
-	DeleteCustomer(customerAccountId string) error
+	CustomerDiff(customer *jiraservicedeskv1alpha1.Customer, existingCustomer Customer) []string
+	AddCustomerToProject(ctx context.Context, customerAccountId string, projectKey string) error
+	RemoveCustomerFromProject(ctx context.Context, customerAccountId string, projectKey string) error
+	DeleteCustomer(ctx context.Context, customerAccountId string) error
 	GetCustomerCRFromCustomer(customer Customer) jiraservicedeskv1alpha1.Customer
 	GetCustomerFromCustomerCRForCreateCustomer(customer *jiraservicedeskv1alpha1.Customer) Customer
+
+	// Methods for Organization
+	GetOrganizationById(ctx context.Context, organizationId string) (Organization, error)
+	GetOrganizationIdByName(ctx context.Context, name string) (string, error)
+	CreateOrganization(ctx context.Context, organization Organization) (string, error)
+	UpdateOrganization(ctx context.Context, organizationId string, organization Organization) error
+	DeleteOrganization(ctx context.Context, organizationId string) error
+	AddOrganizationToProject(ctx context.Context, organizationId string, projectKey string) error
+	RemoveOrganizationFromProject(ctx context.Context, organizationId string, projectKey string) error
+	AddCustomerToOrganization(ctx context.Context, customerAccountId string, organizationId string) error
+	RemoveCustomerFromOrganization(ctx context.Context, customerAccountId string, organizationId string) error
+	IsOrganizationUpdated(organization *jiraservicedeskv1alpha1.Organization, existingOrganization Organization) bool
+	OrganizationDiff(organization *jiraservicedeskv1alpha1.Organization, existingOrganization Organization) []string
+	GetOrganizationFromOrganizationCR(organization *jiraservicedeskv1alpha1.Organization) Organization
+
+	// Methods for RequestType
+	GetRequestTypeById(ctx context.Context, projectKey string, requestTypeId string) (RequestType, error)
+	CreateRequestType(ctx context.Context, projectKey string, requestType RequestType) (string, error)
+	DeleteRequestType(ctx context.Context, projectKey string, requestTypeId string) error
+	RequestTypeDiff(requestType *jiraservicedeskv1alpha1.RequestType, existingRequestType RequestType) []string
+	GetRequestTypeFromRequestTypeCR(requestType *jiraservicedeskv1alpha1.RequestType) RequestType
+
+	// Methods for Queue
+	GetQueueById(ctx context.Context, projectKey string, queueId string) (Queue, error)
+	CreateQueue(ctx context.Context, projectKey string, queue Queue) (string, error)
+	UpdateQueue(ctx context.Context, projectKey string, queueId string, queue Queue) error
+	DeleteQueue(ctx context.Context, projectKey string, queueId string) error
+	IsQueueUpdated(queue *jiraservicedeskv1alpha1.Queue, existingQueue Queue) bool
+	QueueDiff(queue *jiraservicedeskv1alpha1.Queue, existingQueue Queue) []string
+	GetQueueFromQueueCR(queue *jiraservicedeskv1alpha1.Queue) Queue
+
+	// Methods for SLA
+	GetSLAById(ctx context.Context, projectKey string, slaId string) (SLA, error)
+	CreateSLA(ctx context.Context, projectKey string, sla SLA) (string, error)
+	UpdateSLA(ctx context.Context, projectKey string, slaId string, sla SLA) error
+	DeleteSLA(ctx context.Context, projectKey string, slaId string) error
+	IsSLAUpdated(sla *jiraservicedeskv1alpha1.SLA, existingSLA SLA) bool
+	SLADiff(sla *jiraservicedeskv1alpha1.SLA, existingSLA SLA) []string
+	GetSLAFromSLACR(sla *jiraservicedeskv1alpha1.SLA) SLA
+
+	// Methods for Credentials
+	SetSite(site Site)
+	WithSite(site Site) Client
 }
 
 // Client wraps http client
 type jiraServiceDeskClient struct {
-	apiToken   string
-	baseURL    string
-	email      string
-	httpClient *http.Client
+	site        atomic.Value
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	rateLimit   RateLimit
+	timeout     time.Duration
 }
 
-// NewClient creates an API client
-func NewClient(apiToken string, baseURL string, email string) Client {
-	return &jiraServiceDeskClient{
-		apiToken:   apiToken,
-		baseURL:    baseURL,
-		email:      email,
-		httpClient: http.DefaultClient,
+// DefaultTimeout of a call to the JSD api, used by clients that are not given a timeout
+const DefaultTimeout = 2 * time.Minute
+
+// Option configures an API client
+type Option func(*jiraServiceDeskClient)
+
+// WithRetryPolicy sets the policy for retrying rate limited and failed requests
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *jiraServiceDeskClient) {
+		c.retryPolicy = policy
 	}
 }
 
-func (c *jiraServiceDeskClient) newRequest(method, path string, body interface{}, experimental bool) (*http.Request, error) {
-	endpoint := c.baseURL + path
-	url, err := url.Parse(endpoint)
+// WithRateLimit limits the requests sent to a Jira site. The limit is shared by all clients for the same site
+// that are configured with the same limit, including the clients returned by WithSite
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *jiraServiceDeskClient) {
+		c.rateLimit = limit
+	}
+}
+
+// WithTimeout limits the time of a call to the JSD api, including its retries. A timeout of 0 disables the limit,
+// leaving only the deadline of the context passed to the call
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *jiraServiceDeskClient) {
+		c.timeout = timeout
+	}
+}
+
+// NewClient creates an API client authenticating with the email of an Atlassian account and its api token
+func NewClient(apiToken string, baseURL string, email string, options ...Option) Client {
+	return NewClientWithAuth(baseURL, BasicAuth{Email: email, APIToken: apiToken}, options...)
+}
+
+// NewClientWithAuth creates an API client for a Jira Cloud site
+func NewClientWithAuth(baseURL string, auth Authenticator, options ...Option) Client {
+	return NewSiteClient(Site{BaseURL: baseURL, Auth: auth}, options...)
+}
+
+// NewSiteClient creates an API client, which retries requests using the DefaultRetryPolicy and times out
+// after the DefaultTimeout unless configured otherwise
+func NewSiteClient(site Site, options ...Option) Client {
+	client := &jiraServiceDeskClient{
+		retryPolicy: DefaultRetryPolicy,
+		timeout:     DefaultTimeout,
+	}
+	for _, option := range options {
+		option(client)
+	}
+
+	// Every retry waits for the rate limiter again
+	client.httpClient = &http.Client{
+		Transport: &retryTransport{
+			base:   &rateLimitTransport{limit: client.rateLimit},
+			policy: client.retryPolicy,
+		},
+		Timeout: client.timeout,
+	}
+	client.SetSite(site)
+	return client
+}
+
+// SetSite atomically replaces the site and credentials used for subsequent requests
+func (c *jiraServiceDeskClient) SetSite(site Site) {
+	c.site.Store(&site)
+}
+
+// WithSite returns a new client for the given site and credentials, which is configured like this client
+func (c *jiraServiceDeskClient) WithSite(site Site) Client {
+	client := &jiraServiceDeskClient{
+		httpClient:  c.httpClient,
+		retryPolicy: c.retryPolicy,
+		rateLimit:   c.rateLimit,
+		timeout:     c.timeout,
+	}
+	client.SetSite(site)
+	return client
+}
+
+// adapter returns the api adapter for the flavour of the current site
+func (c *jiraServiceDeskClient) adapter() apiAdapter {
+	return c.site.Load().(*Site).adapter()
+}
+
+func (c *jiraServiceDeskClient) newRequest(ctx context.Context, method, path string, body interface{}, experimental bool) (*http.Request, error) {
+	if body == nil {
+		return c.newRequestWithContent(ctx, method, path, nil, "", experimental)
+	}
+
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(body)
 	if err != nil {
 		return nil, err
 	}
+	return c.newRequestWithContent(ctx, method, path, buf, "application/json", experimental)
+}
 
-	var buf io.ReadWriter
+// newRequestWithContent creates a request whose body is sent as it is, e.g. an image
+func (c *jiraServiceDeskClient) newRequestWithContent(ctx context.Context, method, path string, body io.Reader, contentType string, experimental bool) (*http.Request, error) {
+	site := c.site.Load().(*Site)
 
-	if body != nil {
-		buf = new(bytes.Buffer)
-		err := json.NewEncoder(buf).Encode(body)
-		if err != nil {
-			return nil, err
-		}
+	endpoint := site.BaseURL + path
+	url, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
 	}
-	req, err := http.NewRequest(method, url.String(), buf)
+
+	req, err := http.NewRequestWithContext(ctx, method, url.String(), body)
 	if err != nil {
 		return nil, err
 	}
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "golang httpClient")
@@ -85,14 +226,20 @@ func (c *jiraServiceDeskClient) newRequest(method, path string, body interface{}
 		req.Header.Set("X-ExperimentalApi", "opt-in")
 	}
 
-	req.SetBasicAuth(c.email, c.apiToken)
+	// Access tokens are refreshed here if they have expired
+	err = site.Auth.Authenticate(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error authenticating the request: %w", err)
+	}
 	return req, nil
 }
 
 func (c *jiraServiceDeskClient) do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	observeRequest(req, resp, err, start)
 	if err != nil {
-		return resp, fmt.Errorf("Error calling the API endpoint: %v", err)
+		return resp, fmt.Errorf("Error calling the API endpoint: %w", err)
 	}
 
 	return resp, nil
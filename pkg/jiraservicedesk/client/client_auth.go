@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// DefaultOAuth2TokenURL is the token endpoint of Atlassian Cloud
+const DefaultOAuth2TokenURL = "https://auth.atlassian.com/oauth/token"
+
+// OAuth2TokenTimeout of a call to the token endpoint
+const OAuth2TokenTimeout = 30 * time.Second
+
+// Authenticator adds the credentials of a Jira site to requests
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// BasicAuth authenticates with the email of an Atlassian account and its api token
+type BasicAuth struct {
+	Email    string
+	APIToken string
+}
+
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Email, a.APIToken)
+	return nil
+}
+
+// BearerAuth authenticates with a personal access token or a service account token
+type BearerAuth struct {
+	Token string
+}
+
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// oauth2Auth authenticates with OAuth 2.0 access tokens, which are refreshed when they expire
+type oauth2Auth struct {
+	tokenSource oauth2.TokenSource
+}
+
+// NewOAuth2ClientCredentialsAuth authenticates with access tokens obtained through the client credentials grant
+func NewOAuth2ClientCredentialsAuth(clientId string, clientSecret string, tokenURL string) Authenticator {
+	config := clientcredentials.Config{
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		TokenURL:     getTokenURL(tokenURL),
+	}
+	return &oauth2Auth{tokenSource: config.TokenSource(newOAuth2Context())}
+}
+
+// RefreshTokenRotatedFunc persists a refresh token that was rotated by the authorization server. If it fails,
+// it is called again the next time the rotated token is used
+type RefreshTokenRotatedFunc func(refreshToken string) error
+
+// NewOAuth2RefreshTokenAuth authenticates with access tokens obtained through the refresh token of an
+// OAuth 2.0 (3LO) app. Atlassian rotates refresh tokens, so rotated tokens are passed to onRotated, which
+// may be nil if they only need to be kept in memory
+func NewOAuth2RefreshTokenAuth(clientId string, clientSecret string, refreshToken string, tokenURL string, onRotated RefreshTokenRotatedFunc) Authenticator {
+	config := oauth2.Config{
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  getTokenURL(tokenURL),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+	return &oauth2Auth{tokenSource: &rotatingTokenSource{
+		tokenSource:  config.TokenSource(newOAuth2Context(), &oauth2.Token{RefreshToken: refreshToken}),
+		refreshToken: refreshToken,
+		onRotated:    onRotated,
+	}}
+}
+
+// newOAuth2Context returns the context of calls to the token endpoint. Token sources outlive the reconciles
+// that use them, so the calls are bounded by the timeout of their http client instead
+func newOAuth2Context() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: OAuth2TokenTimeout})
+}
+
+// rotatingTokenSource passes refresh tokens rotated by the authorization server to a RefreshTokenRotatedFunc
+type rotatingTokenSource struct {
+	tokenSource oauth2.TokenSource
+	onRotated   RefreshTokenRotatedFunc
+
+	lock sync.Mutex
+	// Last refresh token that was persisted
+	refreshToken string
+}
+
+func (s *rotatingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(token.RefreshToken) > 0 && token.RefreshToken != s.refreshToken {
+		if s.onRotated != nil {
+			if err := s.onRotated(token.RefreshToken); err != nil {
+				// The access token is still valid, so only the persisting of the refresh token is retried
+				Log.Error(err, "Unable to persist rotated OAuth 2.0 refresh token")
+				return token, nil
+			}
+		}
+		s.refreshToken = token.RefreshToken
+	}
+
+	return token, nil
+}
+
+func (a *oauth2Auth) Authenticate(req *http.Request) error {
+	token, err := a.tokenSource.Token()
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+func getTokenURL(tokenURL string) string {
+	if len(tokenURL) == 0 {
+		return DefaultOAuth2TokenURL
+	}
+	return tokenURL
+}
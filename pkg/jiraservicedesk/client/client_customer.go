@@ -1,11 +1,13 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"io/ioutil"
 	"reflect"
 	"strconv"
+	"strings"
 
 	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 )
@@ -18,6 +20,10 @@ const (
 	LegacyCustomerApiPath        = "/rest/servicedesk/1/pages/people/customers/pagination/"
 	LegacyCustomerCreateEndpoint = "/invite"
 	SearchUserEndpoint           = "/rest/api/3/user/search?query="
+	ServiceDeskCustomerApiPath   = "/customer"
+
+	// Page size used while listing customers of a service desk
+	CustomerPageLimit = 50
 )
 
 type Customer struct {
@@ -27,8 +33,15 @@ type Customer struct {
 	ProjectKeys []string `json:"projectKeys,omitempty"`
 }
 
+// CustomerUpdateRequestBody changes the email and name of a user account on Jira Data Center
+type CustomerUpdateRequestBody struct {
+	EmailAddress string `json:"emailAddress,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+}
+
 type CustomerCreateResponse struct {
 	AccountId    string `json:"accountId,omitempty"`
+	Name         string `json:"name,omitempty"`
 	EmailAddress string `json:"emailAddress,omitempty"`
 	DisplayName  string `json:"displayName,omitempty"`
 }
@@ -38,8 +51,10 @@ type CustomerAddResponse struct {
 }
 
 type CustomerGetResponse struct {
-	Self         string `json:"self,omitempty"`
-	AccountId    string `json:"accountId,omitempty"`
+	Self      string `json:"self,omitempty"`
+	AccountId string `json:"accountId,omitempty"`
+	// Username of the user on Jira Data Center
+	Name         string `json:"name,omitempty"`
 	EmailAddress string `json:"emailAddress,omitempty"`
 	DisplayName  string `json:"displayName,omitempty"`
 	AccountType  string `json:"accountType,omitempty"`
@@ -47,6 +62,14 @@ type CustomerGetResponse struct {
 
 type CustomerGetByEmailResponse []CustomerGetResponse
 
+type CustomerListResponse struct {
+	Size       int                   `json:"size,omitempty"`
+	Start      int                   `json:"start,omitempty"`
+	Limit      int                   `json:"limit,omitempty"`
+	IsLastPage bool                  `json:"isLastPage,omitempty"`
+	Values     []CustomerGetResponse `json:"values,omitempty"`
+}
+
 type LegacyCustomerRequestBody struct {
 	Emails []string `json:"emails,omitempty"`
 }
@@ -63,10 +86,11 @@ type LegacyCustomerSuccessResponse struct {
 }
 
 // GetCustomerById gets a customer by ID from JSD
-func (c *jiraServiceDeskClient) GetCustomerById(customerAccountId string) (Customer, error) {
+func (c *jiraServiceDeskClient) GetCustomerById(ctx context.Context, customerAccountId string) (Customer, error) {
 	var customer Customer
+	adapter := c.adapter()
 
-	request, err := c.newRequest("GET", EndpointUser+customerAccountId, nil, false)
+	request, err := c.newRequest(ctx, "GET", adapter.userPath(customerAccountId), nil, false)
 	if err != nil {
 		return customer, err
 	}
@@ -78,7 +102,7 @@ func (c *jiraServiceDeskClient) GetCustomerById(customerAccountId string) (Custo
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err := errors.New("Rest request to get customer failed with status: " + strconv.Itoa(response.StatusCode))
+		err := newAPIError("get customer", response, nil)
 		return customer, err
 	}
 
@@ -89,13 +113,16 @@ func (c *jiraServiceDeskClient) GetCustomerById(customerAccountId string) (Custo
 	}
 
 	customer = customerGetResponseToCustomerMapper(responseObject)
+	customer.AccountId = adapter.userId(responseObject.AccountId, responseObject.Name)
 
 	return customer, err
 }
 
 // GetCustomerById gets a customer by ID from JSD
-func (c *jiraServiceDeskClient) GetCustomerIdByEmail(emailAddress string) (string, error) {
-	request, err := c.newRequest("GET", SearchUserEndpoint+emailAddress, nil, false)
+func (c *jiraServiceDeskClient) GetCustomerIdByEmail(ctx context.Context, emailAddress string) (string, error) {
+	adapter := c.adapter()
+
+	request, err := c.newRequest(ctx, "GET", adapter.userSearchPath(emailAddress), nil, false)
 	if err != nil {
 		return "", err
 	}
@@ -107,7 +134,7 @@ func (c *jiraServiceDeskClient) GetCustomerIdByEmail(emailAddress string) (strin
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err := errors.New("Rest request to get customer failed with status: " + strconv.Itoa(response.StatusCode))
+		err := newAPIError("get customer", response, nil)
 		return "", err
 	}
 
@@ -118,15 +145,17 @@ func (c *jiraServiceDeskClient) GetCustomerIdByEmail(emailAddress string) (strin
 	}
 
 	if len(responseObject) > 0 {
-		return responseObject[0].AccountId, err
+		return adapter.userId(responseObject[0].AccountId, responseObject[0].Name), err
 	}
 
 	return "", err
 }
 
 // CreateCustomer create a new customer on JSD
-func (c *jiraServiceDeskClient) CreateCustomer(customer Customer) (string, error) {
-	request, err := c.newRequest("POST", CreateCustomerApiPath, customer, false)
+func (c *jiraServiceDeskClient) CreateCustomer(ctx context.Context, customer Customer) (string, error) {
+	adapter := c.adapter()
+
+	request, err := c.newRequest(ctx, "POST", CreateCustomerApiPath, adapter.customerRequestBody(customer), false)
 	if err != nil {
 		return "", err
 	}
@@ -140,8 +169,7 @@ func (c *jiraServiceDeskClient) CreateCustomer(customer Customer) (string, error
 	responseData, _ := ioutil.ReadAll(response.Body)
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err = errors.New("Rest request to create customer failed with status: " + strconv.Itoa(response.StatusCode) +
-			" and response: " + string(responseData))
+		err = newAPIError("create customer", response, responseData)
 		return "", err
 	}
 
@@ -151,16 +179,58 @@ func (c *jiraServiceDeskClient) CreateCustomer(customer Customer) (string, error
 		return "", err
 	}
 
-	return responseObject.AccountId, err
+	return adapter.userId(responseObject.AccountId, responseObject.Name), err
+}
+
+// CanUpdateCustomer reports whether the email and name of customers can be changed on the site. Accounts of
+// Jira Cloud are managed by Atlassian, so a customer with a new email needs a new account there
+func (c *jiraServiceDeskClient) CanUpdateCustomer() bool {
+	return c.adapter().supportsUserUpdate()
+}
+
+// UpdateCustomer changes the email and name of a customer on JSD
+func (c *jiraServiceDeskClient) UpdateCustomer(ctx context.Context, customerAccountId string, customer Customer) error {
+	adapter := c.adapter()
+	if !adapter.supportsUserUpdate() {
+		return fmt.Errorf("Updating customers is not supported by Jira Cloud")
+	}
+
+	updateCustomerBody := CustomerUpdateRequestBody{
+		EmailAddress: customer.Email,
+		DisplayName:  customer.DisplayName,
+	}
+
+	request, err := c.newRequest(ctx, "PUT", adapter.userPath(customerAccountId), updateCustomerBody, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("update customer", response, responseData)
+		return err
+	}
+
+	return nil
 }
 
 // CreateLegacyCustomer create a customer on JSD using the legacy api endpoint
-func (c *jiraServiceDeskClient) CreateLegacyCustomer(customerEmail string, projectKey string) (string, error) {
+func (c *jiraServiceDeskClient) CreateLegacyCustomer(ctx context.Context, customerEmail string, projectKey string) (string, error) {
+	if !c.adapter().supportsLegacyCustomers() {
+		return "", fmt.Errorf("Legacy customers are not supported by Jira Data Center")
+	}
+
 	legacyCustomerRequestBody := LegacyCustomerRequestBody{
 		Emails: []string{customerEmail},
 	}
 
-	request, err := c.newRequest("POST", LegacyCustomerApiPath+projectKey+LegacyCustomerCreateEndpoint, legacyCustomerRequestBody, false)
+	request, err := c.newRequest(ctx, "POST", LegacyCustomerApiPath+projectKey+LegacyCustomerCreateEndpoint, legacyCustomerRequestBody, false)
 	if err != nil {
 		return "", err
 	}
@@ -173,8 +243,7 @@ func (c *jiraServiceDeskClient) CreateLegacyCustomer(customerEmail string, proje
 	responseData, _ := ioutil.ReadAll(response.Body)
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err = errors.New("Rest request to create legacy customer failed with status: " + strconv.Itoa(response.StatusCode) +
-			" and response: " + string(responseData))
+		err = newAPIError("create legacy customer", response, responseData)
 		return "", err
 	}
 
@@ -188,12 +257,10 @@ func (c *jiraServiceDeskClient) CreateLegacyCustomer(customerEmail string, proje
 }
 
 // AddCustomerToProject adds a customer to a JSD project
-func (c *jiraServiceDeskClient) AddCustomerToProject(customerAccountId string, projectKey string) error {
-	addCustomerBody := CustomerAddResponse{
-		AccountIds: []string{customerAccountId},
-	}
+func (c *jiraServiceDeskClient) AddCustomerToProject(ctx context.Context, customerAccountId string, projectKey string) error {
+	addCustomerBody := c.adapter().usersRequestBody([]string{customerAccountId})
 
-	request, err := c.newRequest("POST", AddCustomerApiPath+projectKey+"/customer", addCustomerBody, false)
+	request, err := c.newRequest(ctx, "POST", AddCustomerApiPath+projectKey+"/customer", addCustomerBody, false)
 	if err != nil {
 		return err
 	}
@@ -206,7 +273,7 @@ func (c *jiraServiceDeskClient) AddCustomerToProject(customerAccountId string, p
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err = errors.New("Rest request to add Customer failed with status: " + strconv.Itoa(response.StatusCode))
+		err = newAPIError("add Customer", response, nil)
 		return err
 	}
 
@@ -214,20 +281,93 @@ func (c *jiraServiceDeskClient) AddCustomerToProject(customerAccountId string, p
 }
 
 func (c *jiraServiceDeskClient) IsCustomerUpdated(customer *jiraservicedeskv1alpha1.Customer, existingCustomer Customer) bool {
-	if reflect.DeepEqual(customer.Spec.Projects, customer.Status.AssociatedProjects) && customer.Spec.Email == existingCustomer.Email {
+	if reflect.DeepEqual(customer.Spec.Projects, customer.Status.AssociatedProjects) &&
+		reflect.DeepEqual(customer.Spec.Organizations, customer.Status.AssociatedOrganizations) &&
+		strings.EqualFold(customer.Spec.Email, existingCustomer.Email) {
 		return false
 	} else {
 		return true
 	}
 }
 
-// RemoveCustomerFromProject removes a customer from JSD project
-func (c *jiraServiceDeskClient) RemoveCustomerFromProject(customerAccountId string, projectKey string) error {
-	removeCustomerBody := CustomerAddResponse{
-		AccountIds: []string{customerAccountId},
+// GetCustomersByProjectKey lists the customers of the service desk of a JSD project
+func (c *jiraServiceDeskClient) GetCustomersByProjectKey(ctx context.Context, projectKey string) ([]Customer, error) {
+	return c.listCustomers(ctx, AddCustomerApiPath+projectKey+ServiceDeskCustomerApiPath, true, "get customers")
+}
+
+// GetCustomersByOrganizationId lists the users of a JSD organization
+func (c *jiraServiceDeskClient) GetCustomersByOrganizationId(ctx context.Context, organizationId string) ([]Customer, error) {
+	return c.listCustomers(ctx, OrganizationApiPath+"/"+organizationId+OrganizationUserApiPath, false, "get organization users")
+}
+
+// listCustomers reads all pages of a paginated list of customers
+func (c *jiraServiceDeskClient) listCustomers(ctx context.Context, path string, experimental bool, operation string) ([]Customer, error) {
+	var customers []Customer
+	adapter := c.adapter()
+
+	start := 0
+	for {
+		request, err := c.newRequest(ctx, "GET", path+"?start="+strconv.Itoa(start)+"&limit="+strconv.Itoa(CustomerPageLimit), nil, experimental)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := c.do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			err := newAPIError(operation, response, nil)
+			response.Body.Close()
+			return nil, err
+		}
+
+		var responseObject CustomerListResponse
+		err = json.NewDecoder(response.Body).Decode(&responseObject)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, customer := range responseObject.Values {
+			mappedCustomer := customerGetResponseToCustomerMapper(customer)
+			mappedCustomer.AccountId = adapter.userId(customer.AccountId, customer.Name)
+			customers = append(customers, mappedCustomer)
+		}
+
+		if responseObject.IsLastPage || len(responseObject.Values) == 0 {
+			return customers, nil
+		}
+		start += len(responseObject.Values)
 	}
+}
+
+// CustomerDiff lists the spec fields of a customer that differ from the customer on JSD
+func (c *jiraServiceDeskClient) CustomerDiff(customer *jiraservicedeskv1alpha1.Customer, existingCustomer Customer) []string {
+	var diff []string
+
+	// It takes a few seconds for customers to be persisted at JSD, so pending customers can not be compared yet
+	if existingCustomer.DisplayName == "User " && existingCustomer.Email == "?" {
+		return diff
+	}
+
+	if !strings.EqualFold(customer.Spec.Email, existingCustomer.Email) {
+		diff = append(diff, "email")
+	}
+	// Legacy customers choose their own name while signing up
+	if !customer.Spec.LegacyCustomer && customer.Spec.Name != existingCustomer.DisplayName {
+		diff = append(diff, "name")
+	}
+
+	return diff
+}
+
+// RemoveCustomerFromProject removes a customer from JSD project
+func (c *jiraServiceDeskClient) RemoveCustomerFromProject(ctx context.Context, customerAccountId string, projectKey string) error {
+	removeCustomerBody := c.adapter().usersRequestBody([]string{customerAccountId})
 
-	request, err := c.newRequest("DELETE", AddCustomerApiPath+projectKey+"/customer", removeCustomerBody, true)
+	request, err := c.newRequest(ctx, "DELETE", AddCustomerApiPath+projectKey+"/customer", removeCustomerBody, true)
 	if err != nil {
 		return err
 	}
@@ -240,7 +380,7 @@ func (c *jiraServiceDeskClient) RemoveCustomerFromProject(customerAccountId stri
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err = errors.New("Rest request to remove Customer failed with status: " + strconv.Itoa(response.StatusCode))
+		err = newAPIError("remove Customer", response, nil)
 		return err
 	}
 
@@ -248,8 +388,8 @@ func (c *jiraServiceDeskClient) RemoveCustomerFromProject(customerAccountId stri
 }
 
 // Delete customer deletes a customer from JSD
-func (c *jiraServiceDeskClient) DeleteCustomer(customerAccountId string) error {
-	request, err := c.newRequest("DELETE", EndpointUser+customerAccountId, nil, false)
+func (c *jiraServiceDeskClient) DeleteCustomer(ctx context.Context, customerAccountId string) error {
+	request, err := c.newRequest(ctx, "DELETE", c.adapter().userPath(customerAccountId), nil, false)
 	if err != nil {
 		return err
 	}
@@ -262,7 +402,7 @@ func (c *jiraServiceDeskClient) DeleteCustomer(customerAccountId string) error {
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err = errors.New("Rest request to delete Customer failed with status: " + strconv.Itoa(response.StatusCode))
+		err = newAPIError("delete Customer", response, nil)
 		return err
 	}
 
@@ -1,7 +1,8 @@
 package client
 
 import (
-	"errors"
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/nbio/st"
@@ -19,7 +20,7 @@ func TestJiraClient_GetCustomerById_shouldGetCustomer_whenValidCustomerAccountId
 		JSON(mockData.GetCustomerResponseJSON)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	customer, err := jiraClient.GetCustomerById(mockData.CustomerAccountId)
+	customer, err := jiraClient.GetCustomerById(context.TODO(), mockData.CustomerAccountId)
 
 	st.Expect(t, customer.AccountId, mockData.GetCustomerResponse.AccountId)
 	st.Expect(t, customer.DisplayName, mockData.GetCustomerResponse.DisplayName)
@@ -37,13 +38,13 @@ func TestJiraClient_GetCustomerById_shouldNotGetCustomer_whenInValidCustomerAcco
 		Reply(400)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	customer, err := jiraClient.GetCustomerById(mockData.CustomerAccountId)
+	customer, err := jiraClient.GetCustomerById(context.TODO(), mockData.CustomerAccountId)
 
 	st.Expect(t, customer.AccountId, "")
 	st.Expect(t, customer.DisplayName, "")
 	st.Expect(t, customer.Email, "")
 
-	st.Expect(t, err, errors.New(mockData.GetCustomerFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.GetCustomerFailedErrorMsg)
 	st.Expect(t, gock.IsDone(), true)
 }
 
@@ -63,7 +64,7 @@ func TestJiraClient_CreateCustomer_shouldCreateCustomer_whenValidCustomerDataIsG
 	}
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	id, err := jiraClient.CreateCustomer(sampleCustomer)
+	id, err := jiraClient.CreateCustomer(context.TODO(), sampleCustomer)
 
 	st.Expect(t, id, mockData.CustomerAccountId)
 	st.Expect(t, err, nil)
@@ -82,10 +83,10 @@ func TestJiraClient_CreateCustomer_shouldNotCreateCustomer_whenInValidCustomerDa
 	}
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	id, err := jiraClient.CreateCustomer(sampleCustomer)
+	id, err := jiraClient.CreateCustomer(context.TODO(), sampleCustomer)
 
 	st.Expect(t, id, "")
-	st.Expect(t, err, errors.New(mockData.CreateCustomerFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.CreateCustomerFailedErrorMsg)
 
 	st.Expect(t, gock.IsDone(), true)
 }
@@ -99,7 +100,7 @@ func TestJiraClient_AddCustomerToProject_shouldAddCustomerToProject_whenValidPro
 		Reply(201)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.AddCustomerToProject(mockData.CustomerAccountId, mockData.AddProjectKey)
+	err := jiraClient.AddCustomerToProject(context.TODO(), mockData.CustomerAccountId, mockData.AddProjectKey)
 
 	st.Expect(t, err, nil)
 
@@ -113,9 +114,9 @@ func TestJiraClient_AddCustomerToProject_shouldNotAddCustomerToProject_whenInVal
 		Reply(400)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.AddCustomerToProject(mockData.CustomerAccountId, mockData.AddProjectKey)
+	err := jiraClient.AddCustomerToProject(context.TODO(), mockData.CustomerAccountId, mockData.AddProjectKey)
 
-	st.Expect(t, err, errors.New(mockData.AddCustomerFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.AddCustomerFailedErrorMsg)
 
 	st.Expect(t, gock.IsDone(), true)
 }
@@ -127,7 +128,7 @@ func TestJiraClient_RemoveCustomerFromProject_shouldRemoveCustomerFromProject_wh
 		Reply(201)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.RemoveCustomerFromProject(mockData.CustomerAccountId, mockData.RemoveProjectKey)
+	err := jiraClient.RemoveCustomerFromProject(context.TODO(), mockData.CustomerAccountId, mockData.RemoveProjectKey)
 
 	st.Expect(t, err, nil)
 
@@ -141,9 +142,9 @@ func TestJiraClient_RemoveCustomerFromProject_shouldNotRemoveCustomerFromProject
 		Reply(400)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.RemoveCustomerFromProject(mockData.CustomerAccountId, mockData.RemoveProjectKey)
+	err := jiraClient.RemoveCustomerFromProject(context.TODO(), mockData.CustomerAccountId, mockData.RemoveProjectKey)
 
-	st.Expect(t, err, errors.New(mockData.RemoveCustomerFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.RemoveCustomerFailedErrorMsg)
 
 	st.Expect(t, gock.IsDone(), true)
 }
@@ -156,7 +157,7 @@ func TestJiraClient_DeleteCustomer_shouldDeleteCustomer_whenValidCustomerIsGiven
 		Reply(200)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.DeleteCustomer(mockData.CustomerAccountId)
+	err := jiraClient.DeleteCustomer(context.TODO(), mockData.CustomerAccountId)
 
 	st.Expect(t, err, nil)
 
@@ -170,9 +171,105 @@ func TestJiraClient_DeleteCustomer_shouldNotDeleteCustomer_whenInvalidCustomerIs
 		Reply(400)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.DeleteCustomer(mockData.CustomerAccountId)
+	err := jiraClient.DeleteCustomer(context.TODO(), mockData.CustomerAccountId)
 
-	st.Expect(t, err, errors.New(mockData.DeleteCustomerFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.DeleteCustomerFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CustomerDiff_shouldListChangedFields_whenCustomerDiffers(t *testing.T) {
+	customer := mockData.SampleCustomer.DeepCopy()
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	existingCustomer := Customer{
+		DisplayName: customer.Spec.Name,
+		Email:       strings.ToUpper(customer.Spec.Email),
+	}
+	st.Expect(t, len(jiraClient.CustomerDiff(customer, existingCustomer)), 0)
+
+	existingCustomer.DisplayName = "Changed Name"
+	existingCustomer.Email = "changed@sample.com"
+	st.Expect(t, jiraClient.CustomerDiff(customer, existingCustomer), []string{"email", "name"})
+
+	customer.Spec.LegacyCustomer = true
+	st.Expect(t, jiraClient.CustomerDiff(customer, existingCustomer), []string{"email"})
+
+	// Customers that are not persisted yet can not drift
+	pendingCustomer := Customer{DisplayName: "User ", Email: "?"}
+	st.Expect(t, len(jiraClient.CustomerDiff(customer, pendingCustomer)), 0)
+}
+
+func TestJiraClient_IsCustomerUpdated_shouldIgnoreCaseOfEmail_whenMembershipsAreReconciled(t *testing.T) {
+	customer := mockData.SampleCustomer.DeepCopy()
+	customer.Spec.Email = "Sample.Customer@Sample.com"
+	customer.Status.AssociatedProjects = customer.Spec.Projects
+	customer.Status.AssociatedOrganizations = customer.Spec.Organizations
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	existingCustomer := Customer{
+		DisplayName: customer.Spec.Name,
+		Email:       "sample.customer@sample.com",
+	}
+	st.Expect(t, jiraClient.IsCustomerUpdated(customer, existingCustomer), false)
+
+	existingCustomer.Email = "changed@sample.com"
+	st.Expect(t, jiraClient.IsCustomerUpdated(customer, existingCustomer), true)
+}
+
+func TestJiraClient_GetCustomersByProjectKey_shouldListCustomers_whenValidProjectIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+AddCustomerApiPath+"SAMPLE").
+		Get(ServiceDeskCustomerApiPath).
+		MatchParam("start", "0").
+		Reply(200).
+		JSON(mockData.ListCustomersResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	customers, err := jiraClient.GetCustomersByProjectKey(context.TODO(), "SAMPLE")
+
+	st.Expect(t, len(customers), 1)
+	st.Expect(t, customers[0].AccountId, mockData.GetCustomerResponse.AccountId)
+	st.Expect(t, customers[0].Email, mockData.GetCustomerResponse.Email)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetCustomersByOrganizationId_shouldListUsers_whenValidOrganizationIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+OrganizationApiPath+"/"+mockData.OrganizationID).
+		Get(OrganizationUserApiPath).
+		MatchParam("start", "0").
+		Reply(200).
+		JSON(mockData.ListCustomersResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	customers, err := jiraClient.GetCustomersByOrganizationId(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, len(customers), 1)
+	st.Expect(t, customers[0].AccountId, mockData.GetCustomerResponse.AccountId)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetCustomersByProjectKey_shouldNotListCustomers_whenInValidProjectIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + "INVALID").
+		Get(ServiceDeskCustomerApiPath).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	customers, err := jiraClient.GetCustomersByProjectKey(context.TODO(), "INVALID")
+
+	st.Expect(t, len(customers), 0)
+	st.Expect(t, err.Error(), mockData.GetCustomersFailedErrorMsg)
 
 	st.Expect(t, gock.IsDone(), true)
 }
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marker of the messages JSD responds with when an object with the same name or key already exists
+const alreadyExistsMessage = "already exists"
+
+// APIError is returned when JSD responds to a request with a non-2xx status
+type APIError struct {
+	// Operation that failed, e.g. "create Project"
+	Operation string
+	// Method and path of the request
+	Endpoint   string
+	StatusCode int
+	// Id of the request on the Jira site, to correlate the failure with the logs of Atlassian support
+	RequestId string
+	// General error messages returned by Jira
+	ErrorMessages []string
+	// Error messages by field returned by Jira
+	Errors map[string]string
+	// Body of the response
+	Response string
+
+	// Whether the body is part of the error message, if Jira did not return any structured errors
+	withResponse bool
+}
+
+// errorResponse holds the error formats of the Jira platform and the service desk apis
+type errorResponse struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+	ErrorMessage  string            `json:"errorMessage"`
+}
+
+// newAPIError builds the error for a failed response. The body of the response is read unless it is passed
+// as responseData, in which case it is also part of the error message if Jira did not return structured errors
+func newAPIError(operation string, response *http.Response, responseData []byte) error {
+	apiErr := &APIError{
+		Operation:    operation,
+		StatusCode:   response.StatusCode,
+		RequestId:    response.Header.Get("X-Arequestid"),
+		withResponse: responseData != nil,
+	}
+	if response.Request != nil {
+		apiErr.Endpoint = response.Request.Method + " " + response.Request.URL.Path
+	}
+
+	if responseData == nil && response.Body != nil {
+		responseData, _ = ioutil.ReadAll(response.Body)
+	}
+	apiErr.Response = string(responseData)
+
+	var errorResponse errorResponse
+	if json.Unmarshal(responseData, &errorResponse) == nil {
+		apiErr.ErrorMessages = errorResponse.ErrorMessages
+		if len(errorResponse.ErrorMessage) > 0 {
+			apiErr.ErrorMessages = append(apiErr.ErrorMessages, errorResponse.ErrorMessage)
+		}
+		apiErr.Errors = errorResponse.Errors
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	message := "Rest request to " + e.Operation + " failed with status: " + strconv.Itoa(e.StatusCode)
+
+	if details := e.details(); len(details) > 0 {
+		return message + " and errors: " + strings.Join(details, "; ")
+	}
+	if e.withResponse {
+		return message + " and response: " + e.Response
+	}
+	return message
+}
+
+// details lists the error messages followed by the field errors, sorted by field
+func (e *APIError) details() []string {
+	details := append([]string{}, e.ErrorMessages...)
+
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		details = append(details, field+": "+e.Errors[field])
+	}
+	return details
+}
+
+// hasMessage reports whether any of the error messages or field errors contains the given text
+func (e *APIError) hasMessage(text string) bool {
+	for _, detail := range e.details() {
+		if strings.Contains(detail, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// AsAPIError returns the APIError in the chain of err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// IsNotFound reports whether the object of the request does not exist on JSD
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether the request failed because an object with the same name or key already exists on JSD.
+// Jira reports most of these conflicts as bad requests, so they are recognized by their messages
+func IsConflict(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusConflict ||
+		(apiErr.StatusCode == http.StatusBadRequest && apiErr.hasMessage(alreadyExistsMessage))
+}
+
+// IsRateLimited reports whether the request was rejected by the rate limits of the Jira site
+func IsRateLimited(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// IsAuth reports whether the request failed because the credentials are invalid or lack permissions
+func IsAuth(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// IsServerError reports whether the request failed on the side of the Jira site, which is usually temporary
+func IsServerError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+func TestJiraClient_CreateProject_shouldReturnAPIError_whenProjectAlreadyExists(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Post(EndpointApiVersion3Project).
+		Reply(400).
+		SetHeader("X-Arequestid", "a1b2c3").
+		JSON(map[string]interface{}{
+			"errorMessages": []string{},
+			"errors": map[string]string{
+				"projectName": "A project with that name already exists.",
+				"projectKey":  "A project with that project key already exists.",
+			},
+		})
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	_, err := jiraClient.CreateProject(context.TODO(), Project{Name: "test"})
+
+	apiErr, ok := AsAPIError(err)
+	st.Expect(t, ok, true)
+	st.Expect(t, apiErr.StatusCode, 400)
+	st.Expect(t, apiErr.RequestId, "a1b2c3")
+	st.Expect(t, apiErr.Endpoint, "POST "+EndpointApiVersion3Project)
+	st.Expect(t, apiErr.Errors["projectKey"], "A project with that project key already exists.")
+	st.Expect(t, err.Error(), "Rest request to create Project failed with status: 400 and errors: "+
+		"projectKey: A project with that project key already exists.; projectName: A project with that name already exists.")
+
+	st.Expect(t, IsConflict(err), true)
+	st.Expect(t, IsNotFound(err), false)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateCustomer_shouldReturnConflict_whenCustomerAlreadyExists(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Post(CreateCustomerApiPath).
+		Reply(400).
+		JSON(map[string]interface{}{
+			"errorMessage": "An account already exists for this email",
+		})
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	_, err := jiraClient.CreateCustomer(context.TODO(), Customer{Email: "test@sample.com"})
+
+	st.Expect(t, err.Error(), "Rest request to create customer failed with status: 400 and errors: An account already exists for this email")
+	st.Expect(t, IsConflict(err), true)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestAPIError_predicates_shouldMatchStatus_whenErrorIsWrapped(t *testing.T) {
+	newError := func(statusCode int) error {
+		return fmt.Errorf("reconcile failed: %w", &APIError{StatusCode: statusCode})
+	}
+
+	st.Expect(t, IsNotFound(newError(404)), true)
+	st.Expect(t, IsConflict(newError(409)), true)
+	st.Expect(t, IsConflict(newError(400)), false)
+	st.Expect(t, IsRateLimited(newError(429)), true)
+	st.Expect(t, IsAuth(newError(401)), true)
+	st.Expect(t, IsAuth(newError(403)), true)
+	st.Expect(t, IsAuth(newError(500)), false)
+	st.Expect(t, IsServerError(newError(503)), true)
+	st.Expect(t, IsServerError(newError(429)), false)
+	st.Expect(t, IsNotFound(fmt.Errorf("not an api error")), false)
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Flavour is the deployment type of a Jira site
+type Flavour string
+
+const (
+	// FlavourCloud is Jira Service Management Cloud, which identifies users by their account id
+	FlavourCloud Flavour = "cloud"
+	// FlavourDataCenter is Jira Service Management Data Center or Server, which identifies users by their username
+	FlavourDataCenter Flavour = "datacenter"
+)
+
+const (
+	// Endpoints of Jira Data Center
+	EndpointApiVersion2Project = "/rest/api/2/project"
+	EndpointApiVersion2User    = "/rest/api/2/user?username="
+	SearchUserV2Endpoint       = "/rest/api/2/user/search?username="
+)
+
+// ParseFlavour validates the flavour of a Jira site. An empty flavour is Cloud
+func ParseFlavour(flavour string) (Flavour, error) {
+	switch Flavour(flavour) {
+	case "", FlavourCloud:
+		return FlavourCloud, nil
+	case FlavourDataCenter:
+		return FlavourDataCenter, nil
+	}
+	return "", fmt.Errorf("unknown flavour %s, expected %s or %s", flavour, FlavourCloud, FlavourDataCenter)
+}
+
+// Site is a Jira site, with the credentials used to call its api
+type Site struct {
+	BaseURL string
+	Auth    Authenticator
+	// Flavour of the site, Cloud if unset
+	Flavour Flavour
+}
+
+func (s *Site) adapter() apiAdapter {
+	if s.Flavour == FlavourDataCenter {
+		return dataCenterAdapter{}
+	}
+	return cloudAdapter{}
+}
+
+// apiAdapter hides the differences between the apis of Jira Cloud and Jira Data Center. The client and the CRs
+// use a single user id, which is the account id on Cloud and the username on Data Center
+type apiAdapter interface {
+	projectPath() string
+	userPath(userId string) string
+	userSearchPath(query string) string
+	projectRequestBody(project Project) interface{}
+	customerRequestBody(customer Customer) interface{}
+	usersRequestBody(userIds []string) interface{}
+	userId(accountId string, username string) string
+	// Whether the template of a project can be derived from the project on JSD
+	readsProjectTemplate() bool
+	supportsLegacyCustomers() bool
+	supportsUserUpdate() bool
+}
+
+type cloudAdapter struct{}
+
+func (cloudAdapter) projectPath() string {
+	return EndpointApiVersion3Project
+}
+
+func (cloudAdapter) userPath(userId string) string {
+	return EndpointUser + userId
+}
+
+func (cloudAdapter) userSearchPath(query string) string {
+	return SearchUserEndpoint + url.QueryEscape(query)
+}
+
+func (cloudAdapter) projectRequestBody(project Project) interface{} {
+	return project
+}
+
+func (cloudAdapter) customerRequestBody(customer Customer) interface{} {
+	return customer
+}
+
+func (cloudAdapter) usersRequestBody(userIds []string) interface{} {
+	return CustomerAddResponse{AccountIds: userIds}
+}
+
+func (cloudAdapter) userId(accountId string, username string) string {
+	return accountId
+}
+
+func (cloudAdapter) readsProjectTemplate() bool {
+	return true
+}
+
+func (cloudAdapter) supportsLegacyCustomers() bool {
+	return true
+}
+
+// The email and name of Atlassian accounts can only be changed by their owner
+func (cloudAdapter) supportsUserUpdate() bool {
+	return false
+}
+
+type dataCenterAdapter struct{}
+
+// dataCenterProjectRequestBody replaces the lead account id of a project by the username of its lead
+type dataCenterProjectRequestBody struct {
+	Project
+	LeadAccountId string `json:"leadAccountId,omitempty"`
+	Lead          string `json:"lead,omitempty"`
+}
+
+type dataCenterCustomerRequestBody struct {
+	Email    string `json:"email,omitempty"`
+	FullName string `json:"fullName,omitempty"`
+}
+
+type dataCenterUsersRequestBody struct {
+	Usernames []string `json:"usernames,omitempty"`
+}
+
+func (dataCenterAdapter) projectPath() string {
+	return EndpointApiVersion2Project
+}
+
+func (dataCenterAdapter) userPath(userId string) string {
+	return EndpointApiVersion2User + url.QueryEscape(userId)
+}
+
+func (dataCenterAdapter) userSearchPath(query string) string {
+	return SearchUserV2Endpoint + url.QueryEscape(query)
+}
+
+func (dataCenterAdapter) projectRequestBody(project Project) interface{} {
+	return dataCenterProjectRequestBody{
+		Project: project,
+		Lead:    project.LeadAccountId,
+	}
+}
+
+func (dataCenterAdapter) customerRequestBody(customer Customer) interface{} {
+	return dataCenterCustomerRequestBody{
+		Email:    customer.Email,
+		FullName: customer.DisplayName,
+	}
+}
+
+func (dataCenterAdapter) usersRequestBody(userIds []string) interface{} {
+	return dataCenterUsersRequestBody{Usernames: userIds}
+}
+
+func (dataCenterAdapter) userId(accountId string, username string) string {
+	return username
+}
+
+// Data Center only has classic projects, whose style is not returned by the api
+func (dataCenterAdapter) readsProjectTemplate() bool {
+	return false
+}
+
+// The invite api of the customers page only exists on Cloud
+func (dataCenterAdapter) supportsLegacyCustomers() bool {
+	return false
+}
+
+func (dataCenterAdapter) supportsUserUpdate() bool {
+	return true
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+func newDataCenterClient() Client {
+	return NewSiteClient(Site{
+		BaseURL: mockData.BaseURL,
+		Auth:    BearerAuth{Token: "personal-access-token"},
+		Flavour: FlavourDataCenter,
+	})
+}
+
+func TestJiraClient_GetProjectByIdentifier_shouldUseLeadUsername_whenFlavourIsDataCenter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Get(EndpointApiVersion2Project + "/" + mockData.ProjectID).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":             mockData.ProjectID,
+			"key":            "DCP",
+			"name":           "Data Center Project",
+			"projectTypeKey": "service_desk",
+			"lead":           map[string]string{"key": "JIRAUSER10100", "name": "jdoe"},
+		})
+	mockProjectSchemes(EndpointApiVersion2Project)
+	gock.New(mockData.BaseURL).
+		Get(ServiceDeskV1ApiPath + "DCP" + RequestSecurityPath).
+		Reply(200).
+		JSON(map[string]bool{})
+
+	jiraClient := newDataCenterClient()
+	project, err := jiraClient.GetProjectByIdentifier(context.TODO(), mockData.ProjectID)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, project.Key, "DCP")
+	st.Expect(t, project.LeadAccountId, "jdoe")
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateProject_shouldSendLead_whenFlavourIsDataCenter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Post(EndpointApiVersion2Project).
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"name":               "Data Center Project",
+			"key":                "DCP",
+			"projectTypeKey":     "service_desk",
+			"projectTemplateKey": ClassicProjectTemplateKey,
+			"lead":               "jdoe",
+		}).
+		Reply(201).
+		JSON(mockData.CreateProjectResponseJSON)
+
+	jiraClient := newDataCenterClient()
+	id, err := jiraClient.CreateProject(context.TODO(), Project{
+		Name:               "Data Center Project",
+		Key:                "DCP",
+		ProjectTypeKey:     "service_desk",
+		ProjectTemplateKey: ClassicProjectTemplateKey,
+		LeadAccountId:      "jdoe",
+	})
+
+	st.Expect(t, err, nil)
+	st.Expect(t, id, mockData.ProjectID)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_ProjectDiff_shouldIgnoreTemplate_whenFlavourIsDataCenter(t *testing.T) {
+	oldProject := Project{Key: "DCP", LeadAccountId: "jdoe"}
+	newProject := Project{Key: "DCP", LeadAccountId: "jdoe", ProjectTemplateKey: ClassicProjectTemplateKey}
+
+	st.Expect(t, len(newDataCenterClient().ProjectDiff(oldProject, newProject)), 0)
+	st.Expect(t, NewClient("", mockData.BaseURL, "").ProjectDiff(oldProject, newProject), []string{"projectTemplateKey"})
+}
+
+func TestJiraClient_CreateCustomer_shouldReturnUsername_whenFlavourIsDataCenter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Post(CreateCustomerApiPath).
+		MatchType("json").
+		JSON(map[string]string{"email": "jdoe@sample.com", "fullName": "John Doe"}).
+		Reply(201).
+		JSON(map[string]string{"key": "JIRAUSER10100", "name": "jdoe", "emailAddress": "jdoe@sample.com", "displayName": "John Doe"})
+
+	jiraClient := newDataCenterClient()
+	id, err := jiraClient.CreateCustomer(context.TODO(), Customer{Email: "jdoe@sample.com", DisplayName: "John Doe"})
+
+	st.Expect(t, err, nil)
+	st.Expect(t, id, "jdoe")
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetCustomerById_shouldGetUserByUsername_whenFlavourIsDataCenter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Get("/rest/api/2/user").
+		MatchParam("username", "jdoe").
+		Reply(200).
+		JSON(map[string]string{"key": "JIRAUSER10100", "name": "jdoe", "emailAddress": "jdoe@sample.com", "displayName": "John Doe"})
+
+	jiraClient := newDataCenterClient()
+	customer, err := jiraClient.GetCustomerById(context.TODO(), "jdoe")
+
+	st.Expect(t, err, nil)
+	st.Expect(t, customer.AccountId, "jdoe")
+	st.Expect(t, customer.Email, "jdoe@sample.com")
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetCustomerIdByEmail_shouldEscapeQuery_whenEmailContainsPlus(t *testing.T) {
+	defer gock.Off()
+
+	// Unescaped, the plus of the email would be sent as a space
+	email := "john+doe@sample.com"
+
+	gock.New(mockData.BaseURL).
+		Get("/rest/api/3/user/search").
+		MatchParam("query", regexp.QuoteMeta(email)).
+		Reply(200).
+		JSON([]map[string]string{{"accountId": mockData.CustomerAccountId, "emailAddress": email}})
+
+	gock.New(mockData.BaseURL).
+		Get("/rest/api/2/user/search").
+		MatchParam("username", regexp.QuoteMeta(email)).
+		Reply(200).
+		JSON([]map[string]string{{"key": "JIRAUSER10100", "name": "jdoe", "emailAddress": email}})
+
+	id, err := NewClient("", mockData.BaseURL, "").GetCustomerIdByEmail(context.TODO(), email)
+	st.Expect(t, err, nil)
+	st.Expect(t, id, mockData.CustomerAccountId)
+
+	id, err = newDataCenterClient().GetCustomerIdByEmail(context.TODO(), email)
+	st.Expect(t, err, nil)
+	st.Expect(t, id, "jdoe")
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_AddCustomerToProject_shouldSendUsernames_whenFlavourIsDataCenter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Post(AddCustomerApiPath + "DCP/customer").
+		MatchType("json").
+		JSON(map[string][]string{"usernames": {"jdoe"}}).
+		Reply(204)
+
+	jiraClient := newDataCenterClient()
+	err := jiraClient.AddCustomerToProject(context.TODO(), "jdoe", "DCP")
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateCustomer_shouldUpdateUser_whenFlavourIsDataCenter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL).
+		Put("/rest/api/2/user").
+		MatchParam("username", "jdoe").
+		MatchType("json").
+		JSON(map[string]string{"emailAddress": "john.doe@sample.com", "displayName": "John Doe"}).
+		Reply(200)
+
+	jiraClient := newDataCenterClient()
+	st.Expect(t, jiraClient.CanUpdateCustomer(), true)
+
+	err := jiraClient.UpdateCustomer(context.TODO(), "jdoe", Customer{Email: "john.doe@sample.com", DisplayName: "John Doe"})
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateCustomer_shouldFail_whenFlavourIsCloud(t *testing.T) {
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	st.Expect(t, jiraClient.CanUpdateCustomer(), false)
+
+	err := jiraClient.UpdateCustomer(context.TODO(), mockData.CustomerAccountId, Customer{Email: "john.doe@sample.com"})
+
+	st.Expect(t, err.Error(), "Updating customers is not supported by Jira Cloud")
+}
+
+func TestJiraClient_CreateLegacyCustomer_shouldFail_whenFlavourIsDataCenter(t *testing.T) {
+	jiraClient := newDataCenterClient()
+	_, err := jiraClient.CreateLegacyCustomer(context.TODO(), "jdoe@sample.com", "DCP")
+
+	st.Expect(t, err.Error(), "Legacy customers are not supported by Jira Data Center")
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_service_desk_requests_total",
+		Help: "Calls to the Jira Service Desk api by endpoint, method and status class",
+	}, []string{"endpoint", "method", "status_class"})
+
+	authFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jira_service_desk_auth_failures_total",
+		Help: "Calls to the Jira Service Desk api rejected with 401 or 403, by endpoint and method",
+	}, []string{"endpoint", "method"})
+
+	requestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jira_service_desk_request_duration_seconds",
+		Help:    "Duration of calls to the Jira Service Desk api, including retries and rate limiting",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"endpoint", "method", "status_class"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, authFailuresTotal, requestDurationSeconds)
+}
+
+// Status class of calls that failed without a response
+const statusClassError = "error"
+
+// Endpoint of calls whose path matches none of the endpointTemplates
+const otherEndpoint = "other"
+
+// endpointTemplates are the paths of the api called by the client, with identifiers replaced by placeholders
+// so that metrics are not labelled with project keys or account ids
+var endpointTemplates = [][]string{
+	splitPath("/rest/api/{version}/project"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}/avatar"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}/avatar/{avatarId}"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}/avatar2"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}/permissionscheme"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}/notificationscheme"),
+	splitPath("/rest/api/{version}/project/{projectIdOrKey}/issuesecuritylevelscheme"),
+	splitPath("/rest/api/{version}/user"),
+	splitPath("/rest/api/{version}/user/search"),
+	splitPath("/rest/servicedeskapi/customer"),
+	splitPath("/rest/servicedeskapi/organization"),
+	splitPath("/rest/servicedeskapi/organization/{organizationId}"),
+	splitPath("/rest/servicedeskapi/organization/{organizationId}/user"),
+	splitPath("/rest/servicedeskapi/servicedesk"),
+	splitPath("/rest/servicedeskapi/servicedesk/{serviceDeskId}/customer"),
+	splitPath("/rest/servicedeskapi/servicedesk/{serviceDeskId}/organization"),
+	splitPath("/rest/servicedeskapi/servicedesk/{serviceDeskId}/queue/{queueId}"),
+	splitPath("/rest/servicedeskapi/servicedesk/{serviceDeskId}/requesttype"),
+	splitPath("/rest/servicedeskapi/servicedesk/{serviceDeskId}/requesttype/{requestTypeId}"),
+	splitPath("/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics"),
+	splitPath("/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics/{slaId}"),
+	splitPath("/rest/servicedesk/1/servicedesk/{projectKey}/queues"),
+	splitPath("/rest/servicedesk/1/servicedesk/{projectKey}/queues/{queueId}"),
+	splitPath("/rest/servicedesk/1/servicedesk/{projectKey}/settings/requestsecurity"),
+	splitPath("/rest/servicedesk/1/pages/people/customers/pagination/{projectKey}/invite"),
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// endpointTemplate returns the template of the path of a request. The base url of a site may have a path
+// of its own, e.g. for the api gateway of OAuth 2.0 apps, so templates are matched against the end of the path
+func endpointTemplate(path string) string {
+	segments := splitPath(path)
+
+	for _, template := range endpointTemplates {
+		if len(template) > len(segments) || !matchesTemplate(template, segments[len(segments)-len(template):]) {
+			continue
+		}
+		return "/" + strings.Join(template, "/")
+	}
+	return otherEndpoint
+}
+
+func matchesTemplate(template []string, segments []string) bool {
+	for i, segment := range template {
+		if !strings.HasPrefix(segment, "{") && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func statusClass(response *http.Response, err error) string {
+	if err != nil || response == nil {
+		return statusClassError
+	}
+	return strconv.Itoa(response.StatusCode/100) + "xx"
+}
+
+// observeRequest records a call to the api that was started at the given time
+func observeRequest(req *http.Request, response *http.Response, err error, start time.Time) {
+	labels := prometheus.Labels{
+		"endpoint":     endpointTemplate(req.URL.Path),
+		"method":       req.Method,
+		"status_class": statusClass(response, err),
+	}
+	requestsTotal.With(labels).Inc()
+	requestDurationSeconds.With(labels).Observe(time.Since(start).Seconds())
+
+	if err == nil && (response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden) {
+		authFailuresTotal.WithLabelValues(labels["endpoint"], labels["method"]).Inc()
+	}
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nbio/st"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+func TestEndpointTemplate_shouldReplaceIdentifiers_whenPathMatchesEndpoint(t *testing.T) {
+	st.Expect(t, endpointTemplate("/rest/api/3/project/10003"), "/rest/api/{version}/project/{projectIdOrKey}")
+	st.Expect(t, endpointTemplate("/rest/api/2/user"), "/rest/api/{version}/user")
+	st.Expect(t, endpointTemplate("/rest/api/3/project/TEST/avatar/10010"), "/rest/api/{version}/project/{projectIdOrKey}/avatar/{avatarId}")
+	st.Expect(t, endpointTemplate("/rest/api/3/project/10003/permissionscheme"), "/rest/api/{version}/project/{projectIdOrKey}/permissionscheme")
+	st.Expect(t, endpointTemplate("/rest/api/2/project/10003/issuesecuritylevelscheme"), "/rest/api/{version}/project/{projectIdOrKey}/issuesecuritylevelscheme")
+	st.Expect(t, endpointTemplate("/rest/servicedeskapi/servicedesk/TEST/customer"), "/rest/servicedeskapi/servicedesk/{serviceDeskId}/customer")
+	st.Expect(t, endpointTemplate("/rest/servicedesk/1/servicedesk/agent/TEST/sla/metrics/5"), "/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics/{slaId}")
+	st.Expect(t, endpointTemplate("/rest/servicedesk/1/servicedesk/TEST/queues"), "/rest/servicedesk/1/servicedesk/{projectKey}/queues")
+	st.Expect(t, endpointTemplate("/ex/jira/cloud-id/rest/servicedeskapi/organization/1"), "/rest/servicedeskapi/organization/{organizationId}")
+	st.Expect(t, endpointTemplate("/rest/api/3/issue/TEST-1"), otherEndpoint)
+}
+
+func TestJiraClient_do_shouldCountRequests_whenRequestsFail(t *testing.T) {
+	defer gock.Off()
+
+	labels := []string{"/rest/api/{version}/project/{projectIdOrKey}", "GET", "4xx"}
+	before := testutil.ToFloat64(requestsTotal.WithLabelValues(labels...))
+
+	gock.New(mockData.BaseURL).
+		Get(EndpointApiVersion3Project + "/" + mockData.ProjectID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	_, err := jiraClient.GetProjectByIdentifier(context.TODO(), mockData.ProjectID)
+
+	st.Expect(t, IsNotFound(err), true)
+	st.Expect(t, testutil.ToFloat64(requestsTotal.WithLabelValues(labels...)), before+1)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_do_shouldCountAuthFailures_whenCredentialsAreRejected(t *testing.T) {
+	defer gock.Off()
+
+	labels := []string{"/rest/servicedeskapi/organization/{organizationId}", "GET"}
+	before := testutil.ToFloat64(authFailuresTotal.WithLabelValues(labels...))
+
+	gock.New(mockData.BaseURL).
+		Get(OrganizationApiPath + "/" + mockData.OrganizationID).
+		Reply(401)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	_, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, IsAuth(err), true)
+	st.Expect(t, testutil.ToFloat64(authFailuresTotal.WithLabelValues(labels...)), before+1)
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,335 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+const (
+	// Endpoints
+	OrganizationApiPath            = "/rest/servicedeskapi/organization"
+	ServiceDeskOrganizationApiPath = "/organization"
+	OrganizationUserApiPath        = "/user"
+
+	// Page size used while searching through organizations
+	OrganizationPageLimit = 50
+)
+
+type Organization struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type OrganizationRequestBody struct {
+	Name string `json:"name,omitempty"`
+}
+
+type OrganizationGetResponse struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type OrganizationListResponse struct {
+	Size       int                       `json:"size,omitempty"`
+	Start      int                       `json:"start,omitempty"`
+	Limit      int                       `json:"limit,omitempty"`
+	IsLastPage bool                      `json:"isLastPage,omitempty"`
+	Values     []OrganizationGetResponse `json:"values,omitempty"`
+}
+
+type ServiceDeskOrganizationRequestBody struct {
+	OrganizationId int `json:"organizationId"`
+}
+
+// GetOrganizationById gets an organization by ID from JSD
+func (c *jiraServiceDeskClient) GetOrganizationById(ctx context.Context, organizationId string) (Organization, error) {
+	var organization Organization
+
+	request, err := c.newRequest(ctx, "GET", OrganizationApiPath+"/"+organizationId, nil, false)
+	if err != nil {
+		return organization, err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return organization, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := newAPIError("get organization", response, nil)
+		return organization, err
+	}
+
+	var responseObject OrganizationGetResponse
+	err = json.NewDecoder(response.Body).Decode(&responseObject)
+	if err != nil {
+		return organization, err
+	}
+
+	organization = organizationGetResponseToOrganizationMapper(responseObject)
+
+	return organization, err
+}
+
+// GetOrganizationIdByName searches all organizations on JSD for the one with the given name
+func (c *jiraServiceDeskClient) GetOrganizationIdByName(ctx context.Context, name string) (string, error) {
+	start := 0
+	for {
+		request, err := c.newRequest(ctx, "GET", OrganizationApiPath+"?start="+strconv.Itoa(start)+"&limit="+strconv.Itoa(OrganizationPageLimit), nil, false)
+		if err != nil {
+			return "", err
+		}
+
+		response, err := c.do(request)
+		if err != nil {
+			return "", err
+		}
+
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			err := newAPIError("get organizations", response, nil)
+			response.Body.Close()
+			return "", err
+		}
+
+		var responseObject OrganizationListResponse
+		err = json.NewDecoder(response.Body).Decode(&responseObject)
+		response.Body.Close()
+		if err != nil {
+			return "", err
+		}
+
+		for _, organization := range responseObject.Values {
+			if organization.Name == name {
+				return organization.Id, nil
+			}
+		}
+
+		if responseObject.IsLastPage || len(responseObject.Values) == 0 {
+			return "", nil
+		}
+		start += len(responseObject.Values)
+	}
+}
+
+// CreateOrganization creates a new organization on JSD
+func (c *jiraServiceDeskClient) CreateOrganization(ctx context.Context, organization Organization) (string, error) {
+	body := OrganizationRequestBody{
+		Name: organization.Name,
+	}
+
+	request, err := c.newRequest(ctx, "POST", OrganizationApiPath, body, false)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("create organization", response, responseData)
+		return "", err
+	}
+
+	var responseObject OrganizationGetResponse
+	err = json.Unmarshal(responseData, &responseObject)
+	if err != nil {
+		return "", err
+	}
+
+	return responseObject.Id, err
+}
+
+// UpdateOrganization renames an existing organization on JSD
+func (c *jiraServiceDeskClient) UpdateOrganization(ctx context.Context, organizationId string, organization Organization) error {
+	body := OrganizationRequestBody{
+		Name: organization.Name,
+	}
+
+	request, err := c.newRequest(ctx, "PUT", OrganizationApiPath+"/"+organizationId, body, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("update organization", response, responseData)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteOrganization deletes an organization from JSD
+func (c *jiraServiceDeskClient) DeleteOrganization(ctx context.Context, organizationId string) error {
+	request, err := c.newRequest(ctx, "DELETE", OrganizationApiPath+"/"+organizationId, nil, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("delete organization", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+// AddOrganizationToProject adds an organization to the service desk of a JSD project
+func (c *jiraServiceDeskClient) AddOrganizationToProject(ctx context.Context, organizationId string, projectKey string) error {
+	body, err := serviceDeskOrganizationRequestBody(organizationId)
+	if err != nil {
+		return err
+	}
+
+	request, err := c.newRequest(ctx, "POST", AddCustomerApiPath+projectKey+ServiceDeskOrganizationApiPath, body, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("add organization", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveOrganizationFromProject removes an organization from the service desk of a JSD project
+func (c *jiraServiceDeskClient) RemoveOrganizationFromProject(ctx context.Context, organizationId string, projectKey string) error {
+	body, err := serviceDeskOrganizationRequestBody(organizationId)
+	if err != nil {
+		return err
+	}
+
+	request, err := c.newRequest(ctx, "DELETE", AddCustomerApiPath+projectKey+ServiceDeskOrganizationApiPath, body, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("remove organization", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+// AddCustomerToOrganization adds a customer to a JSD organization
+func (c *jiraServiceDeskClient) AddCustomerToOrganization(ctx context.Context, customerAccountId string, organizationId string) error {
+	addCustomerBody := c.adapter().usersRequestBody([]string{customerAccountId})
+
+	request, err := c.newRequest(ctx, "POST", OrganizationApiPath+"/"+organizationId+OrganizationUserApiPath, addCustomerBody, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("add Customer to organization", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveCustomerFromOrganization removes a customer from a JSD organization
+func (c *jiraServiceDeskClient) RemoveCustomerFromOrganization(ctx context.Context, customerAccountId string, organizationId string) error {
+	removeCustomerBody := c.adapter().usersRequestBody([]string{customerAccountId})
+
+	request, err := c.newRequest(ctx, "DELETE", OrganizationApiPath+"/"+organizationId+OrganizationUserApiPath, removeCustomerBody, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("remove Customer from organization", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+func (c *jiraServiceDeskClient) IsOrganizationUpdated(organization *jiraservicedeskv1alpha1.Organization, existingOrganization Organization) bool {
+	if reflect.DeepEqual(organization.Spec.Projects, organization.Status.AssociatedProjects) && len(c.OrganizationDiff(organization, existingOrganization)) == 0 {
+		return false
+	} else {
+		return true
+	}
+}
+
+// OrganizationDiff lists the fields of the spec that differ from the organization on JSD. The service desks
+// of an organization can not be read from JSD, so they are not compared
+func (c *jiraServiceDeskClient) OrganizationDiff(organization *jiraservicedeskv1alpha1.Organization, existingOrganization Organization) []string {
+	var diff []string
+
+	if organization.Spec.Name != existingOrganization.Name {
+		diff = append(diff, "name")
+	}
+
+	return diff
+}
+
+func (c *jiraServiceDeskClient) GetOrganizationFromOrganizationCR(organization *jiraservicedeskv1alpha1.Organization) Organization {
+	return organizationCRToOrganizationMapper(organization)
+}
+
+func serviceDeskOrganizationRequestBody(organizationId string) (ServiceDeskOrganizationRequestBody, error) {
+	id, err := strconv.Atoi(organizationId)
+	if err != nil {
+		return ServiceDeskOrganizationRequestBody{}, errors.New("Invalid organization id: " + organizationId)
+	}
+	return ServiceDeskOrganizationRequestBody{OrganizationId: id}, nil
+}
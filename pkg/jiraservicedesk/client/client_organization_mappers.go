@@ -0,0 +1,22 @@
+package client
+
+import jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+
+func organizationCRToOrganizationMapper(organization *jiraservicedeskv1alpha1.Organization) Organization {
+	organizationObject := Organization{
+		Name: organization.Spec.Name,
+	}
+
+	if len(organization.Status.OrganizationId) > 0 {
+		organizationObject.Id = organization.Status.OrganizationId
+	}
+
+	return organizationObject
+}
+
+func organizationGetResponseToOrganizationMapper(response OrganizationGetResponse) Organization {
+	return Organization{
+		Id:   response.Id,
+		Name: response.Name,
+	}
+}
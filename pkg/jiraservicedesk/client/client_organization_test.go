@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nbio/st"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestJiraClient_GetOrganizationById_shouldGetOrganization_whenValidOrganizationIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Reply(200).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, mockData.OrganizationID)
+	st.Expect(t, organization.Name, "Sample Organization")
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetOrganizationById_shouldNotGetOrganization_whenInValidOrganizationIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, "")
+	st.Expect(t, err.Error(), mockData.GetOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetOrganizationIdByName_shouldGetOrganizationId_whenOrganizationExists(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+OrganizationApiPath).
+		Get("").
+		MatchParam("start", "0").
+		Reply(200).
+		JSON(mockData.ListOrganizationsResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.GetOrganizationIdByName(context.TODO(), "Sample Organization")
+
+	st.Expect(t, id, mockData.OrganizationID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateOrganization_shouldCreateOrganization_whenValidOrganizationDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Post("").
+		MatchType("json").
+		JSON(mockData.CreateOrganizationInputJSON).
+		Reply(201).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateOrganization(context.TODO(), Organization{Name: "Sample Organization"})
+
+	st.Expect(t, id, mockData.OrganizationID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateOrganization_shouldNotCreateOrganization_whenInValidOrganizationDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Post("").
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateOrganization(context.TODO(), Organization{})
+
+	st.Expect(t, id, "")
+	st.Expect(t, err.Error(), mockData.CreateOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateOrganization_shouldRenameOrganization_whenValidOrganizationIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Put("/" + mockData.OrganizationID).
+		MatchType("json").
+		JSON(mockData.UpdateOrganizationInputJSON).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateOrganization(context.TODO(), mockData.OrganizationID, Organization{Name: "Renamed Organization"})
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateOrganization_shouldNotRenameOrganization_whenInValidOrganizationIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Put("/" + mockData.OrganizationID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateOrganization(context.TODO(), mockData.OrganizationID, Organization{Name: "Renamed Organization"})
+
+	st.Expect(t, err.Error(), mockData.UpdateOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteOrganization_shouldDeleteOrganization_whenValidOrganizationIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Delete("/" + mockData.OrganizationID).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteOrganization(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteOrganization_shouldNotDeleteOrganization_whenInValidOrganizationIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Delete("/" + mockData.OrganizationID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteOrganization(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, err.Error(), mockData.DeleteOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_AddOrganizationToProject_shouldAddOrganizationToProject_whenValidProjectIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.AddProjectKey).
+		Post(mockData.OrganizationEndPoint).
+		MatchType("json").
+		JSON(mockData.AddOrganizationRequestJSON).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.AddOrganizationToProject(context.TODO(), mockData.OrganizationID, mockData.AddProjectKey)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_AddOrganizationToProject_shouldNotAddOrganizationToProject_whenInValidProjectIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.AddProjectKey).
+		Post(mockData.OrganizationEndPoint).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.AddOrganizationToProject(context.TODO(), mockData.OrganizationID, mockData.AddProjectKey)
+
+	st.Expect(t, err.Error(), mockData.AddOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_RemoveOrganizationFromProject_shouldRemoveOrganizationFromProject_whenValidProjectIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RemoveProjectKey).
+		Delete(mockData.OrganizationEndPoint).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.RemoveOrganizationFromProject(context.TODO(), mockData.OrganizationID, mockData.RemoveProjectKey)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_RemoveOrganizationFromProject_shouldNotRemoveOrganizationFromProject_whenInvalidProjectIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RemoveProjectKey).
+		Delete(mockData.OrganizationEndPoint).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.RemoveOrganizationFromProject(context.TODO(), mockData.OrganizationID, mockData.RemoveProjectKey)
+
+	st.Expect(t, err.Error(), mockData.RemoveOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_AddCustomerToOrganization_shouldAddCustomerToOrganization_whenValidOrganizationIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath + "/" + mockData.OrganizationID).
+		Post(mockData.OrganizationUserEndPoint).
+		MatchType("json").
+		JSON(mockData.AddCustomerSuccessResponse).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.AddCustomerToOrganization(context.TODO(), mockData.CustomerAccountId, mockData.OrganizationID)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_AddCustomerToOrganization_shouldNotAddCustomerToOrganization_whenInValidOrganizationIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath + "/" + mockData.OrganizationID).
+		Post(mockData.OrganizationUserEndPoint).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.AddCustomerToOrganization(context.TODO(), mockData.CustomerAccountId, mockData.OrganizationID)
+
+	st.Expect(t, err.Error(), mockData.AddCustomerToOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_RemoveCustomerFromOrganization_shouldRemoveCustomerFromOrganization_whenValidOrganizationIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath + "/" + mockData.OrganizationID).
+		Delete(mockData.OrganizationUserEndPoint).
+		MatchType("json").
+		JSON(mockData.AddCustomerSuccessResponse).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.RemoveCustomerFromOrganization(context.TODO(), mockData.CustomerAccountId, mockData.OrganizationID)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_RemoveCustomerFromOrganization_shouldNotRemoveCustomerFromOrganization_whenInValidOrganizationIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath + "/" + mockData.OrganizationID).
+		Delete(mockData.OrganizationUserEndPoint).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.RemoveCustomerFromOrganization(context.TODO(), mockData.CustomerAccountId, mockData.OrganizationID)
+
+	st.Expect(t, err.Error(), mockData.RemoveCustomerFromOrganizationFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_OrganizationDiff_shouldListChangedFields_whenSpecDiffersFromOrganization(t *testing.T) {
+	organization := mockData.SampleOrganization.DeepCopy()
+	existingOrganization := Organization{Id: mockData.OrganizationID, Name: organization.Spec.Name}
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	st.Expect(t, len(jiraClient.OrganizationDiff(organization, existingOrganization)), 0)
+
+	existingOrganization.Name = "Renamed Organization"
+	st.Expect(t, jiraClient.OrganizationDiff(organization, existingOrganization), []string{"name"})
+}
@@ -1,8 +1,8 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"strconv"
 
@@ -14,6 +14,13 @@ const (
 	EndpointApiVersion3Project = "/rest/api/3/project"
 	ServiceDeskV1ApiPath       = "/rest/servicedesk/1/servicedesk/"
 	RequestSecurityPath        = "/settings/requestsecurity"
+	ServiceDeskApiPath         = "/rest/servicedeskapi/servicedesk"
+	PermissionSchemePath       = "/permissionscheme"
+	NotificationSchemePath     = "/notificationscheme"
+	IssueSecuritySchemePath    = "/issuesecuritylevelscheme"
+
+	// Page size used while listing service desks
+	ServiceDeskPageLimit = 50
 
 	// Project Template Types
 	ClassicProjectTemplateKey = "com.atlassian.servicedesk:itil-v2-service-desk-project"
@@ -35,24 +42,60 @@ type Project struct {
 	PermissionScheme    int    `json:"permissionScheme,omitempty"`
 	NotificationScheme  int    `json:"notificationScheme,omitempty"`
 	CategoryId          int    `json:"categoryId,omitempty"`
+	// Request security settings of a service desk project, managed through their own endpoint
+	CustomerAccess *CustomerAccess `json:"-"`
 }
 
 type ProjectGetResponse struct {
-	Self           string      `json:"self,omitempty"`
-	Id             string      `json:"id,omitempty"`
-	Name           string      `json:"name,omitempty"`
-	Key            string      `json:"key,omitempty"`
-	Description    string      `json:"description,omitempty"`
-	Lead           ProjectLead `json:"lead,omitempty"`
-	ProjectTypeKey string      `json:"projectTypeKey,omitempty"`
-	Style          string      `json:"style,omitempty"`
-	AssigneeType   string      `json:"assigneeType,omitempty"`
-	URL            string      `json:"url,omitempty"`
+	Self            string          `json:"self,omitempty"`
+	Id              string          `json:"id,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	Key             string          `json:"key,omitempty"`
+	Description     string          `json:"description,omitempty"`
+	Lead            ProjectLead     `json:"lead,omitempty"`
+	ProjectTypeKey  string          `json:"projectTypeKey,omitempty"`
+	Style           string          `json:"style,omitempty"`
+	AssigneeType    string          `json:"assigneeType,omitempty"`
+	URL             string          `json:"url,omitempty"`
+	ProjectCategory ProjectCategory `json:"projectCategory,omitempty"`
+}
+
+type ProjectCategory struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// ProjectSchemeResponse is the scheme of a project, returned by the permission, notification and issue
+// security scheme endpoints of a project
+type ProjectSchemeResponse struct {
+	Id   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type ProjectSchemeRequestBody struct {
+	Id int `json:"id"`
+}
+
+type ServiceDeskGetResponse struct {
+	Id          string `json:"id,omitempty"`
+	ProjectId   string `json:"projectId,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+	ProjectKey  string `json:"projectKey,omitempty"`
+}
+
+type ServiceDeskListResponse struct {
+	Size       int                      `json:"size,omitempty"`
+	Start      int                      `json:"start,omitempty"`
+	Limit      int                      `json:"limit,omitempty"`
+	IsLastPage bool                     `json:"isLastPage,omitempty"`
+	Values     []ServiceDeskGetResponse `json:"values,omitempty"`
 }
 
 type ProjectLead struct {
 	Self      string `json:"self,omitempty"`
 	AccountId string `json:"accountId,omitempty"`
+	// Username of the lead on Jira Data Center
+	Name string `json:"name,omitempty"`
 }
 
 type ProjectCreateResponse struct {
@@ -61,17 +104,45 @@ type ProjectCreateResponse struct {
 	Key  string `json:"key"`
 }
 
-type CustomerAccessRequestBody struct {
-	autocompleteEnabled     bool
-	manageEnabled           bool
-	serviceDeskOpenAccess   bool
-	serviceDeskPublicSignup bool
+type CustomerAccess struct {
+	AutocompleteEnabled     bool `json:"autocompleteEnabled"`
+	ManageEnabled           bool `json:"manageEnabled"`
+	ServiceDeskOpenAccess   bool `json:"serviceDeskOpenAccess"`
+	ServiceDeskPublicSignup bool `json:"serviceDeskPublicSignup"`
+}
+
+// GetProjectByIdentifier reads a project with all of its schemes and, for service desks, its customer access
+func (c *jiraServiceDeskClient) GetProjectByIdentifier(ctx context.Context, id string) (Project, error) {
+	return c.getProject(ctx, id, allProjectSettings)
 }
 
-func (c *jiraServiceDeskClient) GetProjectByIdentifier(id string) (Project, error) {
+// GetDeclaredProject reads a project with only the schemes and customer access that are declared by the given
+// project, since each of them takes another call to JSD. The category is part of the project itself
+func (c *jiraServiceDeskClient) GetDeclaredProject(ctx context.Context, id string, declaredProject Project) (Project, error) {
+	return c.getProject(ctx, id, projectSettings{
+		permissionScheme:    declaredProject.PermissionScheme != 0,
+		notificationScheme:  declaredProject.NotificationScheme != 0,
+		issueSecurityScheme: declaredProject.IssueSecurityScheme != 0,
+		customerAccess:      declaredProject.CustomerAccess != nil,
+	})
+}
+
+// projectSettings selects the settings of a project that are read besides the project itself
+type projectSettings struct {
+	permissionScheme    bool
+	notificationScheme  bool
+	issueSecurityScheme bool
+	customerAccess      bool
+}
+
+var allProjectSettings = projectSettings{permissionScheme: true, notificationScheme: true, issueSecurityScheme: true, customerAccess: true}
+
+func (c *jiraServiceDeskClient) getProject(ctx context.Context, id string, settings projectSettings) (Project, error) {
 	var project Project
 
-	request, err := c.newRequest("GET", EndpointApiVersion3Project+"/"+id, nil, false)
+	adapter := c.adapter()
+
+	request, err := c.newRequest(ctx, "GET", adapter.projectPath()+"/"+id, nil, false)
 	if err != nil {
 		return project, err
 	}
@@ -83,7 +154,7 @@ func (c *jiraServiceDeskClient) GetProjectByIdentifier(id string) (Project, erro
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err := errors.New("Rest request to get Project failed with status: " + strconv.Itoa(response.StatusCode))
+		err := newAPIError("get Project", response, nil)
 		return project, err
 	}
 
@@ -94,11 +165,79 @@ func (c *jiraServiceDeskClient) GetProjectByIdentifier(id string) (Project, erro
 	}
 
 	project = projectGetResponseToProjectMapper(responseObject)
-	return project, err
+	project.LeadAccountId = adapter.userId(responseObject.Lead.AccountId, responseObject.Lead.Name)
+
+	// Team-managed projects have no schemes
+	if responseObject.Style != "next-gen" {
+		err = c.getProjectSchemes(ctx, &project, settings)
+		if err != nil {
+			return project, err
+		}
+	}
+
+	// Only service desks have request security settings
+	if settings.customerAccess && project.ProjectTypeKey == jiraservicedeskv1alpha1.ServiceDeskProjectTypeKey {
+		customerAccess, err := c.GetProjectAccessPermissions(ctx, project.Key)
+		if err != nil {
+			return project, err
+		}
+		project.CustomerAccess = &customerAccess
+	}
+	return project, nil
+}
+
+// getProjectSchemes reads the permission, notification and issue security schemes assigned to a project, which
+// are not returned by the get project REST API call
+func (c *jiraServiceDeskClient) getProjectSchemes(ctx context.Context, project *Project, settings projectSettings) error {
+	var err error
+
+	if settings.permissionScheme {
+		project.PermissionScheme, err = c.getProjectScheme(ctx, project.Id, PermissionSchemePath, "get project permission scheme")
+		if err != nil {
+			return err
+		}
+	}
+	if settings.notificationScheme {
+		project.NotificationScheme, err = c.getProjectScheme(ctx, project.Id, NotificationSchemePath, "get project notification scheme")
+		if err != nil {
+			return err
+		}
+	}
+	if settings.issueSecurityScheme {
+		project.IssueSecurityScheme, err = c.getProjectScheme(ctx, project.Id, IssueSecuritySchemePath, "get project issue security scheme")
+		// Projects without an issue security scheme are not found
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
 }
 
-func (c *jiraServiceDeskClient) CreateProject(project Project) (string, error) {
-	request, err := c.newRequest("POST", EndpointApiVersion3Project, project, false)
+func (c *jiraServiceDeskClient) getProjectScheme(ctx context.Context, id string, path string, operation string) (int, error) {
+	request, err := c.newRequest(ctx, "GET", c.adapter().projectPath()+"/"+id+path, nil, false)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return 0, newAPIError(operation, response, nil)
+	}
+
+	var responseObject ProjectSchemeResponse
+	err = json.NewDecoder(response.Body).Decode(&responseObject)
+	return responseObject.Id, err
+}
+
+func (c *jiraServiceDeskClient) CreateProject(ctx context.Context, project Project) (string, error) {
+	adapter := c.adapter()
+
+	request, err := c.newRequest(ctx, "POST", adapter.projectPath(), adapter.projectRequestBody(project), false)
 	if err != nil {
 		return "", err
 	}
@@ -112,8 +251,7 @@ func (c *jiraServiceDeskClient) CreateProject(project Project) (string, error) {
 	responseData, _ := ioutil.ReadAll(response.Body)
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err := errors.New("Rest request to create Project failed with status: " + strconv.Itoa(response.StatusCode) +
-			" and response: " + string(responseData))
+		err := newAPIError("create Project", response, responseData)
 		return "", err
 	}
 
@@ -127,8 +265,22 @@ func (c *jiraServiceDeskClient) CreateProject(project Project) (string, error) {
 	return projectId, err
 }
 
-func (c *jiraServiceDeskClient) UpdateProject(updatedProject Project, id string) error {
-	request, err := c.newRequest("PUT", EndpointApiVersion3Project+"/"+id, updatedProject, false)
+func (c *jiraServiceDeskClient) UpdateProject(ctx context.Context, updatedProject Project, id string) error {
+	adapter := c.adapter()
+
+	// Permission schemes are assigned through their own endpoint, the other schemes and the category with the project
+	if updatedProject.PermissionScheme != 0 {
+		err := c.AssignPermissionScheme(ctx, id, updatedProject.PermissionScheme)
+		if err != nil {
+			return err
+		}
+		updatedProject.PermissionScheme = 0
+		if updatedProject == (Project{}) {
+			return nil
+		}
+	}
+
+	request, err := c.newRequest(ctx, "PUT", adapter.projectPath()+"/"+id, adapter.projectRequestBody(updatedProject), false)
 	if err != nil {
 		return err
 	}
@@ -142,16 +294,25 @@ func (c *jiraServiceDeskClient) UpdateProject(updatedProject Project, id string)
 	responseData, _ := ioutil.ReadAll(response.Body)
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err := errors.New("Rest request to update Project failed with status: " + strconv.Itoa(response.StatusCode) +
-			" and response: " + string(responseData))
+		err := newAPIError("update Project", response, responseData)
 		return err
 	}
 
 	return nil
 }
 
-func (c *jiraServiceDeskClient) DeleteProject(id string) error {
-	request, err := c.newRequest("DELETE", EndpointApiVersion3Project+"/"+id, nil, false)
+// ProjectLeadExists reports whether the account of a project lead exists on JSD
+func (c *jiraServiceDeskClient) ProjectLeadExists(ctx context.Context, leadAccountId string) (bool, error) {
+	_, err := c.GetCustomerById(ctx, leadAccountId)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// AssignPermissionScheme assigns a permission scheme to a project
+func (c *jiraServiceDeskClient) AssignPermissionScheme(ctx context.Context, id string, schemeId int) error {
+	request, err := c.newRequest(ctx, "PUT", c.adapter().projectPath()+"/"+id+PermissionSchemePath, ProjectSchemeRequestBody{Id: schemeId}, false)
 	if err != nil {
 		return err
 	}
@@ -161,22 +322,61 @@ func (c *jiraServiceDeskClient) DeleteProject(id string) error {
 		return err
 	}
 
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		return errors.New("Rest request to delete Project failed with status: " + strconv.Itoa(response.StatusCode))
+		return newAPIError("assign project permission scheme", response, responseData)
+	}
+
+	return nil
+}
+
+func (c *jiraServiceDeskClient) DeleteProject(ctx context.Context, id string) error {
+	request, err := c.newRequest(ctx, "DELETE", c.adapter().projectPath()+"/"+id, nil, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return newAPIError("delete Project", response, nil)
 	}
 
 	return err
 }
 
-func (c *jiraServiceDeskClient) UpdateProjectAccessPermissions(status bool, key string) error {
-	body := CustomerAccessRequestBody{
-		autocompleteEnabled:     false,
-		manageEnabled:           false,
-		serviceDeskOpenAccess:   status,
-		serviceDeskPublicSignup: status,
+// GetProjectAccessPermissions reads the request security settings of a service desk project
+func (c *jiraServiceDeskClient) GetProjectAccessPermissions(ctx context.Context, key string) (CustomerAccess, error) {
+	var customerAccess CustomerAccess
+
+	request, err := c.newRequest(ctx, "GET", ServiceDeskV1ApiPath+key+RequestSecurityPath, nil, false)
+	if err != nil {
+		return customerAccess, err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return customerAccess, err
 	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := newAPIError("get project permissions", response, nil)
+		return customerAccess, err
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&customerAccess)
+	return customerAccess, err
+}
 
-	request, err := c.newRequest("POST", ServiceDeskV1ApiPath+key+RequestSecurityPath, body, false)
+func (c *jiraServiceDeskClient) UpdateProjectAccessPermissions(ctx context.Context, customerAccess CustomerAccess, key string) error {
+	request, err := c.newRequest(ctx, "POST", ServiceDeskV1ApiPath+key+RequestSecurityPath, customerAccess, false)
 	if err != nil {
 		return err
 	}
@@ -189,7 +389,7 @@ func (c *jiraServiceDeskClient) UpdateProjectAccessPermissions(status bool, key
 	defer response.Body.Close()
 
 	if response.StatusCode < 200 || response.StatusCode > 299 {
-		err := errors.New("Rest request to update project permissions failed with status: " + strconv.Itoa(response.StatusCode))
+		err := newAPIError("update project permissions", response, nil)
 		return err
 	}
 
@@ -197,17 +397,60 @@ func (c *jiraServiceDeskClient) UpdateProjectAccessPermissions(status bool, key
 }
 
 func (c *jiraServiceDeskClient) ProjectEqual(oldProject Project, newProject Project) bool {
-	// The fields AvatarId, IssueSecurityScheme, NotificationScheme, PermissionScheme, CategoryId are not retrieved
-	// through get project REST API call so they cannot be used in project comparison
-	return oldProject.Id == newProject.Id &&
-		oldProject.Name == newProject.Name &&
-		oldProject.Key == newProject.Key &&
-		oldProject.ProjectTypeKey == newProject.ProjectTypeKey &&
-		oldProject.ProjectTemplateKey == newProject.ProjectTemplateKey &&
-		oldProject.Description == newProject.Description &&
-		oldProject.AssigneeType == newProject.AssigneeType &&
-		oldProject.LeadAccountId == newProject.LeadAccountId &&
-		oldProject.URL == newProject.URL
+	return len(c.ProjectDiff(oldProject, newProject)) == 0
+}
+
+// ProjectDiff lists the spec fields in which two projects differ
+func (c *jiraServiceDeskClient) ProjectDiff(oldProject Project, newProject Project) []string {
+	// The field AvatarId is not retrieved through get project REST API call so it cannot be used in project comparison
+	var diff []string
+
+	if oldProject.Id != newProject.Id {
+		diff = append(diff, "id")
+	}
+	if oldProject.Name != newProject.Name {
+		diff = append(diff, "name")
+	}
+	if oldProject.Key != newProject.Key {
+		diff = append(diff, "key")
+	}
+	if oldProject.ProjectTypeKey != newProject.ProjectTypeKey {
+		diff = append(diff, "projectTypeKey")
+	}
+	if c.adapter().readsProjectTemplate() && oldProject.ProjectTemplateKey != newProject.ProjectTemplateKey {
+		diff = append(diff, "projectTemplateKey")
+	}
+	if oldProject.Description != newProject.Description {
+		diff = append(diff, "description")
+	}
+	if oldProject.AssigneeType != newProject.AssigneeType {
+		diff = append(diff, "assigneeType")
+	}
+	if oldProject.LeadAccountId != newProject.LeadAccountId {
+		diff = append(diff, "leadAccountId")
+	}
+	if oldProject.URL != newProject.URL {
+		diff = append(diff, "url")
+	}
+	// Schemes and categories that are not given are left as they are on JSD
+	if newProject.PermissionScheme != 0 && oldProject.PermissionScheme != newProject.PermissionScheme {
+		diff = append(diff, "permissionScheme")
+	}
+	if newProject.NotificationScheme != 0 && oldProject.NotificationScheme != newProject.NotificationScheme {
+		diff = append(diff, "notificationScheme")
+	}
+	if newProject.IssueSecurityScheme != 0 && oldProject.IssueSecurityScheme != newProject.IssueSecurityScheme {
+		diff = append(diff, "issueSecurityScheme")
+	}
+	if newProject.CategoryId != 0 && oldProject.CategoryId != newProject.CategoryId {
+		diff = append(diff, "categoryId")
+	}
+	// Customer access is only known for service desks that were read from JSD or declared in a spec
+	if oldProject.CustomerAccess != nil && newProject.CustomerAccess != nil && *oldProject.CustomerAccess != *newProject.CustomerAccess {
+		diff = append(diff, "customerAccess")
+	}
+
+	return diff
 }
 
 func (c *jiraServiceDeskClient) GetProjectFromProjectCR(project *jiraservicedeskv1alpha1.Project) Project {
@@ -235,9 +478,64 @@ func (c *jiraServiceDeskClient) GetProjectForUpdateRequest(existingProject Proje
 	if existingProject.AssigneeType != newProject.Spec.AssigneeType {
 		updatedProject.AssigneeType = newProject.Spec.AssigneeType
 	}
+	if existingProject.LeadAccountId != newProject.Spec.LeadAccountId {
+		updatedProject.LeadAccountId = newProject.Spec.LeadAccountId
+	}
 	if existingProject.URL != newProject.Spec.URL {
 		updatedProject.URL = newProject.Spec.URL
 	}
+	if newProject.Spec.PermissionScheme != 0 && existingProject.PermissionScheme != newProject.Spec.PermissionScheme {
+		updatedProject.PermissionScheme = newProject.Spec.PermissionScheme
+	}
+	if newProject.Spec.NotificationScheme != 0 && existingProject.NotificationScheme != newProject.Spec.NotificationScheme {
+		updatedProject.NotificationScheme = newProject.Spec.NotificationScheme
+	}
+	if newProject.Spec.IssueSecurityScheme != 0 && existingProject.IssueSecurityScheme != newProject.Spec.IssueSecurityScheme {
+		updatedProject.IssueSecurityScheme = newProject.Spec.IssueSecurityScheme
+	}
+	if newProject.Spec.CategoryId != 0 && existingProject.CategoryId != newProject.Spec.CategoryId {
+		updatedProject.CategoryId = newProject.Spec.CategoryId
+	}
 	return updatedProject
 
 }
+
+// GetServiceDeskProjectKeys lists the keys of the projects of all service desks on JSD
+func (c *jiraServiceDeskClient) GetServiceDeskProjectKeys(ctx context.Context) ([]string, error) {
+	var projectKeys []string
+
+	start := 0
+	for {
+		request, err := c.newRequest(ctx, "GET", ServiceDeskApiPath+"?start="+strconv.Itoa(start)+"&limit="+strconv.Itoa(ServiceDeskPageLimit), nil, false)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := c.do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			err := newAPIError("get service desks", response, nil)
+			response.Body.Close()
+			return nil, err
+		}
+
+		var responseObject ServiceDeskListResponse
+		err = json.NewDecoder(response.Body).Decode(&responseObject)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, serviceDesk := range responseObject.Values {
+			projectKeys = append(projectKeys, serviceDesk.ProjectKey)
+		}
+
+		if responseObject.IsLastPage || len(responseObject.Values) == 0 {
+			return projectKeys, nil
+		}
+		start += len(responseObject.Values)
+	}
+}
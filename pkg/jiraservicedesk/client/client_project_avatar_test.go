@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+func TestJiraService_UploadProjectAvatar_shouldReturnAvatarId_whenImageIsUploaded(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+EndpointApiVersion3Project).
+		Post("/SAMPLE"+ProjectAvatarUploadPath).
+		MatchHeader("Content-Type", "image/png").
+		MatchHeader("X-Atlassian-Token", "no-check").
+		Reply(201).
+		JSON(mockData.UploadProjectAvatarResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	avatarId, err := jiraClient.UploadProjectAvatar(context.TODO(), "SAMPLE", "image/png", mockData.AvatarPNG)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, avatarId, mockData.AvatarID)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_UploadProjectAvatar_shouldNotUpload_whenImageIsInvalid(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Post("/SAMPLE" + ProjectAvatarUploadPath).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	_, err := jiraClient.UploadProjectAvatar(context.TODO(), "SAMPLE", "image/png", []byte("not an image"))
+
+	st.Expect(t, err.Error(), mockData.UploadProjectAvatarFailedErrorMsg)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_SetProjectAvatar_shouldSelectAvatar_whenAvatarExists(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Put("/SAMPLE" + ProjectAvatarPath).
+		MatchType("json").
+		JSON(map[string]string{"id": mockData.AvatarID}).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.SetProjectAvatar(context.TODO(), "SAMPLE", mockData.AvatarID)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_DeleteProjectAvatar_shouldReturnNotFound_whenAvatarDoesNotExist(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Delete("/SAMPLE" + ProjectAvatarPath + "/" + mockData.AvatarID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteProjectAvatar(context.TODO(), "SAMPLE", mockData.AvatarID)
+
+	st.Expect(t, IsNotFound(err), true)
+	st.Expect(t, gock.IsDone(), true)
+}
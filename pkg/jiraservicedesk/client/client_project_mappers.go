@@ -1,6 +1,8 @@
 package client
 
 import (
+	"strconv"
+
 	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 )
 
@@ -26,6 +28,16 @@ func projectCRToProjectMapper(project *jiraservicedeskv1alpha1.Project) Project
 		projectObject.Id = project.Status.ID
 	}
 
+	// Customer access is only managed if it is declared in the spec
+	if customerAccess := project.Spec.CustomerAccess; customerAccess != nil {
+		projectObject.CustomerAccess = &CustomerAccess{
+			AutocompleteEnabled:     customerAccess.AutocompleteEnabled,
+			ManageEnabled:           customerAccess.ManageEnabled,
+			ServiceDeskOpenAccess:   customerAccess.OpenAccess,
+			ServiceDeskPublicSignup: customerAccess.PublicSignup,
+		}
+	}
+
 	return projectObject
 }
 
@@ -39,6 +51,9 @@ func projectGetResponseToProjectMapper(response ProjectGetResponse) Project {
 		}
 	}
 
+	// Projects without a category have no category id
+	categoryId, _ := strconv.Atoi(response.ProjectCategory.Id)
+
 	return Project{
 		Id:                 response.Id,
 		Name:               response.Name,
@@ -49,6 +64,7 @@ func projectGetResponseToProjectMapper(response ProjectGetResponse) Project {
 		AssigneeType:       response.AssigneeType,
 		LeadAccountId:      response.Lead.AccountId,
 		URL:                response.URL,
+		CategoryId:         categoryId,
 	}
 }
 
@@ -71,5 +87,14 @@ func projectToProjectCRMapper(project Project) jiraservicedeskv1alpha1.Project {
 	projectObject.Spec.NotificationScheme = project.NotificationScheme
 	projectObject.Spec.CategoryId = project.CategoryId
 
+	if project.CustomerAccess != nil {
+		projectObject.Spec.CustomerAccess = &jiraservicedeskv1alpha1.CustomerAccess{
+			OpenAccess:          project.CustomerAccess.ServiceDeskOpenAccess,
+			PublicSignup:        project.CustomerAccess.ServiceDeskPublicSignup,
+			AutocompleteEnabled: project.CustomerAccess.AutocompleteEnabled,
+			ManageEnabled:       project.CustomerAccess.ManageEnabled,
+		}
+	}
+
 	return projectObject
 }
@@ -1,16 +1,32 @@
 package client
 
 import (
-	"errors"
+	"context"
 	"testing"
 
 	"github.com/nbio/st"
 	"gopkg.in/h2non/gock.v1"
 
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
 	"github.com/stakater/jira-service-desk-operator/mock"
 	mockData "github.com/stakater/jira-service-desk-operator/mock"
 )
 
+// mockProjectSchemes mocks the scheme endpoints of a project without an issue security scheme
+func mockProjectSchemes(projectPath string) {
+	gock.New(mockData.BaseURL + projectPath).
+		Get("/" + mockData.ProjectID + PermissionSchemePath).
+		Reply(200).
+		JSON(mockData.PermissionSchemeResponseJSON)
+	gock.New(mockData.BaseURL + projectPath).
+		Get("/" + mockData.ProjectID + NotificationSchemePath).
+		Reply(200).
+		JSON(mockData.NotificationSchemeResponseJSON)
+	gock.New(mockData.BaseURL + projectPath).
+		Get("/" + mockData.ProjectID + IssueSecuritySchemePath).
+		Reply(404)
+}
+
 func TestJiraService_GetProject_shouldGetProject_whenValidProjectIdIsGiven(t *testing.T) {
 	defer gock.Off()
 
@@ -18,9 +34,10 @@ func TestJiraService_GetProject_shouldGetProject_whenValidProjectIdIsGiven(t *te
 		Get("/" + mockData.ProjectID).
 		Reply(200).
 		JSON(mockData.GetProjectByIdResponseJSON)
+	mockProjectSchemes(EndpointApiVersion3Project)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	project, err := jiraClient.GetProjectByIdentifier("/" + mockData.ProjectID)
+	project, err := jiraClient.GetProjectByIdentifier(context.TODO(), "/"+mockData.ProjectID)
 
 	st.Expect(t, project.Description, mockData.GetProjectByIdExpectedResponse.Description)
 	st.Expect(t, project.Name, mockData.GetProjectByIdExpectedResponse.Name)
@@ -28,6 +45,9 @@ func TestJiraService_GetProject_shouldGetProject_whenValidProjectIdIsGiven(t *te
 	st.Expect(t, project.ProjectTypeKey, mockData.GetProjectByIdExpectedResponse.ProjectTypeKey)
 	st.Expect(t, project.Key, mockData.GetProjectByIdExpectedResponse.Key)
 	st.Expect(t, project.URL, mockData.GetProjectByIdExpectedResponse.URL)
+	st.Expect(t, project.PermissionScheme, 10100)
+	st.Expect(t, project.NotificationScheme, 10000)
+	st.Expect(t, project.IssueSecurityScheme, 0)
 	st.Expect(t, err, nil)
 
 	st.Expect(t, gock.IsDone(), true)
@@ -41,9 +61,9 @@ func TestJiraService_GetProject_shouldNotGetProject_whenInValidProjectIdIsGiven(
 		Reply(404)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	_, err := jiraClient.GetProjectByIdentifier("/" + mockData.ProjectID)
+	_, err := jiraClient.GetProjectByIdentifier(context.TODO(), "/"+mockData.ProjectID)
 
-	st.Expect(t, err, errors.New(mockData.GetProjectFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.GetProjectFailedErrorMsg)
 	st.Expect(t, gock.IsDone(), true)
 }
 
@@ -69,7 +89,7 @@ func TestJiraService_CreateProject_shouldCreateProject_whenValidProjectDataIsGiv
 	}
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	id, err := jiraClient.CreateProject(sampleProject)
+	id, err := jiraClient.CreateProject(context.TODO(), sampleProject)
 
 	st.Expect(t, id, mockData.ProjectID)
 	st.Expect(t, err, nil)
@@ -89,9 +109,9 @@ func TestJiraService_CreateProject_shouldNotCreateProject_whenInValidProjectData
 	}
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	_, err := jiraClient.CreateProject(sampleProject)
+	_, err := jiraClient.CreateProject(context.TODO(), sampleProject)
 
-	st.Expect(t, err, errors.New(mockData.CreateProjectFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.CreateProjectFailedErrorMsg)
 	st.Expect(t, gock.IsDone(), true)
 }
 
@@ -110,7 +130,7 @@ func TestJiraService_UpdateProject_shouldUpdateProject_whenValidProjectIdIsGiven
 	}
 
 	client := NewClient("", mock.BaseURL, "")
-	err := client.UpdateProject(updateProject, mock.ProjectID)
+	err := client.UpdateProject(context.TODO(), updateProject, mock.ProjectID)
 	st.Expect(t, err, nil)
 
 	st.Expect(t, gock.IsDone(), true)
@@ -130,9 +150,31 @@ func TestJiraServiceDesk_UpdateProject_shouldNotUpdateProject_whenInvalidProject
 	}
 
 	client := NewClient("", mock.BaseURL, "")
-	err := client.UpdateProject(updateProject, mock.ProjectID)
+	err := client.UpdateProject(context.TODO(), updateProject, mock.ProjectID)
+
+	st.Expect(t, err.Error(), mockData.UpdateProjectFailedErrorMsg)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_UpdateProject_shouldAssignPermissionScheme_whenPermissionSchemeIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Put("/" + mockData.ProjectID + PermissionSchemePath).
+		MatchType("json").
+		JSON(mockData.AssignPermissionSchemeRequestJSON).
+		Reply(200).
+		JSON(mockData.PermissionSchemeResponseJSON)
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Put("/" + mockData.ProjectID).
+		MatchType("json").
+		JSON(map[string]int{"notificationScheme": 10101}).
+		Reply(200)
+
+	client := NewClient("", mockData.BaseURL, "")
+	err := client.UpdateProject(context.TODO(), Project{PermissionScheme: 10100, NotificationScheme: 10101}, mockData.ProjectID)
+	st.Expect(t, err, nil)
 
-	st.Expect(t, err, errors.New(mockData.UpdateProjectFailedErrorMsg))
 	st.Expect(t, gock.IsDone(), true)
 }
 
@@ -148,9 +190,9 @@ func TestJiraServiceDesk_UpdateProject_shouldNotUpdateProject_whenImmutableField
 	}
 
 	client := NewClient("", mock.BaseURL, "")
-	err := client.UpdateProject(updateProject, mock.ProjectID)
+	err := client.UpdateProject(context.TODO(), updateProject, mock.ProjectID)
 
-	st.Expect(t, err, errors.New(mockData.UpdateProjectFailedErrorMsg))
+	st.Expect(t, err.Error(), mockData.UpdateProjectFailedErrorMsg)
 	st.Expect(t, gock.IsDone(), true)
 }
 
@@ -163,7 +205,7 @@ func TestJiraService_DeleteProject_shouldDeleteProject_whenValidProjectIdIsGiven
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
 
-	err := jiraClient.DeleteProject(mockData.ProjectID)
+	err := jiraClient.DeleteProject(context.TODO(), mockData.ProjectID)
 	st.Expect(t, err, nil)
 
 	st.Expect(t, gock.IsDone(), true)
@@ -177,8 +219,215 @@ func TestJiraService_DeleteProject_shouldNotDeleteProject_whenInValidProjectIdIs
 		Reply(404)
 
 	jiraClient := NewClient("", mockData.BaseURL, "")
-	err := jiraClient.DeleteProject(mockData.ProjectID)
+	err := jiraClient.DeleteProject(context.TODO(), mockData.ProjectID)
+
+	st.Expect(t, err.Error(), mockData.DeleteProjectFailedErrorMsg)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_GetProject_shouldReadCustomerAccess_whenProjectIsServiceDesk(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Get("/" + mockData.ProjectID).
+		Reply(200).
+		JSON(mockData.GetServiceDeskProjectByIdResponseJSON)
+	mockProjectSchemes(EndpointApiVersion3Project)
+	gock.New(mockData.BaseURL + ServiceDeskV1ApiPath).
+		Get("SAMPLE" + RequestSecurityPath).
+		Reply(200).
+		JSON(mockData.CustomerAccessJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	project, err := jiraClient.GetProjectByIdentifier(context.TODO(), mockData.ProjectID)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, project.CustomerAccess, &CustomerAccess{AutocompleteEnabled: true, ServiceDeskOpenAccess: true, ServiceDeskPublicSignup: true})
+
+	projectCR := jiraClient.GetProjectCRFromProject(project)
+	st.Expect(t, projectCR.Spec.CustomerAccess.PublicSignup, true)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_GetDeclaredProject_shouldOnlyReadDeclaredSchemes_whenSchemesAreGiven(t *testing.T) {
+	defer gock.Off()
+
+	// Neither the other schemes nor the customer access of the service desk are read
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Get("/" + mockData.ProjectID).
+		Reply(200).
+		JSON(mockData.GetServiceDeskProjectByIdResponseJSON)
+	gock.New(mockData.BaseURL + EndpointApiVersion3Project).
+		Get("/" + mockData.ProjectID + PermissionSchemePath).
+		Reply(200).
+		JSON(mockData.PermissionSchemeResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	project, err := jiraClient.GetDeclaredProject(context.TODO(), mockData.ProjectID, Project{PermissionScheme: 10001})
+
+	st.Expect(t, err, nil)
+	st.Expect(t, project.PermissionScheme != 0, true)
+	st.Expect(t, project.NotificationScheme, 0)
+	st.Expect(t, project.CustomerAccess == nil, true)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_UpdateProjectAccessPermissions_shouldSendCustomerAccess_whenCustomerAccessIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + ServiceDeskV1ApiPath).
+		Post("SAMPLE" + RequestSecurityPath).
+		MatchType("json").
+		JSON(mockData.CustomerAccessJSON).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateProjectAccessPermissions(context.TODO(), CustomerAccess{AutocompleteEnabled: true, ServiceDeskOpenAccess: true, ServiceDeskPublicSignup: true}, "SAMPLE")
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_UpdateProjectAccessPermissions_shouldNotUpdate_whenProjectIsNotServiceDesk(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + ServiceDeskV1ApiPath).
+		Post("SAMPLE" + RequestSecurityPath).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateProjectAccessPermissions(context.TODO(), CustomerAccess{}, "SAMPLE")
+
+	st.Expect(t, err.Error(), mockData.UpdateProjectAccessPermissionsFailedErrorMsg)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_ProjectDiff_shouldListChangedFields_whenProjectsDiffer(t *testing.T) {
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	project := jiraClient.GetProjectFromProjectCR(&mockData.CreateProjectInput)
+	st.Expect(t, len(jiraClient.ProjectDiff(project, project)), 0)
+	st.Expect(t, jiraClient.ProjectEqual(project, project), true)
+
+	changedProject := project
+	changedProject.Name = "Changed Name"
+	changedProject.Description = "Changed description"
+
+	st.Expect(t, jiraClient.ProjectDiff(project, changedProject), []string{"name", "description"})
+	st.Expect(t, jiraClient.ProjectEqual(project, changedProject), false)
+}
+
+func TestJiraService_ProjectDiff_shouldListSchemes_whenSchemesAreGivenAndDiffer(t *testing.T) {
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	existingProject := Project{PermissionScheme: 10100, NotificationScheme: 10000, CategoryId: 10300}
+
+	st.Expect(t, len(jiraClient.ProjectDiff(existingProject, Project{})), 0)
+	st.Expect(t, jiraClient.ProjectDiff(existingProject, Project{PermissionScheme: 10101, NotificationScheme: 10000, IssueSecurityScheme: 10200, CategoryId: 10301}),
+		[]string{"permissionScheme", "issueSecurityScheme", "categoryId"})
+}
+
+func TestJiraService_ProjectDiff_shouldListCustomerAccess_whenCustomerAccessDiffers(t *testing.T) {
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	projectCR := mockData.CreateProjectInput
+	project := jiraClient.GetProjectFromProjectCR(&projectCR)
+	project.CustomerAccess = &CustomerAccess{}
+
+	projectCR.Spec.CustomerAccess = &jiraservicedeskv1alpha1.CustomerAccess{OpenAccess: true, ManageEnabled: true}
+	changedProject := jiraClient.GetProjectFromProjectCR(&projectCR)
+
+	st.Expect(t, jiraClient.ProjectDiff(project, changedProject), []string{"customerAccess"})
+}
+
+func TestJiraService_GetProjectFromProjectCR_shouldNotSetCustomerAccess_whenCustomerAccessIsNotGiven(t *testing.T) {
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	projectCR := mockData.CreateProjectInput
+	projectCR.Spec.OpenAccess = true
+	project := jiraClient.GetProjectFromProjectCR(&projectCR)
+	st.Expect(t, project.CustomerAccess, (*CustomerAccess)(nil))
+
+	existingProject := jiraClient.GetProjectFromProjectCR(&projectCR)
+	existingProject.CustomerAccess = &CustomerAccess{AutocompleteEnabled: true, ManageEnabled: true}
+	st.Expect(t, len(jiraClient.ProjectDiff(existingProject, project)), 0)
+}
+
+func TestJiraService_GetProjectForUpdateRequest_shouldSetLead_whenLeadIsChanged(t *testing.T) {
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	projectCR := mockData.CreateProjectInput
+	existingProject := jiraClient.GetProjectFromProjectCR(&projectCR)
+	st.Expect(t, jiraClient.GetProjectForUpdateRequest(existingProject, &projectCR), Project{})
+
+	projectCR.Spec.LeadAccountId = mockData.CustomerAccountId
+	st.Expect(t, jiraClient.GetProjectForUpdateRequest(existingProject, &projectCR), Project{LeadAccountId: mockData.CustomerAccountId})
+}
+
+func TestJiraService_ProjectLeadExists_shouldReturnTrue_whenLeadExists(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+EndpointUser).
+		Get("/").
+		MatchParam("accountId", mockData.CustomerAccountId).
+		Reply(200).
+		JSON(mockData.GetCustomerResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	exists, err := jiraClient.ProjectLeadExists(context.TODO(), mockData.CustomerAccountId)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, exists, true)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_ProjectLeadExists_shouldReturnFalse_whenLeadDoesNotExist(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+EndpointUser).
+		Get("/").
+		MatchParam("accountId", mockData.CustomerAccountId).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	exists, err := jiraClient.ProjectLeadExists(context.TODO(), mockData.CustomerAccountId)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, exists, false)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_GetServiceDeskProjectKeys_shouldListProjectKeys_whenServiceDesksExist(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+ServiceDeskApiPath).
+		Get("").
+		MatchParam("start", "0").
+		Reply(200).
+		JSON(mockData.ListServiceDesksResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	projectKeys, err := jiraClient.GetServiceDeskProjectKeys(context.TODO())
+
+	st.Expect(t, projectKeys, []string{"SAMPLE", "OTHER"})
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraService_GetServiceDeskProjectKeys_shouldNotListProjectKeys_whenRequestFails(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + ServiceDeskApiPath).
+		Get("").
+		Reply(401)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	projectKeys, err := jiraClient.GetServiceDeskProjectKeys(context.TODO())
+
+	st.Expect(t, len(projectKeys), 0)
+	st.Expect(t, err.Error(), mockData.GetServiceDesksFailedErrorMsg)
 
-	st.Expect(t, err, errors.New(mockData.DeleteProjectFailedErrorMsg))
 	st.Expect(t, gock.IsDone(), true)
 }
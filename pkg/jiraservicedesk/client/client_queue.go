@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+const (
+	// Endpoints
+	QueueApiPath         = "/queue"
+	InternalQueueApiPath = "/rest/servicedesk/1/servicedesk/"
+	InternalQueuesPath   = "/queues"
+)
+
+type Queue struct {
+	Id      string   `json:"id,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	JQL     string   `json:"jql,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+type QueueRequestBody struct {
+	Name    string   `json:"name,omitempty"`
+	JQL     string   `json:"jql,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+type QueueGetResponse struct {
+	Id     string   `json:"id,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	JQL    string   `json:"jql,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+type QueueCreateResponse struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type QueueDeleteRequestBody struct {
+	Deleted []int `json:"deleted"`
+}
+
+// GetQueueById gets a queue of a JSD project's service desk by ID
+func (c *jiraServiceDeskClient) GetQueueById(ctx context.Context, projectKey string, queueId string) (Queue, error) {
+	var queue Queue
+
+	request, err := c.newRequest(ctx, "GET", AddCustomerApiPath+projectKey+QueueApiPath+"/"+queueId, nil, false)
+	if err != nil {
+		return queue, err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return queue, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := newAPIError("get queue", response, nil)
+		return queue, err
+	}
+
+	var responseObject QueueGetResponse
+	err = json.NewDecoder(response.Body).Decode(&responseObject)
+	if err != nil {
+		return queue, err
+	}
+
+	queue = queueGetResponseToQueueMapper(responseObject)
+
+	return queue, err
+}
+
+// CreateQueue creates a new queue in the service desk of a JSD project
+// The servicedeskapi only supports reading queues, so the internal api used by the JSD UI is used
+func (c *jiraServiceDeskClient) CreateQueue(ctx context.Context, projectKey string, queue Queue) (string, error) {
+	body := QueueRequestBody{
+		Name:    queue.Name,
+		JQL:     queue.JQL,
+		Columns: queue.Columns,
+	}
+
+	request, err := c.newRequest(ctx, "POST", InternalQueueApiPath+projectKey+InternalQueuesPath, body, false)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("create queue", response, responseData)
+		return "", err
+	}
+
+	var responseObject QueueCreateResponse
+	err = json.Unmarshal(responseData, &responseObject)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(responseObject.Id), err
+}
+
+// UpdateQueue updates the name, JQL and columns of an existing queue
+func (c *jiraServiceDeskClient) UpdateQueue(ctx context.Context, projectKey string, queueId string, queue Queue) error {
+	body := QueueRequestBody{
+		Name:    queue.Name,
+		JQL:     queue.JQL,
+		Columns: queue.Columns,
+	}
+
+	request, err := c.newRequest(ctx, "PUT", InternalQueueApiPath+projectKey+InternalQueuesPath+"/"+queueId, body, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("update queue", response, responseData)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteQueue deletes a queue from the service desk of a JSD project
+func (c *jiraServiceDeskClient) DeleteQueue(ctx context.Context, projectKey string, queueId string) error {
+	id, err := strconv.Atoi(queueId)
+	if err != nil {
+		return errors.New("Invalid queue id: " + queueId)
+	}
+
+	body := QueueDeleteRequestBody{
+		Deleted: []int{id},
+	}
+
+	request, err := c.newRequest(ctx, "DELETE", InternalQueueApiPath+projectKey+InternalQueuesPath, body, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("delete queue", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+func (c *jiraServiceDeskClient) IsQueueUpdated(queue *jiraservicedeskv1alpha1.Queue, existingQueue Queue) bool {
+	return len(c.QueueDiff(queue, existingQueue)) > 0
+}
+
+// QueueDiff lists the fields of the spec that differ from the queue on JSD
+func (c *jiraServiceDeskClient) QueueDiff(queue *jiraservicedeskv1alpha1.Queue, existingQueue Queue) []string {
+	var diff []string
+	newQueue := queueCRToQueueMapper(queue)
+
+	if newQueue.Name != existingQueue.Name {
+		diff = append(diff, "name")
+	}
+	// The ordering is part of the JQL of the queue on JSD
+	if newQueue.JQL != existingQueue.JQL {
+		diff = append(diff, "jql")
+	}
+	// Queues without columns keep the columns chosen by JSD
+	if len(newQueue.Columns) > 0 && !reflect.DeepEqual(newQueue.Columns, existingQueue.Columns) {
+		diff = append(diff, "columns")
+	}
+
+	return diff
+}
+
+func (c *jiraServiceDeskClient) GetQueueFromQueueCR(queue *jiraservicedeskv1alpha1.Queue) Queue {
+	return queueCRToQueueMapper(queue)
+}
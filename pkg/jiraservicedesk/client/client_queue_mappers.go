@@ -0,0 +1,26 @@
+package client
+
+import jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+
+func queueCRToQueueMapper(queue *jiraservicedeskv1alpha1.Queue) Queue {
+	queueObject := Queue{
+		Name:    queue.Spec.Name,
+		JQL:     queue.GetQueueJQL(),
+		Columns: queue.Spec.Columns,
+	}
+
+	if len(queue.Status.QueueId) > 0 {
+		queueObject.Id = queue.Status.QueueId
+	}
+
+	return queueObject
+}
+
+func queueGetResponseToQueueMapper(response QueueGetResponse) Queue {
+	return Queue{
+		Id:      response.Id,
+		Name:    response.Name,
+		JQL:     response.JQL,
+		Columns: response.Fields,
+	}
+}
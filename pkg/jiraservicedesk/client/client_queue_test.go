@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nbio/st"
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var sampleQueue = Queue{
+	Name:    "Sample Queue",
+	JQL:     "resolution = EMPTY ORDER BY created DESC",
+	Columns: []string{"issuekey", "summary"},
+}
+
+func TestJiraClient_GetQueueById_shouldGetQueue_whenValidQueueIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.QueueProjectKey + QueueApiPath).
+		Get("/" + mockData.QueueID).
+		Reply(200).
+		JSON(mockData.GetQueueResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	queue, err := jiraClient.GetQueueById(context.TODO(), mockData.QueueProjectKey, mockData.QueueID)
+
+	st.Expect(t, queue.Id, mockData.QueueID)
+	st.Expect(t, queue.Name, "Sample Queue")
+	st.Expect(t, queue.JQL, "resolution = EMPTY ORDER BY created DESC")
+	st.Expect(t, queue.Columns, []string{"issuekey", "summary"})
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetQueueById_shouldNotGetQueue_whenInValidQueueIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.QueueProjectKey + QueueApiPath).
+		Get("/" + mockData.QueueID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	queue, err := jiraClient.GetQueueById(context.TODO(), mockData.QueueProjectKey, mockData.QueueID)
+
+	st.Expect(t, queue.Id, "")
+	st.Expect(t, err.Error(), mockData.GetQueueFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateQueue_shouldCreateQueue_whenValidQueueDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + InternalQueueApiPath + mockData.QueueProjectKey).
+		Post(InternalQueuesPath).
+		MatchType("json").
+		JSON(mockData.CreateQueueInputJSON).
+		Reply(200).
+		JSON(mockData.CreateQueueResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateQueue(context.TODO(), mockData.QueueProjectKey, sampleQueue)
+
+	st.Expect(t, id, mockData.QueueID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateQueue_shouldNotCreateQueue_whenInValidQueueDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + InternalQueueApiPath + mockData.QueueProjectKey).
+		Post(InternalQueuesPath).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateQueue(context.TODO(), mockData.QueueProjectKey, Queue{})
+
+	st.Expect(t, id, "")
+	st.Expect(t, err.Error(), mockData.CreateQueueFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateQueue_shouldUpdateQueue_whenValidQueueDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + InternalQueueApiPath + mockData.QueueProjectKey + InternalQueuesPath).
+		Put("/" + mockData.QueueID).
+		MatchType("json").
+		JSON(mockData.CreateQueueInputJSON).
+		Reply(200)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateQueue(context.TODO(), mockData.QueueProjectKey, mockData.QueueID, sampleQueue)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateQueue_shouldNotUpdateQueue_whenInValidQueueDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + InternalQueueApiPath + mockData.QueueProjectKey + InternalQueuesPath).
+		Put("/" + mockData.QueueID).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateQueue(context.TODO(), mockData.QueueProjectKey, mockData.QueueID, Queue{})
+
+	st.Expect(t, err.Error(), mockData.UpdateQueueFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteQueue_shouldDeleteQueue_whenValidQueueIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + InternalQueueApiPath + mockData.QueueProjectKey).
+		Delete(InternalQueuesPath).
+		MatchType("json").
+		JSON(mockData.DeleteQueueRequestJSON).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteQueue(context.TODO(), mockData.QueueProjectKey, mockData.QueueID)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteQueue_shouldNotDeleteQueue_whenInValidQueueIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + InternalQueueApiPath + mockData.QueueProjectKey).
+		Delete(InternalQueuesPath).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteQueue(context.TODO(), mockData.QueueProjectKey, mockData.QueueID)
+
+	st.Expect(t, err.Error(), mockData.DeleteQueueFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_IsQueueUpdated_shouldDetectChanges_whenSpecDiffersFromQueue(t *testing.T) {
+	queue := mockData.SampleQueue.DeepCopy()
+	queue.Spec.Name = "Sample Queue"
+	queue.Spec.JQL = "resolution = EMPTY"
+	queue.Spec.Columns = []string{"issuekey", "summary"}
+	queue.Spec.OrderBy = &jiraservicedeskv1alpha1.QueueOrderBy{Field: "created", Direction: "DESC"}
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	st.Expect(t, jiraClient.IsQueueUpdated(queue, sampleQueue), false)
+
+	queue.Spec.OrderBy.Direction = "ASC"
+	st.Expect(t, jiraClient.IsQueueUpdated(queue, sampleQueue), true)
+}
+
+func TestJiraClient_QueueDiff_shouldListChangedFields_whenSpecDiffersFromQueue(t *testing.T) {
+	queue := mockData.SampleQueue.DeepCopy()
+	queue.Spec.Name = "Sample Queue"
+	queue.Spec.JQL = "resolution = EMPTY"
+	queue.Spec.Columns = []string{"issuekey", "summary"}
+	queue.Spec.OrderBy = &jiraservicedeskv1alpha1.QueueOrderBy{Field: "created", Direction: "DESC"}
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	st.Expect(t, len(jiraClient.QueueDiff(queue, sampleQueue)), 0)
+
+	queue.Spec.Name = "Renamed Queue"
+	queue.Spec.Columns = []string{"issuekey"}
+	st.Expect(t, jiraClient.QueueDiff(queue, sampleQueue), []string{"name", "columns"})
+
+	// Queues without columns keep the columns chosen by JSD
+	queue.Spec.Columns = nil
+	st.Expect(t, jiraClient.QueueDiff(queue, sampleQueue), []string{"name"})
+}
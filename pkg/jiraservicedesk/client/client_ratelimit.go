@@ -0,0 +1,85 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var rateLimiterWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "jira_service_desk_rate_limiter_wait_seconds",
+	Help:    "Time requests to Jira Service Desk waited in the client side rate limiter",
+	Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+}, []string{"site"})
+
+func init() {
+	metrics.Registry.MustRegister(rateLimiterWaitSeconds)
+}
+
+// RateLimit configures the token bucket that limits the requests sent to a Jira site
+type RateLimit struct {
+	// Requests per second, 0 disables the rate limit
+	QPS float64
+	// Maximum number of requests sent at once, at least 1
+	Burst int
+}
+
+// siteLimiterKey identifies a rate limiter by the host of a Jira site and its limit
+type siteLimiterKey struct {
+	site  string
+	limit RateLimit
+}
+
+// siteLimiters holds the rate limiters by Jira site and limit, so that all clients for a site with the same
+// limit share it. Clients configured with another limit for the same site get a limiter of their own
+var siteLimiters = struct {
+	sync.Mutex
+	limiters map[siteLimiterKey]*rate.Limiter
+}{limiters: map[siteLimiterKey]*rate.Limiter{}}
+
+// getSiteLimiter returns the rate limiter of a Jira site with the given limit, creating it on first use
+func getSiteLimiter(site string, limit RateLimit) *rate.Limiter {
+	siteLimiters.Lock()
+	defer siteLimiters.Unlock()
+
+	key := siteLimiterKey{site: site, limit: limit}
+	limiter, ok := siteLimiters.limiters[key]
+	if !ok {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit.QPS), burst)
+		siteLimiters.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// rateLimitTransport waits for the rate limiter of the Jira site before sending a request
+type rateLimitTransport struct {
+	// Transport used for the requests, http.DefaultTransport if nil
+	base  http.RoundTripper
+	limit RateLimit
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if t.limit.QPS > 0 {
+		start := time.Now()
+		err := getSiteLimiter(req.URL.Host, t.limit).Wait(req.Context())
+		rateLimiterWaitSeconds.WithLabelValues(req.URL.Host).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+func TestJiraClient_rateLimit_shouldDelayRequests_whenBurstIsExceeded(t *testing.T) {
+	defer gock.Off()
+
+	baseURL := "https://ratelimited.atlassian.net"
+
+	gock.New(baseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Times(4).
+		Reply(200).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	limit := WithRateLimit(RateLimit{QPS: 20, Burst: 1})
+	jiraClient := NewClient("", baseURL, "", limit)
+	otherJiraClient := NewClient("", baseURL, "", limit)
+	siteJiraClient := jiraClient.WithSite(Site{BaseURL: baseURL, Auth: BasicAuth{}})
+
+	start := time.Now()
+	for _, c := range []Client{jiraClient, otherJiraClient, jiraClient, siteJiraClient} {
+		_, err := c.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+		st.Expect(t, err, nil)
+	}
+
+	// All clients share the limiter of the site, including the one for other credentials, so every request after the
+	// first waits 50ms
+	st.Expect(t, time.Since(start) >= 140*time.Millisecond, true)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestGetSiteLimiter_shouldShareLimiter_whenSiteIsTheSame(t *testing.T) {
+	limit := RateLimit{QPS: 10, Burst: 5}
+
+	limiter := getSiteLimiter("first.atlassian.net", limit)
+
+	st.Expect(t, getSiteLimiter("first.atlassian.net", limit) == limiter, true)
+	st.Expect(t, getSiteLimiter("second.atlassian.net", limit) == limiter, false)
+	st.Expect(t, limiter.Burst(), 5)
+}
+
+func TestGetSiteLimiter_shouldUseOwnLimiter_whenLimitIsDifferent(t *testing.T) {
+	limiter := getSiteLimiter("limits.atlassian.net", RateLimit{QPS: 10, Burst: 5})
+	otherLimiter := getSiteLimiter("limits.atlassian.net", RateLimit{QPS: 1, Burst: 2})
+
+	st.Expect(t, otherLimiter == limiter, false)
+	st.Expect(t, limiter.Burst(), 5)
+	st.Expect(t, otherLimiter.Burst(), 2)
+	st.Expect(t, float64(otherLimiter.Limit()), 1.0)
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+const (
+	// Endpoints
+	RequestTypeApiPath = "/requesttype"
+)
+
+type RequestType struct {
+	Id          string   `json:"id,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	HelpText    string   `json:"helpText,omitempty"`
+	IssueTypeId string   `json:"issueTypeId,omitempty"`
+	GroupIds    []string `json:"groupIds,omitempty"`
+}
+
+type RequestTypeRequestBody struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	HelpText    string   `json:"helpText,omitempty"`
+	IssueTypeId string   `json:"issueTypeId,omitempty"`
+	GroupIds    []string `json:"groupIds,omitempty"`
+}
+
+type RequestTypeGetResponse struct {
+	Id            string   `json:"id,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	HelpText      string   `json:"helpText,omitempty"`
+	IssueTypeId   string   `json:"issueTypeId,omitempty"`
+	ServiceDeskId string   `json:"serviceDeskId,omitempty"`
+	GroupIds      []string `json:"groupIds,omitempty"`
+}
+
+// GetRequestTypeById gets a request type of a JSD project's service desk by ID
+func (c *jiraServiceDeskClient) GetRequestTypeById(ctx context.Context, projectKey string, requestTypeId string) (RequestType, error) {
+	var requestType RequestType
+
+	request, err := c.newRequest(ctx, "GET", AddCustomerApiPath+projectKey+RequestTypeApiPath+"/"+requestTypeId, nil, false)
+	if err != nil {
+		return requestType, err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return requestType, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := newAPIError("get request type", response, nil)
+		return requestType, err
+	}
+
+	var responseObject RequestTypeGetResponse
+	err = json.NewDecoder(response.Body).Decode(&responseObject)
+	if err != nil {
+		return requestType, err
+	}
+
+	requestType = requestTypeGetResponseToRequestTypeMapper(responseObject)
+
+	return requestType, err
+}
+
+// CreateRequestType creates a new request type in the service desk of a JSD project
+func (c *jiraServiceDeskClient) CreateRequestType(ctx context.Context, projectKey string, requestType RequestType) (string, error) {
+	body := RequestTypeRequestBody{
+		Name:        requestType.Name,
+		Description: requestType.Description,
+		HelpText:    requestType.HelpText,
+		IssueTypeId: requestType.IssueTypeId,
+		GroupIds:    requestType.GroupIds,
+	}
+
+	request, err := c.newRequest(ctx, "POST", AddCustomerApiPath+projectKey+RequestTypeApiPath, body, true)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("create request type", response, responseData)
+		return "", err
+	}
+
+	var responseObject RequestTypeGetResponse
+	err = json.Unmarshal(responseData, &responseObject)
+	if err != nil {
+		return "", err
+	}
+
+	return responseObject.Id, err
+}
+
+// DeleteRequestType deletes a request type from the service desk of a JSD project
+func (c *jiraServiceDeskClient) DeleteRequestType(ctx context.Context, projectKey string, requestTypeId string) error {
+	request, err := c.newRequest(ctx, "DELETE", AddCustomerApiPath+projectKey+RequestTypeApiPath+"/"+requestTypeId, nil, true)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("delete request type", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+func (c *jiraServiceDeskClient) GetRequestTypeFromRequestTypeCR(requestType *jiraservicedeskv1alpha1.RequestType) RequestType {
+	return requestTypeCRToRequestTypeMapper(requestType)
+}
+
+// RequestTypeDiff lists the fields of the spec that differ from the request type on JSD
+func (c *jiraServiceDeskClient) RequestTypeDiff(requestType *jiraservicedeskv1alpha1.RequestType, existingRequestType RequestType) []string {
+	var diff []string
+	newRequestType := requestTypeCRToRequestTypeMapper(requestType)
+
+	if newRequestType.Name != existingRequestType.Name {
+		diff = append(diff, "name")
+	}
+	if newRequestType.Description != existingRequestType.Description {
+		diff = append(diff, "description")
+	}
+	if newRequestType.HelpText != existingRequestType.HelpText {
+		diff = append(diff, "helpText")
+	}
+	if newRequestType.IssueTypeId != existingRequestType.IssueTypeId {
+		diff = append(diff, "issueTypeId")
+	}
+	if (len(newRequestType.GroupIds) > 0 || len(existingRequestType.GroupIds) > 0) &&
+		!reflect.DeepEqual(newRequestType.GroupIds, existingRequestType.GroupIds) {
+		diff = append(diff, "groupIds")
+	}
+
+	return diff
+}
@@ -0,0 +1,30 @@
+package client
+
+import jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+
+func requestTypeCRToRequestTypeMapper(requestType *jiraservicedeskv1alpha1.RequestType) RequestType {
+	requestTypeObject := RequestType{
+		Name:        requestType.Spec.Name,
+		Description: requestType.Spec.Description,
+		HelpText:    requestType.Spec.HelpText,
+		IssueTypeId: requestType.Spec.IssueTypeId,
+		GroupIds:    requestType.Spec.GroupIds,
+	}
+
+	if len(requestType.Status.RequestTypeId) > 0 {
+		requestTypeObject.Id = requestType.Status.RequestTypeId
+	}
+
+	return requestTypeObject
+}
+
+func requestTypeGetResponseToRequestTypeMapper(response RequestTypeGetResponse) RequestType {
+	return RequestType{
+		Id:          response.Id,
+		Name:        response.Name,
+		Description: response.Description,
+		HelpText:    response.HelpText,
+		IssueTypeId: response.IssueTypeId,
+		GroupIds:    response.GroupIds,
+	}
+}
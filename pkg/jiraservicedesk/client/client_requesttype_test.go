@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nbio/st"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestJiraClient_GetRequestTypeById_shouldGetRequestType_whenValidRequestTypeIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RequestTypeProjectKey + RequestTypeApiPath).
+		Get("/" + mockData.RequestTypeID).
+		Reply(200).
+		JSON(mockData.GetRequestTypeResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	requestType, err := jiraClient.GetRequestTypeById(context.TODO(), mockData.RequestTypeProjectKey, mockData.RequestTypeID)
+
+	st.Expect(t, requestType.Id, mockData.RequestTypeID)
+	st.Expect(t, requestType.Name, "Sample Request Type")
+	st.Expect(t, requestType.IssueTypeId, "10001")
+	st.Expect(t, requestType.GroupIds, []string{"2"})
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetRequestTypeById_shouldNotGetRequestType_whenInValidRequestTypeIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RequestTypeProjectKey + RequestTypeApiPath).
+		Get("/" + mockData.RequestTypeID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	requestType, err := jiraClient.GetRequestTypeById(context.TODO(), mockData.RequestTypeProjectKey, mockData.RequestTypeID)
+
+	st.Expect(t, requestType.Id, "")
+	st.Expect(t, err.Error(), mockData.GetRequestTypeFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateRequestType_shouldCreateRequestType_whenValidRequestTypeDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RequestTypeProjectKey).
+		Post(RequestTypeApiPath).
+		MatchType("json").
+		JSON(mockData.CreateRequestTypeInputJSON).
+		Reply(201).
+		JSON(mockData.GetRequestTypeResponseJSON)
+
+	requestType := RequestType{
+		Name:        "Sample Request Type",
+		Description: "Sample description",
+		HelpText:    "Sample help text",
+		IssueTypeId: "10001",
+		GroupIds:    []string{"2"},
+	}
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateRequestType(context.TODO(), mockData.RequestTypeProjectKey, requestType)
+
+	st.Expect(t, id, mockData.RequestTypeID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateRequestType_shouldNotCreateRequestType_whenInValidRequestTypeDataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RequestTypeProjectKey).
+		Post(RequestTypeApiPath).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateRequestType(context.TODO(), mockData.RequestTypeProjectKey, RequestType{})
+
+	st.Expect(t, id, "")
+	st.Expect(t, err.Error(), mockData.CreateRequestTypeFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteRequestType_shouldDeleteRequestType_whenValidRequestTypeIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RequestTypeProjectKey + RequestTypeApiPath).
+		Delete("/" + mockData.RequestTypeID).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteRequestType(context.TODO(), mockData.RequestTypeProjectKey, mockData.RequestTypeID)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteRequestType_shouldNotDeleteRequestType_whenInValidRequestTypeIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + AddCustomerApiPath + mockData.RequestTypeProjectKey + RequestTypeApiPath).
+		Delete("/" + mockData.RequestTypeID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteRequestType(context.TODO(), mockData.RequestTypeProjectKey, mockData.RequestTypeID)
+
+	st.Expect(t, err.Error(), mockData.DeleteRequestTypeFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_RequestTypeDiff_shouldListChangedFields_whenSpecDiffersFromRequestType(t *testing.T) {
+	requestType := mockData.SampleRequestType.DeepCopy()
+	existingRequestType := RequestType{
+		Id:          mockData.RequestTypeID,
+		Name:        requestType.Spec.Name,
+		Description: requestType.Spec.Description,
+		HelpText:    requestType.Spec.HelpText,
+		IssueTypeId: requestType.Spec.IssueTypeId,
+		GroupIds:    []string{},
+	}
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	st.Expect(t, len(jiraClient.RequestTypeDiff(requestType, existingRequestType)), 0)
+
+	requestType.Spec.HelpText = "Describe the problem"
+	requestType.Spec.GroupIds = []string{"2"}
+	st.Expect(t, jiraClient.RequestTypeDiff(requestType, existingRequestType), []string{"helpText", "groupIds"})
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests to JSD are retried when they are rate limited or fail with a server error
+type RetryPolicy struct {
+	// Maximum number of retries of a request, 0 disables retries
+	MaxRetries int
+	// Backoff before the first retry, which is doubled on every further retry
+	MinBackoff time.Duration
+	// Maximum backoff between retries. Requests asking for a longer Retry-After are not retried
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used by clients that are not given a retry policy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// retryTransport retries requests that were rate limited (429), are unavailable (503) or failed with another
+// server error. Rate limited requests are retried for all verbs after the time given in Retry-After, as they have
+// not been processed. Other failures are only retried for idempotent verbs, using jittered exponential backoff
+type retryTransport struct {
+	// Transport used for the requests, http.DefaultTransport if nil
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := base.RoundTrip(req)
+
+		if attempt >= t.policy.MaxRetries {
+			return response, err
+		}
+
+		backoff, retry := t.backoff(req, response, err, attempt)
+		if !retry {
+			return response, err
+		}
+
+		// Request bodies have to be read again for the retry
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return response, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return response, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		if response != nil {
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		Log.Info("Retrying request to JSD", "method", req.Method, "path", req.URL.Path, "attempt", attempt+1, "backoff", backoff.String())
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns how long to wait before retrying the request, and whether it should be retried at all
+func (t *retryTransport) backoff(req *http.Request, response *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err == nil && (response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable) {
+		if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			return retryAfter, retryAfter <= t.policy.MaxBackoff
+		}
+		if response.StatusCode == http.StatusTooManyRequests {
+			return t.exponentialBackoff(attempt), true
+		}
+	}
+
+	if !isIdempotent(req.Method) {
+		return 0, false
+	}
+	if err != nil || response.StatusCode >= 500 {
+		return t.exponentialBackoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// exponentialBackoff doubles the minimum backoff on every attempt, with jitter of up to half of the backoff
+func (t *retryTransport) exponentialBackoff(attempt int) time.Duration {
+	backoff := t.policy.MinBackoff << uint(attempt)
+	if backoff <= 0 || backoff > t.policy.MaxBackoff {
+		backoff = t.policy.MaxBackoff
+	}
+	if backoff < 2 {
+		return backoff
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)))
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as a date
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	if len(retryAfter) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 10 * time.Millisecond,
+}
+
+func TestJiraClient_retry_shouldRetryRequest_whenRateLimitedWithRetryAfter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+OrganizationApiPath).
+		Get("/"+mockData.OrganizationID).
+		Reply(429).
+		SetHeader("Retry-After", "0")
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Reply(200).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "", WithRetryPolicy(testRetryPolicy))
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, mockData.OrganizationID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_retry_shouldRetryNonIdempotentRequest_whenRateLimited(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+OrganizationApiPath).
+		Post("").
+		Reply(429).
+		SetHeader("Retry-After", "0")
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Post("").
+		MatchType("json").
+		JSON(mockData.CreateOrganizationInputJSON).
+		Reply(201).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "", WithRetryPolicy(testRetryPolicy))
+	id, err := jiraClient.CreateOrganization(context.TODO(), Organization{Name: "Sample Organization"})
+
+	st.Expect(t, id, mockData.OrganizationID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_retry_shouldRetryIdempotentRequest_whenServerErrorOccurs(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Times(2).
+		Reply(502)
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Reply(200).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "", WithRetryPolicy(testRetryPolicy))
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, mockData.OrganizationID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_retry_shouldNotRetryNonIdempotentRequest_whenServerErrorOccurs(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Post("").
+		Reply(500)
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Post("").
+		Reply(201).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "", WithRetryPolicy(testRetryPolicy))
+	id, err := jiraClient.CreateOrganization(context.TODO(), Organization{Name: "Sample Organization"})
+
+	st.Expect(t, id, "")
+	st.Expect(t, err.Error(), "Rest request to create organization failed with status: 500 and response: ")
+
+	st.Expect(t, len(gock.Pending()), 1)
+}
+
+func TestJiraClient_retry_shouldNotRetryRequest_whenRetryAfterExceedsMaxBackoff(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL+OrganizationApiPath).
+		Get("/"+mockData.OrganizationID).
+		Reply(503).
+		SetHeader("Retry-After", "120")
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Reply(200).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "", WithRetryPolicy(testRetryPolicy))
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, "")
+	st.Expect(t, err.Error(), "Rest request to get organization failed with status: 503")
+
+	st.Expect(t, len(gock.Pending()), 1)
+}
+
+func TestJiraClient_retry_shouldReturnLastResponse_whenRetriesAreExhausted(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + OrganizationApiPath).
+		Get("/" + mockData.OrganizationID).
+		Times(3).
+		Reply(429)
+
+	jiraClient := NewClient("", mockData.BaseURL, "", WithRetryPolicy(testRetryPolicy))
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, "")
+	st.Expect(t, err.Error(), "Rest request to get organization failed with status: 429")
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestParseRetryAfter_shouldParseSecondsAndDates(t *testing.T) {
+	wait, ok := parseRetryAfter("5")
+	st.Expect(t, wait, 5*time.Second)
+	st.Expect(t, ok, true)
+
+	wait, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	st.Expect(t, wait, time.Duration(0))
+	st.Expect(t, ok, true)
+
+	wait, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	st.Expect(t, wait > 59*time.Minute, true)
+	st.Expect(t, ok, true)
+
+	_, ok = parseRetryAfter("soon")
+	st.Expect(t, ok, false)
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+const (
+	// Endpoints
+	// The servicedeskapi does not support managing SLAs, so the internal api used by the JSD UI is used
+	SLAApiPath        = "/rest/servicedesk/1/servicedesk/agent/"
+	SLAMetricsApiPath = "/sla/metrics"
+)
+
+type SLA struct {
+	Id              string         `json:"id,omitempty"`
+	Name            string         `json:"name,omitempty"`
+	StartConditions []SLACondition `json:"startConditions,omitempty"`
+	PauseConditions []SLACondition `json:"pauseConditions,omitempty"`
+	StopConditions  []SLACondition `json:"stopConditions,omitempty"`
+	Goals           []SLAGoal      `json:"goals,omitempty"`
+}
+
+type SLACondition struct {
+	FactoryKey  string `json:"factoryKey"`
+	ConditionId string `json:"conditionId"`
+}
+
+type SLAGoal struct {
+	JqlQuery    string `json:"jqlQuery"`
+	Duration    int64  `json:"duration"`
+	CalendarId  int    `json:"calendarId,omitempty"`
+	DefaultGoal bool   `json:"defaultGoal"`
+}
+
+type SLADefinition struct {
+	Start []SLACondition `json:"start"`
+	Pause []SLACondition `json:"pause"`
+	Stop  []SLACondition `json:"stop"`
+}
+
+type SLARequestBody struct {
+	Name       string        `json:"name"`
+	Definition SLADefinition `json:"definition"`
+	Goals      []SLAGoal     `json:"goals"`
+}
+
+type SLAGetResponse struct {
+	Id         int           `json:"id,omitempty"`
+	Name       string        `json:"name,omitempty"`
+	Definition SLADefinition `json:"definition,omitempty"`
+	Goals      []SLAGoal     `json:"goals,omitempty"`
+}
+
+// GetSLAById gets an SLA metric of a JSD project's service desk by ID
+func (c *jiraServiceDeskClient) GetSLAById(ctx context.Context, projectKey string, slaId string) (SLA, error) {
+	var sla SLA
+
+	request, err := c.newRequest(ctx, "GET", SLAApiPath+projectKey+SLAMetricsApiPath+"/"+slaId, nil, false)
+	if err != nil {
+		return sla, err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return sla, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := newAPIError("get SLA", response, nil)
+		return sla, err
+	}
+
+	var responseObject SLAGetResponse
+	err = json.NewDecoder(response.Body).Decode(&responseObject)
+	if err != nil {
+		return sla, err
+	}
+
+	sla = slaGetResponseToSLAMapper(responseObject)
+
+	return sla, err
+}
+
+// CreateSLA creates a new SLA metric with its goals in the service desk of a JSD project
+func (c *jiraServiceDeskClient) CreateSLA(ctx context.Context, projectKey string, sla SLA) (string, error) {
+	request, err := c.newRequest(ctx, "POST", SLAApiPath+projectKey+SLAMetricsApiPath, slaToSLARequestBodyMapper(sla), false)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("create SLA", response, responseData)
+		return "", err
+	}
+
+	var responseObject SLAGetResponse
+	err = json.Unmarshal(responseData, &responseObject)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(responseObject.Id), err
+}
+
+// UpdateSLA replaces the conditions and goals of an existing SLA metric
+func (c *jiraServiceDeskClient) UpdateSLA(ctx context.Context, projectKey string, slaId string, sla SLA) error {
+	request, err := c.newRequest(ctx, "PUT", SLAApiPath+projectKey+SLAMetricsApiPath+"/"+slaId, slaToSLARequestBodyMapper(sla), false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	responseData, _ := ioutil.ReadAll(response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("update SLA", response, responseData)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSLA deletes an SLA metric from the service desk of a JSD project
+func (c *jiraServiceDeskClient) DeleteSLA(ctx context.Context, projectKey string, slaId string) error {
+	request, err := c.newRequest(ctx, "DELETE", SLAApiPath+projectKey+SLAMetricsApiPath+"/"+slaId, nil, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = newAPIError("delete SLA", response, nil)
+		return err
+	}
+
+	return nil
+}
+
+func (c *jiraServiceDeskClient) IsSLAUpdated(sla *jiraservicedeskv1alpha1.SLA, existingSLA SLA) bool {
+	return len(c.SLADiff(sla, existingSLA)) > 0
+}
+
+// SLADiff lists the fields of the spec that differ from the SLA metric on JSD
+func (c *jiraServiceDeskClient) SLADiff(sla *jiraservicedeskv1alpha1.SLA, existingSLA SLA) []string {
+	var diff []string
+	newSLA := slaCRToSLAMapper(sla)
+
+	if newSLA.Name != existingSLA.Name {
+		diff = append(diff, "name")
+	}
+	if !reflect.DeepEqual(newSLA.StartConditions, existingSLA.StartConditions) {
+		diff = append(diff, "startConditions")
+	}
+	if !reflect.DeepEqual(newSLA.PauseConditions, existingSLA.PauseConditions) {
+		diff = append(diff, "pauseConditions")
+	}
+	if !reflect.DeepEqual(newSLA.StopConditions, existingSLA.StopConditions) {
+		diff = append(diff, "stopConditions")
+	}
+	if !reflect.DeepEqual(newSLA.Goals, existingSLA.Goals) {
+		diff = append(diff, "goals")
+	}
+
+	return diff
+}
+
+func (c *jiraServiceDeskClient) GetSLAFromSLACR(sla *jiraservicedeskv1alpha1.SLA) SLA {
+	return slaCRToSLAMapper(sla)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"strconv"
+
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+)
+
+func slaCRToSLAMapper(sla *jiraservicedeskv1alpha1.SLA) SLA {
+	slaObject := SLA{
+		Name:            sla.Spec.Name,
+		StartConditions: slaConditionsCRToSLAConditionsMapper(sla.Spec.StartConditions),
+		PauseConditions: slaConditionsCRToSLAConditionsMapper(sla.Spec.PauseConditions),
+		StopConditions:  slaConditionsCRToSLAConditionsMapper(sla.Spec.StopConditions),
+		Goals:           []SLAGoal{},
+	}
+
+	for _, goal := range sla.Spec.Goals {
+		slaObject.Goals = append(slaObject.Goals, SLAGoal{
+			JqlQuery:    goal.JQL,
+			Duration:    goal.Duration.Milliseconds(),
+			CalendarId:  goal.CalendarId,
+			DefaultGoal: goal.JQL == "",
+		})
+	}
+
+	if len(sla.Status.SLAId) > 0 {
+		slaObject.Id = sla.Status.SLAId
+	}
+
+	return slaObject
+}
+
+func slaConditionsCRToSLAConditionsMapper(conditions []jiraservicedeskv1alpha1.SLACondition) []SLACondition {
+	slaConditions := []SLACondition{}
+	for _, condition := range conditions {
+		slaConditions = append(slaConditions, SLACondition{
+			FactoryKey:  condition.FactoryKey,
+			ConditionId: condition.ConditionId,
+		})
+	}
+	return slaConditions
+}
+
+func slaGetResponseToSLAMapper(response SLAGetResponse) SLA {
+	sla := SLA{
+		Id:              strconv.Itoa(response.Id),
+		Name:            response.Name,
+		StartConditions: append([]SLACondition{}, response.Definition.Start...),
+		PauseConditions: append([]SLACondition{}, response.Definition.Pause...),
+		StopConditions:  append([]SLACondition{}, response.Definition.Stop...),
+		Goals:           append([]SLAGoal{}, response.Goals...),
+	}
+
+	return sla
+}
+
+func slaToSLARequestBodyMapper(sla SLA) SLARequestBody {
+	return SLARequestBody{
+		Name: sla.Name,
+		Definition: SLADefinition{
+			Start: sla.StartConditions,
+			Pause: sla.PauseConditions,
+			Stop:  sla.StopConditions,
+		},
+		Goals: sla.Goals,
+	}
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	jiraservicedeskv1alpha1 "github.com/stakater/jira-service-desk-operator/api/v1alpha1"
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+	"gopkg.in/h2non/gock.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var sampleSLA = SLA{
+	Name: "Time to first response",
+	StartConditions: []SLACondition{
+		{FactoryKey: "issue-created-sla-condition-factory", ConditionId: "issue-created-hit-condition"},
+	},
+	PauseConditions: []SLACondition{},
+	StopConditions: []SLACondition{
+		{FactoryKey: "comment-sla-condition-factory", ConditionId: "comment-for-reporter-hit-condition"},
+	},
+	Goals: []SLAGoal{
+		{JqlQuery: "priority = Highest", Duration: 3600000},
+		{JqlQuery: "", Duration: 28800000, DefaultGoal: true},
+	},
+}
+
+func TestJiraClient_GetSLAById_shouldGetSLA_whenValidSLAIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey + SLAMetricsApiPath).
+		Get("/" + mockData.SLAID).
+		Reply(200).
+		JSON(mockData.GetSLAResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	sla, err := jiraClient.GetSLAById(context.TODO(), mockData.SLAProjectKey, mockData.SLAID)
+
+	expectedSLA := sampleSLA
+	expectedSLA.Id = mockData.SLAID
+
+	st.Expect(t, sla, expectedSLA)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_GetSLAById_shouldNotGetSLA_whenInValidSLAIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey + SLAMetricsApiPath).
+		Get("/" + mockData.SLAID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	sla, err := jiraClient.GetSLAById(context.TODO(), mockData.SLAProjectKey, mockData.SLAID)
+
+	st.Expect(t, sla.Id, "")
+	st.Expect(t, err.Error(), mockData.GetSLAFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateSLA_shouldCreateSLA_whenValidSLADataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey).
+		Post(SLAMetricsApiPath).
+		MatchType("json").
+		JSON(mockData.SLARequestJSON).
+		Reply(200).
+		JSON(mockData.GetSLAResponseJSON)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateSLA(context.TODO(), mockData.SLAProjectKey, sampleSLA)
+
+	st.Expect(t, id, mockData.SLAID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_CreateSLA_shouldNotCreateSLA_whenInValidSLADataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey).
+		Post(SLAMetricsApiPath).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	id, err := jiraClient.CreateSLA(context.TODO(), mockData.SLAProjectKey, SLA{})
+
+	st.Expect(t, id, "")
+	st.Expect(t, err.Error(), mockData.CreateSLAFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateSLA_shouldUpdateSLA_whenValidSLADataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey + SLAMetricsApiPath).
+		Put("/" + mockData.SLAID).
+		MatchType("json").
+		JSON(mockData.SLARequestJSON).
+		Reply(200)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateSLA(context.TODO(), mockData.SLAProjectKey, mockData.SLAID, sampleSLA)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_UpdateSLA_shouldNotUpdateSLA_whenInValidSLADataIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey + SLAMetricsApiPath).
+		Put("/" + mockData.SLAID).
+		Reply(400)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.UpdateSLA(context.TODO(), mockData.SLAProjectKey, mockData.SLAID, SLA{})
+
+	st.Expect(t, err.Error(), mockData.UpdateSLAFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteSLA_shouldDeleteSLA_whenValidSLAIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey + SLAMetricsApiPath).
+		Delete("/" + mockData.SLAID).
+		Reply(204)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteSLA(context.TODO(), mockData.SLAProjectKey, mockData.SLAID)
+
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_DeleteSLA_shouldNotDeleteSLA_whenInValidSLAIdIsGiven(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mockData.BaseURL + SLAApiPath + mockData.SLAProjectKey + SLAMetricsApiPath).
+		Delete("/" + mockData.SLAID).
+		Reply(404)
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+	err := jiraClient.DeleteSLA(context.TODO(), mockData.SLAProjectKey, mockData.SLAID)
+
+	st.Expect(t, err.Error(), mockData.DeleteSLAFailedErrorMsg)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestJiraClient_IsSLAUpdated_shouldDetectChanges_whenSpecDiffersFromSLA(t *testing.T) {
+	sla := &jiraservicedeskv1alpha1.SLA{
+		Spec: jiraservicedeskv1alpha1.SLASpec{
+			Name: "Time to first response",
+			StartConditions: []jiraservicedeskv1alpha1.SLACondition{
+				{FactoryKey: "issue-created-sla-condition-factory", ConditionId: "issue-created-hit-condition"},
+			},
+			StopConditions: []jiraservicedeskv1alpha1.SLACondition{
+				{FactoryKey: "comment-sla-condition-factory", ConditionId: "comment-for-reporter-hit-condition"},
+			},
+			Goals: []jiraservicedeskv1alpha1.SLAGoal{
+				{JQL: "priority = Highest", Duration: metav1.Duration{Duration: time.Hour}},
+				{Duration: metav1.Duration{Duration: 8 * time.Hour}},
+			},
+		},
+	}
+
+	existingSLA := sampleSLA
+	existingSLA.Id = mockData.SLAID
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	st.Expect(t, jiraClient.IsSLAUpdated(sla, existingSLA), false)
+
+	sla.Spec.Goals[0].Duration = metav1.Duration{Duration: 2 * time.Hour}
+	st.Expect(t, jiraClient.IsSLAUpdated(sla, existingSLA), true)
+}
+
+func TestJiraClient_SLADiff_shouldListChangedFields_whenSpecDiffersFromSLA(t *testing.T) {
+	sla := &jiraservicedeskv1alpha1.SLA{
+		Spec: jiraservicedeskv1alpha1.SLASpec{
+			Name: "Time to first response",
+			StartConditions: []jiraservicedeskv1alpha1.SLACondition{
+				{FactoryKey: "issue-created-sla-condition-factory", ConditionId: "issue-created-hit-condition"},
+			},
+			StopConditions: []jiraservicedeskv1alpha1.SLACondition{
+				{FactoryKey: "comment-sla-condition-factory", ConditionId: "comment-for-reporter-hit-condition"},
+			},
+			Goals: []jiraservicedeskv1alpha1.SLAGoal{
+				{JQL: "priority = Highest", Duration: metav1.Duration{Duration: time.Hour}},
+				{Duration: metav1.Duration{Duration: 8 * time.Hour}},
+			},
+		},
+	}
+
+	existingSLA := sampleSLA
+	existingSLA.Id = mockData.SLAID
+
+	jiraClient := NewClient("", mockData.BaseURL, "")
+
+	st.Expect(t, len(jiraClient.SLADiff(sla, existingSLA)), 0)
+
+	sla.Spec.Name = "Time to resolution"
+	sla.Spec.PauseConditions = []jiraservicedeskv1alpha1.SLACondition{
+		{FactoryKey: "status-sla-condition-factory", ConditionId: "10002"},
+	}
+	sla.Spec.Goals[0].Duration = metav1.Duration{Duration: 2 * time.Hour}
+	st.Expect(t, jiraClient.SLADiff(sla, existingSLA), []string{"name", "pauseConditions", "goals"})
+}
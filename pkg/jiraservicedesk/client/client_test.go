@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gock.v1"
+
+	mockData "github.com/stakater/jira-service-desk-operator/mock"
+)
+
+func TestJiraClient_SetSite_shouldUseNewCredentials_whenCredentialsAreRotated(t *testing.T) {
+	defer gock.Off()
+
+	rotatedBaseURL := "https://rotated.atlassian.net"
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte("rotated@sample.com:rotated-token"))
+
+	gock.New(rotatedBaseURL+OrganizationApiPath).
+		Get("/"+mockData.OrganizationID).
+		MatchHeader("Authorization", regexp.QuoteMeta(authorization)).
+		Reply(200).
+		JSON(mockData.GetOrganizationResponseJSON)
+
+	jiraClient := NewClient("token", mockData.BaseURL, "sample@test.com")
+	jiraClient.SetSite(Site{BaseURL: rotatedBaseURL, Auth: BasicAuth{Email: "rotated@sample.com", APIToken: "rotated-token"}})
+
+	organization, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, organization.Id, mockData.OrganizationID)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+// newHangingServer starts a server that does not respond until the request is cancelled
+func newHangingServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+}
+
+func TestJiraClient_shouldFail_whenTimeoutIsExceeded(t *testing.T) {
+	server := newHangingServer()
+	defer server.Close()
+
+	jiraClient := NewClient("", server.URL, "", WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err := jiraClient.GetOrganizationById(context.TODO(), mockData.OrganizationID)
+
+	st.Expect(t, err != nil, true)
+	st.Expect(t, time.Since(start) < time.Second, true)
+}
+
+func TestJiraClient_shouldFail_whenContextIsCancelled(t *testing.T) {
+	server := newHangingServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	jiraClient := NewClient("", server.URL, "")
+
+	start := time.Now()
+	_, err := jiraClient.GetOrganizationById(ctx, mockData.OrganizationID)
+
+	st.Expect(t, errors.Is(err, context.DeadlineExceeded), true)
+	st.Expect(t, time.Since(start) < time.Second, true)
+}
@@ -1,12 +1,24 @@
 package config
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	util "github.com/stakater/operator-utils/util"
-	secretsUtil "github.com/stakater/operator-utils/util/secrets"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
 )
 
 var log = logf.Log.WithName("config")
@@ -16,16 +28,78 @@ const (
 	JiraServiceDeskAPITokenSecretKey   string = "JIRA_SERVICE_DESK_API_TOKEN"
 	JiraServiceDeskAPIBaseURLSecretKey string = "JIRA_SERVICE_DESK_API_BASE_URL"
 	JiraServiceDeskEmailSecretKey      string = "JIRA_SERVICE_DESK_EMAIL"
+	JiraServiceDeskFlavourSecretKey    string = "JIRA_SERVICE_DESK_FLAVOUR"
+
+	JiraServiceDeskAuthMethodSecretKey        string = "JIRA_SERVICE_DESK_AUTH_METHOD"
+	JiraServiceDeskOAuthClientIdSecretKey     string = "JIRA_SERVICE_DESK_OAUTH_CLIENT_ID"
+	JiraServiceDeskOAuthClientSecretSecretKey string = "JIRA_SERVICE_DESK_OAUTH_CLIENT_SECRET"
+	JiraServiceDeskOAuthRefreshTokenSecretKey string = "JIRA_SERVICE_DESK_OAUTH_REFRESH_TOKEN"
+	JiraServiceDeskOAuthTokenURLSecretKey     string = "JIRA_SERVICE_DESK_OAUTH_TOKEN_URL"
+)
+
+// Methods to authenticate with a Jira site, selected by the JIRA_SERVICE_DESK_AUTH_METHOD key of the secret
+const (
+	// Email of an Atlassian account and its api token
+	AuthMethodBasic string = "basic"
+	// Personal access token or service account token in JIRA_SERVICE_DESK_API_TOKEN
+	AuthMethodBearer string = "bearer"
+	// OAuth 2.0 client credentials grant
+	AuthMethodOAuth2ClientCredentials string = "oauth2-client-credentials"
+	// Refresh token of an OAuth 2.0 (3LO) app
+	AuthMethodOAuth2RefreshToken string = "oauth2-refresh-token"
 )
 
+// Secret keys required by each auth method, besides the base url
+var authMethodSecretKeys = map[string][]string{
+	AuthMethodBasic:                   {JiraServiceDeskAPITokenSecretKey, JiraServiceDeskEmailSecretKey},
+	AuthMethodBearer:                  {JiraServiceDeskAPITokenSecretKey},
+	AuthMethodOAuth2ClientCredentials: {JiraServiceDeskOAuthClientIdSecretKey, JiraServiceDeskOAuthClientSecretSecretKey},
+	AuthMethodOAuth2RefreshToken:      {JiraServiceDeskOAuthClientIdSecretKey, JiraServiceDeskOAuthClientSecretSecretKey, JiraServiceDeskOAuthRefreshTokenSecretKey},
+}
+
 var (
 	JiraServiceDeskSecretName = getConfigSecretName()
+
+	// Authenticators by the secret, or environment, they were loaded from, so that OAuth 2.0 tokens are reused
+	// across reconciles and reloads. The authenticator of a source is replaced when its credentials change
+	authenticators     = map[string]sourceAuthenticator{}
+	authenticatorsLock sync.Mutex
+
+	// Writes rotated OAuth 2.0 refresh tokens back to the secrets they were loaded from
+	secretWriter client.Writer
 )
 
+// SecretWriteTimeout of writing a rotated refresh token back to its secret
+const SecretWriteTimeout = 30 * time.Second
+
 type ControllerConfig struct {
 	ApiToken   string
 	ApiBaseUrl string
 	Email      string
+	// Deployment type of the Jira site, cloud or datacenter
+	Flavour jiraservicedeskclient.Flavour
+
+	AuthMethod        string
+	OAuthClientId     string
+	OAuthClientSecret string
+	OAuthRefreshToken string
+	OAuthTokenUrl     string
+
+	// Secret the config was loaded from, empty for the environment
+	secretName      string
+	secretNamespace string
+}
+
+// sourceAuthenticator is the authenticator for the credentials of a config source with the given hash
+type sourceAuthenticator struct {
+	hash string
+	auth jiraservicedeskclient.Authenticator
+}
+
+// SetSecretWriter sets the client that writes rotated OAuth 2.0 refresh tokens back to their secrets. Without it,
+// rotated refresh tokens are only kept in memory
+func SetSecretWriter(writer client.Writer) {
+	secretWriter = writer
 }
 
 func getConfigSecretName() string {
@@ -37,10 +111,8 @@ func getConfigSecretName() string {
 	return configSecretName
 }
 
-func LoadControllerConfig(apiReader client.Reader) (ControllerConfig, error) {
-	log.Info("Loading Configuration from secret")
-
-	// Retrieve operator namespace
+// GetOperatorNamespace returns the namespace of the operator, which holds its config secret
+func GetOperatorNamespace() string {
 	operatorNamespace, _ := os.LookupEnv("OPERATOR_NAMESPACE")
 	if len(operatorNamespace) == 0 {
 		operatorNamespaceTemp, err := util.GetOperatorNamespace()
@@ -52,23 +124,180 @@ func LoadControllerConfig(apiReader client.Reader) (ControllerConfig, error) {
 		}
 		operatorNamespace = operatorNamespaceTemp
 	}
+	return operatorNamespace
+}
+
+// Hash returns a short hash of the config, which identifies the loaded credentials without revealing them
+func (config ControllerConfig) Hash() string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{config.ApiToken, config.ApiBaseUrl, config.Email, string(config.Flavour), config.AuthMethod,
+		config.OAuthClientId, config.OAuthClientSecret, config.OAuthRefreshToken, config.OAuthTokenUrl}, "\n")))
+	return hex.EncodeToString(hash[:])[:16]
+}
 
-	apiToken, err := secretsUtil.LoadSecretData(apiReader, JiraServiceDeskSecretName, operatorNamespace, JiraServiceDeskAPITokenSecretKey)
+// Authenticator returns the authenticator for the auth method of the config. The same authenticator is returned
+// for an unchanged config, so that OAuth 2.0 access tokens are only refreshed when they expire
+func (config ControllerConfig) Authenticator() jiraservicedeskclient.Authenticator {
+	authenticatorsLock.Lock()
+	defer authenticatorsLock.Unlock()
+
+	hash, source := config.Hash(), config.source()
+	if existing, ok := authenticators[source]; ok && existing.hash == hash {
+		return existing.auth
+	}
+
+	var auth jiraservicedeskclient.Authenticator
+	switch config.AuthMethod {
+	case AuthMethodBearer:
+		auth = jiraservicedeskclient.BearerAuth{Token: config.ApiToken}
+	case AuthMethodOAuth2ClientCredentials:
+		auth = jiraservicedeskclient.NewOAuth2ClientCredentialsAuth(config.OAuthClientId, config.OAuthClientSecret, config.OAuthTokenUrl)
+	case AuthMethodOAuth2RefreshToken:
+		var refreshTokenAuth jiraservicedeskclient.Authenticator
+		refreshTokenAuth = jiraservicedeskclient.NewOAuth2RefreshTokenAuth(config.OAuthClientId, config.OAuthClientSecret, config.OAuthRefreshToken, config.OAuthTokenUrl,
+			func(refreshToken string) error {
+				return config.saveRefreshToken(refreshToken, refreshTokenAuth)
+			})
+		auth = refreshTokenAuth
+	default:
+		auth = jiraservicedeskclient.BasicAuth{Email: config.Email, APIToken: config.ApiToken}
+	}
+
+	authenticators[source] = sourceAuthenticator{hash: hash, auth: auth}
+	return auth
+}
+
+// source identifies the secret, or environment, the config was loaded from
+func (config ControllerConfig) source() string {
+	if len(config.secretName) == 0 {
+		return "environment"
+	}
+	return config.secretNamespace + "/" + config.secretName
+}
+
+// saveRefreshToken writes a rotated refresh token back to the secret of the config, since the previous refresh token
+// is no longer accepted after a restart. The authenticator is kept for the config with the rotated token, so that
+// reloading the secret does not refresh the token again
+func (config ControllerConfig) saveRefreshToken(refreshToken string, auth jiraservicedeskclient.Authenticator) error {
+	rotatedConfig := config
+	rotatedConfig.OAuthRefreshToken = refreshToken
+
+	// Authenticators that were already replaced by new credentials are not brought back
+	authenticatorsLock.Lock()
+	if existing, ok := authenticators[config.source()]; ok && existing.auth == auth {
+		authenticators[config.source()] = sourceAuthenticator{hash: rotatedConfig.Hash(), auth: auth}
+	}
+	authenticatorsLock.Unlock()
+
+	if secretWriter == nil || len(config.secretName) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string][]byte{JiraServiceDeskOAuthRefreshTokenSecretKey: []byte(refreshToken)},
+	})
 	if err != nil {
-		log.Error(err, "Unable to fetch apiToken from secret")
+		return err
 	}
 
-	apiBaseUrl, err := secretsUtil.LoadSecretData(apiReader, JiraServiceDeskSecretName, operatorNamespace, JiraServiceDeskAPIBaseURLSecretKey)
+	ctx, cancel := context.WithTimeout(context.Background(), SecretWriteTimeout)
+	defer cancel()
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: config.secretName, Namespace: config.secretNamespace}}
+	err = secretWriter.Patch(ctx, secret, client.RawPatch(types.MergePatchType, patch))
 	if err != nil {
-		log.Error(err, "Unable to fetch apiBaseUrl from secret")
+		return fmt.Errorf("unable to write rotated refresh token to secret %s: %w", config.secretName, err)
 	}
 
-	email, err := secretsUtil.LoadSecretData(apiReader, JiraServiceDeskSecretName, operatorNamespace, JiraServiceDeskEmailSecretKey)
+	log.Info("Wrote rotated refresh token to secret " + config.secretName)
+	return nil
+}
+
+// Site returns the Jira site of the config with its credentials
+func (config ControllerConfig) Site() jiraservicedeskclient.Site {
+	return jiraservicedeskclient.Site{
+		BaseURL: config.ApiBaseUrl,
+		Auth:    config.Authenticator(),
+		Flavour: config.Flavour,
+	}
+}
+
+func LoadControllerConfig(ctx context.Context, apiReader client.Reader) (ControllerConfig, error) {
+	log.Info("Loading Configuration from secret")
+
+	controllerConfig, err := LoadConnectionConfig(ctx, apiReader, JiraServiceDeskSecretName, GetOperatorNamespace())
 	if err != nil {
-		log.Error(err, "Unable to fetch email from secret")
+		log.Error(err, "Unable to load config from secret")
 	}
 
-	controllerConfig := ControllerConfig{ApiToken: apiToken, ApiBaseUrl: apiBaseUrl, Email: email}
+	return controllerConfig, err
+}
+
+// LoadConnectionConfig loads the configuration of a Jira site from the secret referenced by a connection
+func LoadConnectionConfig(ctx context.Context, apiReader client.Reader, secretName string, namespace string) (ControllerConfig, error) {
+	var controllerConfig ControllerConfig
+
+	secret := &corev1.Secret{}
+	err := apiReader.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+	if err != nil {
+		return controllerConfig, err
+	}
 
+	controllerConfig, err = newControllerConfig(secret.Data, "secret "+secretName)
+	controllerConfig.secretName = secretName
+	controllerConfig.secretNamespace = namespace
 	return controllerConfig, err
 }
+
+// LoadEnvConfig loads the configuration of a Jira site from environment variables named like the keys of the secret
+func LoadEnvConfig() (ControllerConfig, error) {
+	data := map[string][]byte{}
+	for _, key := range []string{JiraServiceDeskAPITokenSecretKey, JiraServiceDeskAPIBaseURLSecretKey, JiraServiceDeskEmailSecretKey,
+		JiraServiceDeskFlavourSecretKey, JiraServiceDeskAuthMethodSecretKey, JiraServiceDeskOAuthClientIdSecretKey, JiraServiceDeskOAuthClientSecretSecretKey,
+		JiraServiceDeskOAuthRefreshTokenSecretKey, JiraServiceDeskOAuthTokenURLSecretKey} {
+		if value := os.Getenv(key); len(value) > 0 {
+			data[key] = []byte(value)
+		}
+	}
+
+	return newControllerConfig(data, "environment")
+}
+
+// newControllerConfig builds the config from the keys of a secret, checking that the keys required
+// by its auth method are present
+func newControllerConfig(data map[string][]byte, source string) (ControllerConfig, error) {
+	var controllerConfig ControllerConfig
+
+	authMethod := string(data[JiraServiceDeskAuthMethodSecretKey])
+	if len(authMethod) == 0 {
+		authMethod = AuthMethodBasic
+	}
+	requiredKeys, ok := authMethodSecretKeys[authMethod]
+	if !ok {
+		return controllerConfig, fmt.Errorf("%s contains unknown auth method %s", source, authMethod)
+	}
+
+	for _, key := range append([]string{JiraServiceDeskAPIBaseURLSecretKey}, requiredKeys...) {
+		if _, ok := data[key]; !ok {
+			return controllerConfig, fmt.Errorf("%s did not contain key %s", source, key)
+		}
+	}
+
+	flavour, err := jiraservicedeskclient.ParseFlavour(string(data[JiraServiceDeskFlavourSecretKey]))
+	if err != nil {
+		return controllerConfig, fmt.Errorf("%s contains %w", source, err)
+	}
+
+	controllerConfig = ControllerConfig{
+		ApiToken:          string(data[JiraServiceDeskAPITokenSecretKey]),
+		ApiBaseUrl:        string(data[JiraServiceDeskAPIBaseURLSecretKey]),
+		Email:             string(data[JiraServiceDeskEmailSecretKey]),
+		Flavour:           flavour,
+		AuthMethod:        authMethod,
+		OAuthClientId:     string(data[JiraServiceDeskOAuthClientIdSecretKey]),
+		OAuthClientSecret: string(data[JiraServiceDeskOAuthClientSecretSecretKey]),
+		OAuthRefreshToken: string(data[JiraServiceDeskOAuthRefreshTokenSecretKey]),
+		OAuthTokenUrl:     string(data[JiraServiceDeskOAuthTokenURLSecretKey]),
+	}
+
+	return controllerConfig, nil
+}
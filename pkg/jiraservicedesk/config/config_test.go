@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nbio/st"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+func newSecretReader(data map[string]string) *fake.ClientBuilder {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jira", Namespace: "default"},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return fake.NewClientBuilder().WithObjects(secret)
+}
+
+func TestControllerConfig_Hash_shouldChange_whenCredentialsAreRotated(t *testing.T) {
+	controllerConfig := ControllerConfig{ApiToken: "token", ApiBaseUrl: "https://sample.atlassian.net/", Email: "sample@test.com"}
+	hash := controllerConfig.Hash()
+
+	st.Expect(t, len(hash), 16)
+	st.Expect(t, controllerConfig.Hash(), hash)
+
+	controllerConfig.ApiToken = "rotated-token"
+	st.Reject(t, controllerConfig.Hash(), hash)
+}
+
+func TestLoadConnectionConfig_shouldUseBasicAuth_whenAuthMethodIsUnset(t *testing.T) {
+	reader := newSecretReader(map[string]string{
+		JiraServiceDeskAPITokenSecretKey:   "token",
+		JiraServiceDeskAPIBaseURLSecretKey: "https://sample.atlassian.net",
+		JiraServiceDeskEmailSecretKey:      "sample@test.com",
+	}).Build()
+
+	controllerConfig, err := LoadConnectionConfig(context.TODO(), reader, "jira", "default")
+
+	st.Expect(t, err, nil)
+	st.Expect(t, controllerConfig.AuthMethod, AuthMethodBasic)
+	st.Expect(t, controllerConfig.Authenticator(), jiraservicedeskclient.Authenticator(jiraservicedeskclient.BasicAuth{Email: "sample@test.com", APIToken: "token"}))
+}
+
+func TestLoadConnectionConfig_shouldNotRequireEmail_whenAuthMethodIsBearer(t *testing.T) {
+	reader := newSecretReader(map[string]string{
+		JiraServiceDeskAuthMethodSecretKey: AuthMethodBearer,
+		JiraServiceDeskAPITokenSecretKey:   "personal-access-token",
+		JiraServiceDeskAPIBaseURLSecretKey: "https://jira.sample.com",
+	}).Build()
+
+	controllerConfig, err := LoadConnectionConfig(context.TODO(), reader, "jira", "default")
+
+	st.Expect(t, err, nil)
+	st.Expect(t, controllerConfig.Authenticator(), jiraservicedeskclient.Authenticator(jiraservicedeskclient.BearerAuth{Token: "personal-access-token"}))
+}
+
+func TestLoadConnectionConfig_shouldFail_whenOAuth2KeysAreMissing(t *testing.T) {
+	reader := newSecretReader(map[string]string{
+		JiraServiceDeskAuthMethodSecretKey:    AuthMethodOAuth2RefreshToken,
+		JiraServiceDeskAPIBaseURLSecretKey:    "https://api.atlassian.com/ex/jira/cloud-id",
+		JiraServiceDeskOAuthClientIdSecretKey: "client-id",
+	}).Build()
+
+	_, err := LoadConnectionConfig(context.TODO(), reader, "jira", "default")
+
+	st.Expect(t, err.Error(), "secret jira did not contain key "+JiraServiceDeskOAuthClientSecretSecretKey)
+}
+
+func TestLoadConnectionConfig_shouldFail_whenAuthMethodIsUnknown(t *testing.T) {
+	reader := newSecretReader(map[string]string{
+		JiraServiceDeskAuthMethodSecretKey: "kerberos",
+		JiraServiceDeskAPIBaseURLSecretKey: "https://sample.atlassian.net",
+	}).Build()
+
+	_, err := LoadConnectionConfig(context.TODO(), reader, "jira", "default")
+
+	st.Expect(t, err.Error(), "secret jira contains unknown auth method kerberos")
+}
+
+func TestControllerConfig_Authenticator_shouldReuseAuthenticator_whenConfigIsUnchanged(t *testing.T) {
+	controllerConfig := ControllerConfig{
+		ApiBaseUrl:        "https://api.atlassian.com/ex/jira/cloud-id",
+		AuthMethod:        AuthMethodOAuth2ClientCredentials,
+		OAuthClientId:     "client-id",
+		OAuthClientSecret: "client-secret",
+	}
+	auth := controllerConfig.Authenticator()
+
+	st.Expect(t, controllerConfig.Authenticator() == auth, true)
+
+	controllerConfig.OAuthClientSecret = "rotated-client-secret"
+	st.Expect(t, controllerConfig.Authenticator() == auth, false)
+}
+
+func TestLoadConnectionConfig_shouldUseDataCenterFlavour_whenFlavourIsDataCenter(t *testing.T) {
+	reader := newSecretReader(map[string]string{
+		JiraServiceDeskFlavourSecretKey:    "datacenter",
+		JiraServiceDeskAuthMethodSecretKey: AuthMethodBearer,
+		JiraServiceDeskAPITokenSecretKey:   "personal-access-token",
+		JiraServiceDeskAPIBaseURLSecretKey: "https://jira.sample.com",
+	}).Build()
+
+	controllerConfig, err := LoadConnectionConfig(context.TODO(), reader, "jira", "default")
+
+	st.Expect(t, err, nil)
+	st.Expect(t, controllerConfig.Site().Flavour, jiraservicedeskclient.FlavourDataCenter)
+	st.Expect(t, controllerConfig.Site().BaseURL, "https://jira.sample.com")
+}
+
+func TestLoadConnectionConfig_shouldFail_whenFlavourIsUnknown(t *testing.T) {
+	reader := newSecretReader(map[string]string{
+		JiraServiceDeskFlavourSecretKey:    "server",
+		JiraServiceDeskAPITokenSecretKey:   "token",
+		JiraServiceDeskAPIBaseURLSecretKey: "https://jira.sample.com",
+		JiraServiceDeskEmailSecretKey:      "sample@test.com",
+	}).Build()
+
+	_, err := LoadConnectionConfig(context.TODO(), reader, "jira", "default")
+
+	st.Expect(t, err.Error(), "secret jira contains unknown flavour server, expected cloud or datacenter")
+}
+
+func TestControllerConfig_saveRefreshToken_shouldWriteRotatedTokenToSecret(t *testing.T) {
+	fakeClient := newSecretReader(map[string]string{
+		JiraServiceDeskAPIBaseURLSecretKey:        "https://api.atlassian.com/ex/jira/cloud-id",
+		JiraServiceDeskAuthMethodSecretKey:        AuthMethodOAuth2RefreshToken,
+		JiraServiceDeskOAuthClientIdSecretKey:     "client-id",
+		JiraServiceDeskOAuthClientSecretSecretKey: "client-secret",
+		JiraServiceDeskOAuthRefreshTokenSecretKey: "initial-refresh-token",
+	}).Build()
+	SetSecretWriter(fakeClient)
+	defer SetSecretWriter(nil)
+
+	controllerConfig, err := LoadConnectionConfig(context.TODO(), fakeClient, "jira", "default")
+	st.Expect(t, err, nil)
+
+	auth := controllerConfig.Authenticator()
+	st.Expect(t, controllerConfig.saveRefreshToken("rotated-refresh-token", auth), nil)
+
+	secret := &corev1.Secret{}
+	st.Expect(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "jira", Namespace: "default"}, secret), nil)
+	st.Expect(t, string(secret.Data[JiraServiceDeskOAuthRefreshTokenSecretKey]), "rotated-refresh-token")
+	st.Expect(t, string(secret.Data[JiraServiceDeskOAuthClientIdSecretKey]), "client-id")
+
+	// Reloading the secret keeps the authenticator, and with it the access token
+	reloadedConfig, err := LoadConnectionConfig(context.TODO(), fakeClient, "jira", "default")
+	st.Expect(t, err, nil)
+	st.Expect(t, reloadedConfig.Authenticator() == auth, true)
+}
+
+func TestControllerConfig_Authenticator_shouldReplaceAuthenticatorOfSecret_whenCredentialsChange(t *testing.T) {
+	controllerConfig := ControllerConfig{
+		ApiBaseUrl:        "https://api.atlassian.com/ex/jira/cloud-id",
+		AuthMethod:        AuthMethodOAuth2ClientCredentials,
+		OAuthClientId:     "client-id",
+		OAuthClientSecret: "client-secret",
+		secretName:        "rotated",
+		secretNamespace:   "default",
+	}
+	auth := controllerConfig.Authenticator()
+
+	controllerConfig.OAuthClientSecret = "rotated-client-secret"
+	rotatedAuth := controllerConfig.Authenticator()
+	st.Expect(t, rotatedAuth == auth, false)
+
+	authenticatorsLock.Lock()
+	entry := authenticators["default/rotated"]
+	authenticatorsLock.Unlock()
+	st.Expect(t, entry.hash, controllerConfig.Hash())
+	st.Expect(t, entry.auth == rotatedAuth, true)
+}
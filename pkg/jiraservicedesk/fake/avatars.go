@@ -0,0 +1,103 @@
+package fake
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Content types of the images that can be uploaded as avatars
+var avatarContentTypes = map[string]bool{
+	"image/png":     true,
+	"image/svg+xml": true,
+}
+
+type avatarRequest struct {
+	Id string `json:"id"`
+}
+
+func (p *project) avatarRepresentation(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             id,
+		"owner":          p.id,
+		"isSystemAvatar": false,
+		"isSelected":     id == p.avatarId,
+		"isDeletable":    id != p.avatarId,
+	}
+}
+
+// uploadProjectAvatar adds an image as a custom avatar of the project, without selecting it
+func (s *Server) uploadProjectAvatar(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+
+	if r.Header.Get("X-Atlassian-Token") != "no-check" {
+		writeErrors(w, http.StatusForbidden, "XSRF check failed")
+		return
+	}
+	contentType := strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])
+	if !avatarContentTypes[contentType] {
+		writeErrors(w, http.StatusBadRequest, "The image type '"+contentType+"' is not supported.")
+		return
+	}
+	image, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(image) == 0 {
+		writeErrors(w, http.StatusBadRequest, "The image could not be read.")
+		return
+	}
+
+	id := s.newId()
+	project.avatars = with(project.avatars, id)
+	writeJSON(w, http.StatusCreated, project.avatarRepresentation(id))
+}
+
+func (s *Server) setProjectAvatar(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+
+	var request avatarRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if !project.hasAvatar(request.Id) {
+		writeErrors(w, http.StatusNotFound, "An avatar with id '"+request.Id+"' does not exist.")
+		return
+	}
+	project.avatarId = request.Id
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteProjectAvatar deletes a custom avatar of the project. The selected avatar can not be deleted
+func (s *Server) deleteProjectAvatar(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+
+	if !project.hasAvatar(params["avatarId"]) {
+		writeErrors(w, http.StatusNotFound, "An avatar with id '"+params["avatarId"]+"' does not exist.")
+		return
+	}
+	if project.avatarId == params["avatarId"] {
+		writeErrors(w, http.StatusBadRequest, "The selected avatar of the project can not be deleted.")
+		return
+	}
+	project.avatars = without(project.avatars, params["avatarId"])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *project) hasAvatar(id string) bool {
+	for _, avatarId := range p.avatars {
+		if avatarId == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,321 @@
+package fake
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Customers are created with the account type of Jira Service Management customers
+const customerAccountType = "customer"
+
+type user struct {
+	accountId string
+	// Username and user key on Data Center, which are derived from the email
+	name        string
+	key         string
+	email       string
+	displayName string
+}
+
+// usersRequest holds the users of a service desk or an organization, by account id on Cloud and by username on Data Center
+type usersRequest struct {
+	AccountIds []string `json:"accountIds"`
+	Usernames  []string `json:"usernames"`
+}
+
+type customerRequest struct {
+	Email       string `json:"email"`
+	DisplayName string `json:"displayName"`
+	// Name of the customer on Data Center
+	FullName string `json:"fullName"`
+}
+
+// updateUserRequest changes the email and name of a user on Data Center
+type updateUserRequest struct {
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+type inviteRequest struct {
+	Emails []string `json:"emails"`
+}
+
+func (u *user) representation(r *http.Request) map[string]interface{} {
+	return map[string]interface{}{
+		"self":         "http://" + r.Host + "/rest/api/3/user?accountId=" + u.accountId,
+		"accountId":    u.accountId,
+		"accountType":  customerAccountType,
+		"name":         u.name,
+		"key":          u.key,
+		"emailAddress": u.email,
+		"displayName":  u.displayName,
+		"active":       true,
+	}
+}
+
+// findUser returns the user with the given account id, username or user key
+func (s *Server) findUser(id string) *user {
+	if user, ok := s.users[id]; ok {
+		return user
+	}
+	for _, user := range s.users {
+		if user.name == id || user.key == id {
+			return user
+		}
+	}
+	return nil
+}
+
+func (s *Server) findUserByEmail(email string) *user {
+	for _, user := range s.users {
+		if strings.EqualFold(user.email, email) {
+			return user
+		}
+	}
+	return nil
+}
+
+// resolveUsers returns the account ids of the users of a request, and the ids that do not belong to any user
+func (s *Server) resolveUsers(request usersRequest) ([]string, []string) {
+	var accountIds, unknownIds []string
+	for _, id := range append(append([]string{}, request.AccountIds...), request.Usernames...) {
+		if user := s.findUser(id); user != nil {
+			accountIds = append(accountIds, user.accountId)
+		} else {
+			unknownIds = append(unknownIds, id)
+		}
+	}
+	return accountIds, unknownIds
+}
+
+func (s *Server) newUser(email string, displayName string) *user {
+	id := s.newId()
+	user := &user{
+		accountId:   "qm:fake:" + id,
+		name:        email,
+		key:         "JIRAUSER" + id,
+		email:       email,
+		displayName: displayName,
+	}
+	s.users[user.accountId] = user
+	return user
+}
+
+// userIdParam returns the id of the user of a request to the user api, by account id on Cloud and by username on Data Center
+func userIdParam(r *http.Request) string {
+	query := r.URL.Query()
+	for _, param := range []string{"accountId", "username", "key"} {
+		if id := query.Get(param); len(id) > 0 {
+			return id
+		}
+	}
+	return ""
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	user := s.findUser(userIdParam(r))
+	if user == nil {
+		writeErrors(w, http.StatusNotFound, "Specified user does not exist or you do not have required permissions")
+		return
+	}
+	writeJSON(w, http.StatusOK, user.representation(r))
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	user := s.findUser(userIdParam(r))
+	if user == nil {
+		writeErrors(w, http.StatusNotFound, "Specified user does not exist or you do not have required permissions")
+		return
+	}
+
+	var request updateUserRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	if len(request.EmailAddress) > 0 {
+		if !strings.Contains(request.EmailAddress, "@") {
+			writeErrors(w, http.StatusBadRequest, "The email address is invalid")
+			return
+		}
+		if existingUser := s.findUserByEmail(request.EmailAddress); existingUser != nil && existingUser != user {
+			writeErrors(w, http.StatusBadRequest, "A user with that email address already exists")
+			return
+		}
+		user.email = request.EmailAddress
+	}
+	if len(request.DisplayName) > 0 {
+		user.displayName = request.DisplayName
+	}
+	writeJSON(w, http.StatusOK, user.representation(r))
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	user := s.findUser(userIdParam(r))
+	if user == nil {
+		writeErrors(w, http.StatusNotFound, "Specified user does not exist or you do not have required permissions")
+		return
+	}
+
+	delete(s.users, user.accountId)
+	for _, project := range s.projects {
+		project.customers = without(project.customers, user.accountId)
+	}
+	for _, organization := range s.organizations {
+		organization.users = without(organization.users, user.accountId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) searchUsers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	query := r.URL.Query().Get("query")
+	if len(query) == 0 {
+		query = r.URL.Query().Get("username")
+	}
+	query = strings.ToLower(query)
+
+	users := []map[string]interface{}{}
+	for _, user := range s.sortedUsers() {
+		if len(query) > 0 && (strings.Contains(strings.ToLower(user.email), query) ||
+			strings.Contains(strings.ToLower(user.displayName), query) || strings.Contains(strings.ToLower(user.name), query)) {
+			users = append(users, user.representation(r))
+		}
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+// sortedUsers lists the users in the order they were created
+func (s *Server) sortedUsers() []*user {
+	users := make([]*user, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return idLess(strings.TrimPrefix(users[i].key, "JIRAUSER"), strings.TrimPrefix(users[j].key, "JIRAUSER"))
+	})
+	return users
+}
+
+func (s *Server) createCustomer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var request customerRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	if !strings.Contains(request.Email, "@") {
+		writeServiceDeskError(w, http.StatusBadRequest, "The email address is invalid")
+		return
+	}
+	if s.findUserByEmail(request.Email) != nil {
+		writeServiceDeskError(w, http.StatusBadRequest, "An account already exists for this email")
+		return
+	}
+
+	displayName := request.DisplayName
+	if len(displayName) == 0 {
+		displayName = request.FullName
+	}
+	user := s.newUser(request.Email, displayName)
+	writeJSON(w, http.StatusCreated, user.representation(r))
+}
+
+// inviteCustomers creates the customers for the emails of the request, unless they exist, and adds them to
+// the service desk. Invited customers choose their name when they sign up, so they are named after their email
+func (s *Server) inviteCustomers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+
+	var request inviteRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	success := []map[string]interface{}{}
+	for _, email := range request.Emails {
+		user := s.findUserByEmail(email)
+		if user == nil {
+			user = s.newUser(email, email)
+		}
+		project.customers = with(project.customers, user.accountId)
+
+		success = append(success, map[string]interface{}{
+			"key":          user.key,
+			"emailAddress": user.email,
+			"displayName":  user.displayName,
+			"accountId":    user.accountId,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": success, "failure": []interface{}{}})
+}
+
+func (s *Server) listServiceDeskCustomers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+
+	start, end, page := paginate(r, len(project.customers))
+	customers := []map[string]interface{}{}
+	for _, accountId := range project.customers[start:end] {
+		customers = append(customers, s.users[accountId].representation(r))
+	}
+	page.Values = customers
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) addServiceDeskCustomers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.updateServiceDeskCustomers(w, r, params, with)
+}
+
+func (s *Server) removeServiceDeskCustomers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.updateServiceDeskCustomers(w, r, params, without)
+}
+
+func (s *Server) updateServiceDeskCustomers(w http.ResponseWriter, r *http.Request, params map[string]string, update func([]string, string) []string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+
+	var request usersRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	accountIds, unknownIds := s.resolveUsers(request)
+	if len(unknownIds) > 0 {
+		writeServiceDeskError(w, http.StatusBadRequest, "The following users do not exist: "+strings.Join(unknownIds, ", "))
+		return
+	}
+
+	for _, accountId := range accountIds {
+		project.customers = update(project.customers, accountId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// with adds an id to a list of ids, unless it is already in the list
+func with(ids []string, id string) []string {
+	for _, existingId := range ids {
+		if existingId == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// without removes an id from a list of ids
+func without(ids []string, id string) []string {
+	var remainingIds []string
+	for _, existingId := range ids {
+		if existingId != id {
+			remainingIds = append(remainingIds, existingId)
+		}
+	}
+	return remainingIds
+}
@@ -0,0 +1,201 @@
+package fake
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type organization struct {
+	id   string
+	name string
+	// Account ids of the users of the organization
+	users []string
+}
+
+type organizationRequest struct {
+	Name string `json:"name"`
+}
+
+type serviceDeskOrganizationRequest struct {
+	OrganizationId int `json:"organizationId"`
+}
+
+func (o *organization) representation() map[string]interface{} {
+	return map[string]interface{}{
+		"id":   o.id,
+		"name": o.name,
+	}
+}
+
+func (s *Server) findOrganizationByName(name string) *organization {
+	for _, organization := range s.organizations {
+		if strings.EqualFold(organization.name, name) {
+			return organization
+		}
+	}
+	return nil
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	organizations := make([]*organization, 0, len(s.organizations))
+	for _, organization := range s.organizations {
+		organizations = append(organizations, organization)
+	}
+	sort.Slice(organizations, func(i, j int) bool {
+		return idLess(organizations[i].id, organizations[j].id)
+	})
+
+	start, end, page := paginate(r, len(organizations))
+	values := []map[string]interface{}{}
+	for _, organization := range organizations[start:end] {
+		values = append(values, organization.representation())
+	}
+	page.Values = values
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	organization, ok := s.organizations[params["organizationId"]]
+	if !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Organization "+params["organizationId"]+" does not exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, organization.representation())
+}
+
+func (s *Server) createOrganization(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var request organizationRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if !s.validateOrganization(w, request, nil) {
+		return
+	}
+
+	organization := &organization{id: s.newId(), name: request.Name}
+	s.organizations[organization.id] = organization
+	writeJSON(w, http.StatusCreated, organization.representation())
+}
+
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	organization, ok := s.organizations[params["organizationId"]]
+	if !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Organization "+params["organizationId"]+" does not exist.")
+		return
+	}
+
+	var request organizationRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if !s.validateOrganization(w, request, organization) {
+		return
+	}
+
+	organization.name = request.Name
+	writeJSON(w, http.StatusOK, organization.representation())
+}
+
+func (s *Server) validateOrganization(w http.ResponseWriter, request organizationRequest, existingOrganization *organization) bool {
+	if len(request.Name) == 0 {
+		writeServiceDeskError(w, http.StatusBadRequest, "The organization name must not be empty.")
+		return false
+	}
+	if organization := s.findOrganizationByName(request.Name); organization != nil && organization != existingOrganization {
+		writeServiceDeskError(w, http.StatusConflict, "An organization with this name already exists.")
+		return false
+	}
+	return true
+}
+
+func (s *Server) deleteOrganization(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	organizationId := params["organizationId"]
+	if _, ok := s.organizations[organizationId]; !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Organization "+organizationId+" does not exist.")
+		return
+	}
+
+	delete(s.organizations, organizationId)
+	for _, project := range s.projects {
+		project.organizations = without(project.organizations, organizationId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listOrganizationUsers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	organization, ok := s.organizations[params["organizationId"]]
+	if !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Organization "+params["organizationId"]+" does not exist.")
+		return
+	}
+
+	start, end, page := paginate(r, len(organization.users))
+	users := []map[string]interface{}{}
+	for _, accountId := range organization.users[start:end] {
+		users = append(users, s.users[accountId].representation(r))
+	}
+	page.Values = users
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) addOrganizationUsers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.updateOrganizationUsers(w, r, params, with)
+}
+
+func (s *Server) removeOrganizationUsers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.updateOrganizationUsers(w, r, params, without)
+}
+
+func (s *Server) updateOrganizationUsers(w http.ResponseWriter, r *http.Request, params map[string]string, update func([]string, string) []string) {
+	organization, ok := s.organizations[params["organizationId"]]
+	if !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Organization "+params["organizationId"]+" does not exist.")
+		return
+	}
+
+	var request usersRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	accountIds, unknownIds := s.resolveUsers(request)
+	if len(unknownIds) > 0 {
+		writeServiceDeskError(w, http.StatusBadRequest, "The following users do not exist: "+strings.Join(unknownIds, ", "))
+		return
+	}
+
+	for _, accountId := range accountIds {
+		organization.users = update(organization.users, accountId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addServiceDeskOrganization(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.updateServiceDeskOrganizations(w, r, params, with)
+}
+
+func (s *Server) removeServiceDeskOrganization(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.updateServiceDeskOrganizations(w, r, params, without)
+}
+
+func (s *Server) updateServiceDeskOrganizations(w http.ResponseWriter, r *http.Request, params map[string]string, update func([]string, string) []string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+
+	var request serviceDeskOrganizationRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	organizationId := strconv.Itoa(request.OrganizationId)
+	if _, ok := s.organizations[organizationId]; !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Organization "+organizationId+" does not exist.")
+		return
+	}
+
+	project.organizations = update(project.organizations, organizationId)
+	w.WriteHeader(http.StatusNoContent)
+}
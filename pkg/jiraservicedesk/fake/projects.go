@@ -0,0 +1,469 @@
+package fake
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	serviceDeskProjectType    = "service_desk"
+	nextGenProjectTemplateKey = "com.atlassian.servicedesk:next-gen-it-service-desk"
+	defaultAssigneeType       = "UNASSIGNED"
+	maxProjectKeyLength       = 10
+)
+
+var projectKeyPattern = regexp.MustCompile("^[A-Z][A-Z0-9]+$")
+
+// Schemes and categories of the site by id. Projects that do not choose a permission or notification scheme
+// get the default one, and have no issue security scheme or category
+var (
+	permissionSchemes = map[int]string{
+		defaultPermissionScheme: "Default Permission Scheme",
+		10100:                   "Restricted Permission Scheme",
+	}
+	notificationSchemes = map[int]string{
+		defaultNotificationScheme: "Default Notification Scheme",
+		10101:                     "Quiet Notification Scheme",
+	}
+	issueSecuritySchemes = map[int]string{
+		10200: "Internal Issue Security Scheme",
+	}
+	projectCategories = map[int]string{
+		10300: "Internal",
+		10301: "Customer facing",
+	}
+)
+
+const (
+	defaultPermissionScheme   = 0
+	defaultNotificationScheme = 10000
+)
+
+type project struct {
+	id                 string
+	key                string
+	name               string
+	description        string
+	projectTypeKey     string
+	projectTemplateKey string
+	assigneeType       string
+	lead               string
+	url                string
+
+	permissionScheme    int
+	notificationScheme  int
+	issueSecurityScheme int
+	categoryId          int
+
+	// Ids of the custom avatars of the project, and of the selected one
+	avatars  []string
+	avatarId string
+
+	// Id of the service desk of a service desk project
+	serviceDeskId   string
+	requestSecurity requestSecurity
+	// Account ids of the customers of the service desk
+	customers     []string
+	organizations []string
+	requestTypes  map[string]*requestType
+	queues        map[string]*queue
+	slas          map[string]*sla
+}
+
+// projectRequest holds the fields of a created or updated project. Data Center sends the username of the
+// lead as lead instead of leadAccountId
+type projectRequest struct {
+	Name                string `json:"name"`
+	Key                 string `json:"key"`
+	ProjectTypeKey      string `json:"projectTypeKey"`
+	ProjectTemplateKey  string `json:"projectTemplateKey"`
+	Description         string `json:"description"`
+	AssigneeType        string `json:"assigneeType"`
+	LeadAccountId       string `json:"leadAccountId"`
+	Lead                string `json:"lead"`
+	URL                 string `json:"url"`
+	AvatarId            int    `json:"avatarId"`
+	IssueSecurityScheme int    `json:"issueSecurityScheme"`
+	PermissionScheme    int    `json:"permissionScheme"`
+	NotificationScheme  int    `json:"notificationScheme"`
+	CategoryId          int    `json:"categoryId"`
+}
+
+// requestSecurity holds the settings of which customers can access a service desk. New service desks are
+// only accessible to the customers added to them
+type requestSecurity struct {
+	AutocompleteEnabled     bool `json:"autocompleteEnabled"`
+	ManageEnabled           bool `json:"manageEnabled"`
+	ServiceDeskOpenAccess   bool `json:"serviceDeskOpenAccess"`
+	ServiceDeskPublicSignup bool `json:"serviceDeskPublicSignup"`
+}
+
+func (request projectRequest) lead() string {
+	if len(request.LeadAccountId) > 0 {
+		return request.LeadAccountId
+	}
+	return request.Lead
+}
+
+// findProject returns the project with the given id or key
+func (s *Server) findProject(idOrKey string) *project {
+	if project, ok := s.projects[idOrKey]; ok {
+		return project
+	}
+	for _, project := range s.projects {
+		if project.key == idOrKey {
+			return project
+		}
+	}
+	return nil
+}
+
+// findServiceDesk returns the service desk project with the given service desk id or project key
+func (s *Server) findServiceDesk(idOrKey string) *project {
+	for _, project := range s.projects {
+		if len(project.serviceDeskId) > 0 && (project.serviceDeskId == idOrKey || project.key == idOrKey) {
+			return project
+		}
+	}
+	return nil
+}
+
+// sortedProjects lists the projects in the order they were created
+func (s *Server) sortedProjects() []*project {
+	projects := make([]*project, 0, len(s.projects))
+	for _, project := range s.projects {
+		projects = append(projects, project)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return idLess(projects[i].id, projects[j].id)
+	})
+	return projects
+}
+
+func idLess(a string, b string) bool {
+	first, _ := strconv.Atoi(a)
+	second, _ := strconv.Atoi(b)
+	return first < second
+}
+
+// representation of a project in the platform api. Data Center identifies the lead by its username and returns no style
+func (p *project) representation(r *http.Request) map[string]interface{} {
+	dataCenter := strings.HasPrefix(r.URL.Path, "/rest/api/2/")
+
+	lead := map[string]interface{}{"accountId": p.lead}
+	if dataCenter {
+		lead = map[string]interface{}{"key": p.lead, "name": p.lead}
+	}
+
+	representation := map[string]interface{}{
+		"self":           "http://" + r.Host + "/rest/api/3/project/" + p.id,
+		"id":             p.id,
+		"key":            p.key,
+		"name":           p.name,
+		"description":    p.description,
+		"lead":           lead,
+		"projectTypeKey": p.projectTypeKey,
+		"assigneeType":   p.assigneeType,
+	}
+	if !dataCenter {
+		representation["style"] = "classic"
+		if p.projectTemplateKey == nextGenProjectTemplateKey {
+			representation["style"] = "next-gen"
+		}
+	}
+	if len(p.url) > 0 {
+		representation["url"] = p.url
+	}
+	if len(p.avatarId) > 0 {
+		representation["avatarUrls"] = map[string]string{
+			"48x48": "http://" + r.Host + "/secure/projectavatar?pid=" + p.id + "&avatarId=" + p.avatarId,
+		}
+	}
+	if p.categoryId != 0 {
+		representation["projectCategory"] = map[string]interface{}{
+			"id":   strconv.Itoa(p.categoryId),
+			"name": projectCategories[p.categoryId],
+		}
+	}
+	return representation
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+	writeJSON(w, http.StatusOK, project.representation(r))
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var request projectRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	errors := s.validateProject(request, nil)
+	if len(request.Name) == 0 {
+		errors["projectName"] = "You must specify a valid project name."
+	}
+	if len(request.Key) == 0 {
+		errors["projectKey"] = "You must specify a valid project key."
+	}
+	if len(request.ProjectTypeKey) == 0 {
+		errors["projectTypeKey"] = "A valid project type is required."
+	}
+	if len(request.lead()) == 0 {
+		errors["projectLead"] = "You must specify a valid project lead."
+	}
+	if len(errors) > 0 {
+		writeFieldErrors(w, http.StatusBadRequest, errors)
+		return
+	}
+
+	project := &project{
+		id:                 s.newId(),
+		key:                request.Key,
+		name:               request.Name,
+		description:        request.Description,
+		projectTypeKey:     request.ProjectTypeKey,
+		projectTemplateKey: request.ProjectTemplateKey,
+		assigneeType:       request.AssigneeType,
+		lead:               request.lead(),
+		url:                request.URL,
+		permissionScheme:   defaultPermissionScheme,
+		notificationScheme: defaultNotificationScheme,
+		requestTypes:       map[string]*requestType{},
+		queues:             map[string]*queue{},
+		slas:               map[string]*sla{},
+	}
+	if len(project.assigneeType) == 0 {
+		project.assigneeType = defaultAssigneeType
+	}
+	project.assignSchemes(request)
+	if project.projectTypeKey == serviceDeskProjectType {
+		project.serviceDeskId = s.newId()
+	}
+	s.projects[project.id] = project
+
+	id, _ := strconv.Atoi(project.id)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"self": "http://" + r.Host + "/rest/api/3/project/" + project.id,
+		"id":   id,
+		"key":  project.key,
+	})
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+
+	var request projectRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if errors := s.validateProject(request, project); len(errors) > 0 {
+		writeFieldErrors(w, http.StatusBadRequest, errors)
+		return
+	}
+
+	if len(request.Name) > 0 {
+		project.name = request.Name
+	}
+	if len(request.Key) > 0 {
+		project.key = request.Key
+	}
+	if len(request.Description) > 0 {
+		project.description = request.Description
+	}
+	if len(request.AssigneeType) > 0 {
+		project.assigneeType = request.AssigneeType
+	}
+	if len(request.lead()) > 0 {
+		project.lead = request.lead()
+	}
+	if len(request.URL) > 0 {
+		project.url = request.URL
+	}
+	if request.AvatarId != 0 {
+		project.avatarId = strconv.Itoa(request.AvatarId)
+	}
+	project.assignSchemes(request)
+
+	writeJSON(w, http.StatusOK, project.representation(r))
+}
+
+// assignSchemes assigns the schemes and category chosen in a request to the project
+func (p *project) assignSchemes(request projectRequest) {
+	if request.PermissionScheme != 0 {
+		p.permissionScheme = request.PermissionScheme
+	}
+	if request.NotificationScheme != 0 {
+		p.notificationScheme = request.NotificationScheme
+	}
+	if request.IssueSecurityScheme != 0 {
+		p.issueSecurityScheme = request.IssueSecurityScheme
+	}
+	if request.CategoryId != 0 {
+		p.categoryId = request.CategoryId
+	}
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+	delete(s.projects, project.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateProject checks the fields set in a request against the other projects, schemes and categories of
+// the site. Only the custom avatars of the project itself can be referenced, so new projects have no avatars
+func (s *Server) validateProject(request projectRequest, existingProject *project) map[string]string {
+	errors := map[string]string{}
+
+	if len(request.Key) > maxProjectKeyLength {
+		errors["projectKey"] = "Project keys must not exceed " + strconv.Itoa(maxProjectKeyLength) + " characters in length."
+	} else if len(request.Key) > 0 && !projectKeyPattern.MatchString(request.Key) {
+		errors["projectKey"] = "Project keys must start with an uppercase letter, followed by one or more uppercase alphanumeric characters."
+	}
+
+	for _, project := range s.projects {
+		if project == existingProject {
+			continue
+		}
+		if len(request.Key) > 0 && project.key == request.Key {
+			errors["projectKey"] = "A project with that project key already exists."
+		}
+		if len(request.Name) > 0 && strings.EqualFold(project.name, request.Name) {
+			errors["projectName"] = "A project with that name already exists."
+		}
+	}
+
+	if request.AvatarId != 0 && (existingProject == nil || !existingProject.hasAvatar(strconv.Itoa(request.AvatarId))) {
+		errors["avatarId"] = "An avatar with id '" + strconv.Itoa(request.AvatarId) + "' does not exist."
+	}
+	if _, ok := issueSecuritySchemes[request.IssueSecurityScheme]; request.IssueSecurityScheme != 0 && !ok {
+		errors["issueSecurityScheme"] = "The issue security scheme does not exist."
+	}
+	if _, ok := permissionSchemes[request.PermissionScheme]; !ok {
+		errors["permissionScheme"] = "The permission scheme does not exist."
+	}
+	if _, ok := notificationSchemes[request.NotificationScheme]; request.NotificationScheme != 0 && !ok {
+		errors["notificationScheme"] = "The notification scheme does not exist."
+	}
+	if _, ok := projectCategories[request.CategoryId]; request.CategoryId != 0 && !ok {
+		errors["categoryId"] = "The project category does not exist."
+	}
+
+	return errors
+}
+
+func (s *Server) listServiceDesks(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var serviceDesks []map[string]interface{}
+	for _, project := range s.sortedProjects() {
+		if len(project.serviceDeskId) > 0 {
+			serviceDesks = append(serviceDesks, map[string]interface{}{
+				"id":          project.serviceDeskId,
+				"projectId":   project.id,
+				"projectName": project.name,
+				"projectKey":  project.key,
+			})
+		}
+	}
+
+	start, end, page := paginate(r, len(serviceDesks))
+	page.Values = append([]map[string]interface{}{}, serviceDesks[start:end]...)
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) getRequestSecurity(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, project.requestSecurity)
+}
+
+func (s *Server) updateRequestSecurity(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+
+	var requestSecurity requestSecurity
+	if !decode(w, r, &requestSecurity) {
+		return
+	}
+	project.requestSecurity = requestSecurity
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getPermissionScheme(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+	writeScheme(w, project.permissionScheme, permissionSchemes)
+}
+
+func (s *Server) assignPermissionScheme(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+
+	var request schemeRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if _, ok := permissionSchemes[request.Id]; !ok {
+		writeErrors(w, http.StatusNotFound, "The permission scheme does not exist.")
+		return
+	}
+	project.permissionScheme = request.Id
+	writeScheme(w, project.permissionScheme, permissionSchemes)
+}
+
+func (s *Server) getNotificationScheme(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+	writeScheme(w, project.notificationScheme, notificationSchemes)
+}
+
+// getIssueSecurityScheme returns the issue security scheme of a project, projects without one are not found
+func (s *Server) getIssueSecurityScheme(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findProject(params["projectIdOrKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "No project could be found with key '"+params["projectIdOrKey"]+"'.")
+		return
+	}
+	if project.issueSecurityScheme == 0 {
+		writeErrors(w, http.StatusNotFound, "The project does not have an issue security level scheme.")
+		return
+	}
+	writeScheme(w, project.issueSecurityScheme, issueSecuritySchemes)
+}
+
+type schemeRequest struct {
+	Id int `json:"id"`
+}
+
+func writeScheme(w http.ResponseWriter, id int, schemes map[int]string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "name": schemes[id]})
+}
@@ -0,0 +1,229 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Page size of lists that do not specify a limit
+const defaultPageLimit = 50
+
+// Server is an in-memory Jira Service Management site. It implements the endpoints of the project, user, customer,
+// organization and service desk apis that the operator uses, on Cloud (/rest/api/3) and Data Center (/rest/api/2)
+// paths, and keeps its state until it is stopped. Requests must be authenticated, but any credentials are accepted
+type Server struct {
+	lock   sync.Mutex
+	routes []route
+
+	nextId        int
+	nextRequestId int
+
+	// Projects by id
+	projects map[string]*project
+	// Users by account id
+	users map[string]*user
+	// Organizations by id
+	organizations map[string]*organization
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  func(w http.ResponseWriter, r *http.Request, params map[string]string)
+}
+
+// NewServer creates an empty site
+func NewServer() *Server {
+	s := &Server{
+		nextId:        10000,
+		projects:      map[string]*project{},
+		users:         map[string]*user{},
+		organizations: map[string]*organization{},
+	}
+
+	for _, version := range []string{"2", "3"} {
+		s.handle("GET", "/rest/api/"+version+"/project/{projectIdOrKey}", s.getProject)
+		s.handle("POST", "/rest/api/"+version+"/project", s.createProject)
+		s.handle("PUT", "/rest/api/"+version+"/project/{projectIdOrKey}", s.updateProject)
+		s.handle("DELETE", "/rest/api/"+version+"/project/{projectIdOrKey}", s.deleteProject)
+		s.handle("GET", "/rest/api/"+version+"/project/{projectIdOrKey}/permissionscheme", s.getPermissionScheme)
+		s.handle("PUT", "/rest/api/"+version+"/project/{projectIdOrKey}/permissionscheme", s.assignPermissionScheme)
+		s.handle("GET", "/rest/api/"+version+"/project/{projectIdOrKey}/notificationscheme", s.getNotificationScheme)
+		s.handle("GET", "/rest/api/"+version+"/project/{projectIdOrKey}/issuesecuritylevelscheme", s.getIssueSecurityScheme)
+		s.handle("POST", "/rest/api/"+version+"/project/{projectIdOrKey}/avatar2", s.uploadProjectAvatar)
+		s.handle("PUT", "/rest/api/"+version+"/project/{projectIdOrKey}/avatar", s.setProjectAvatar)
+		s.handle("DELETE", "/rest/api/"+version+"/project/{projectIdOrKey}/avatar/{avatarId}", s.deleteProjectAvatar)
+		s.handle("GET", "/rest/api/"+version+"/user", s.getUser)
+		s.handle("DELETE", "/rest/api/"+version+"/user", s.deleteUser)
+		s.handle("GET", "/rest/api/"+version+"/user/search", s.searchUsers)
+	}
+
+	// Users can only be updated on Data Center
+	s.handle("PUT", "/rest/api/2/user", s.updateUser)
+
+	s.handle("GET", "/rest/servicedeskapi/servicedesk", s.listServiceDesks)
+	s.handle("GET", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/customer", s.listServiceDeskCustomers)
+	s.handle("POST", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/customer", s.addServiceDeskCustomers)
+	s.handle("DELETE", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/customer", s.removeServiceDeskCustomers)
+	s.handle("POST", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/organization", s.addServiceDeskOrganization)
+	s.handle("DELETE", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/organization", s.removeServiceDeskOrganization)
+	s.handle("GET", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/requesttype/{requestTypeId}", s.getRequestType)
+	s.handle("POST", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/requesttype", s.createRequestType)
+	s.handle("DELETE", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/requesttype/{requestTypeId}", s.deleteRequestType)
+	s.handle("GET", "/rest/servicedeskapi/servicedesk/{serviceDeskId}/queue/{queueId}", s.getQueue)
+	s.handle("POST", "/rest/servicedeskapi/customer", s.createCustomer)
+
+	s.handle("GET", "/rest/servicedeskapi/organization", s.listOrganizations)
+	s.handle("GET", "/rest/servicedeskapi/organization/{organizationId}", s.getOrganization)
+	s.handle("POST", "/rest/servicedeskapi/organization", s.createOrganization)
+	s.handle("PUT", "/rest/servicedeskapi/organization/{organizationId}", s.updateOrganization)
+	s.handle("DELETE", "/rest/servicedeskapi/organization/{organizationId}", s.deleteOrganization)
+	s.handle("GET", "/rest/servicedeskapi/organization/{organizationId}/user", s.listOrganizationUsers)
+	s.handle("POST", "/rest/servicedeskapi/organization/{organizationId}/user", s.addOrganizationUsers)
+	s.handle("DELETE", "/rest/servicedeskapi/organization/{organizationId}/user", s.removeOrganizationUsers)
+
+	// Internal apis used by the JSD UI
+	s.handle("GET", "/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics/{slaId}", s.getSLA)
+	s.handle("POST", "/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics", s.createSLA)
+	s.handle("PUT", "/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics/{slaId}", s.updateSLA)
+	s.handle("DELETE", "/rest/servicedesk/1/servicedesk/agent/{projectKey}/sla/metrics/{slaId}", s.deleteSLA)
+	s.handle("GET", "/rest/servicedesk/1/servicedesk/{projectKey}/settings/requestsecurity", s.getRequestSecurity)
+	s.handle("POST", "/rest/servicedesk/1/servicedesk/{projectKey}/settings/requestsecurity", s.updateRequestSecurity)
+	s.handle("POST", "/rest/servicedesk/1/servicedesk/{projectKey}/queues", s.createQueue)
+	s.handle("PUT", "/rest/servicedesk/1/servicedesk/{projectKey}/queues/{queueId}", s.updateQueue)
+	s.handle("DELETE", "/rest/servicedesk/1/servicedesk/{projectKey}/queues", s.deleteQueues)
+	s.handle("POST", "/rest/servicedesk/1/pages/people/customers/pagination/{projectKey}/invite", s.inviteCustomers)
+
+	return s
+}
+
+// Start serves the site on a local port until the context is done, and returns its base url
+func (s *Server) Start(ctx context.Context) string {
+	server := httptest.NewServer(s)
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	return server.URL
+}
+
+func (s *Server) handle(method string, pattern string, handler func(w http.ResponseWriter, r *http.Request, params map[string]string)) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.nextRequestId++
+	w.Header().Set("X-Arequestid", "fake-"+strconv.Itoa(s.nextRequestId))
+
+	if len(r.Header.Get("Authorization")) == 0 {
+		writeErrors(w, http.StatusUnauthorized, "You are not authenticated. Authentication required to perform this operation.")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, route := range s.routes {
+		if params, ok := route.match(r.Method, segments); ok {
+			route.handler(w, r, params)
+			return
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "null for uri: "+r.URL.String())
+}
+
+func (route route) match(method string, segments []string) (map[string]string, bool) {
+	if route.method != method || len(route.segments) != len(segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range route.segments {
+		if strings.HasPrefix(segment, "{") {
+			params[strings.Trim(segment, "{}")] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// newId returns a new numeric id, which is unique across all objects of the site
+func (s *Server) newId() string {
+	s.nextId++
+	return strconv.Itoa(s.nextId)
+}
+
+// page holds the pagination of a list of the service desk api
+type page struct {
+	Size       int         `json:"size"`
+	Start      int         `json:"start"`
+	Limit      int         `json:"limit"`
+	IsLastPage bool        `json:"isLastPage"`
+	Values     interface{} `json:"values"`
+}
+
+// paginate returns the bounds of the page requested through the start and limit query parameters
+func paginate(r *http.Request, total int) (int, int, page) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if start < 0 || start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return start, end, page{Size: end - start, Start: start, Limit: limit, IsLastPage: end == total}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "Invalid request payload. Refer to the REST API documentation and try again.")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeErrors responds with the error format of the Jira platform api
+func writeErrors(w http.ResponseWriter, status int, errorMessages ...string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errorMessages": append([]string{}, errorMessages...),
+		"errors":        map[string]string{},
+	})
+}
+
+// writeFieldErrors responds with the field errors of the Jira platform api
+func writeFieldErrors(w http.ResponseWriter, status int, errors map[string]string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errorMessages": []string{},
+		"errors":        errors,
+	})
+}
+
+// writeServiceDeskError responds with the error format of the service desk api
+func writeServiceDeskError(w http.ResponseWriter, status int, errorMessage string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errorMessage":     errorMessage,
+		"i18nErrorMessage": map[string]interface{}{"i18nKey": "", "parameters": []string{}},
+	})
+}
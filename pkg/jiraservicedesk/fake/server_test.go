@@ -0,0 +1,309 @@
+package fake
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nbio/st"
+
+	jiraservicedeskclient "github.com/stakater/jira-service-desk-operator/pkg/jiraservicedesk/client"
+)
+
+var sampleProject = jiraservicedeskclient.Project{
+	Name:               "Sample",
+	Key:                "SAMPLE",
+	ProjectTypeKey:     "service_desk",
+	ProjectTemplateKey: jiraservicedeskclient.ClassicProjectTemplateKey,
+	Description:        "Sample project",
+	AssigneeType:       "PROJECT_LEAD",
+	LeadAccountId:      "5ebfbc3ead226b0ba46c3590",
+}
+
+func newClient(t *testing.T, flavour jiraservicedeskclient.Flavour) jiraservicedeskclient.Client {
+	server := httptest.NewServer(NewServer())
+	t.Cleanup(server.Close)
+
+	return jiraservicedeskclient.NewSiteClient(jiraservicedeskclient.Site{
+		BaseURL: server.URL,
+		Auth:    jiraservicedeskclient.BasicAuth{Email: "sample@test.com", APIToken: "token"},
+		Flavour: flavour,
+	})
+}
+
+func TestServer_shouldKeepProjects_whenProjectsAreCreatedUpdatedAndDeleted(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	id, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	project, err := jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, jiraClient.ProjectDiff(project, jiraservicedeskclient.Project{
+		Id:                 id,
+		Name:               sampleProject.Name,
+		Key:                sampleProject.Key,
+		ProjectTypeKey:     sampleProject.ProjectTypeKey,
+		ProjectTemplateKey: sampleProject.ProjectTemplateKey,
+		Description:        sampleProject.Description,
+		AssigneeType:       sampleProject.AssigneeType,
+		LeadAccountId:      sampleProject.LeadAccountId,
+	}), []string(nil))
+
+	_, err = jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, jiraservicedeskclient.IsConflict(err), true)
+
+	err = jiraClient.UpdateProject(ctx, jiraservicedeskclient.Project{Key: "RENAMED"}, id)
+	st.Expect(t, err, nil)
+	project, _ = jiraClient.GetProjectByIdentifier(ctx, "RENAMED")
+	st.Expect(t, project.Id, id)
+
+	keys, err := jiraClient.GetServiceDeskProjectKeys(ctx)
+	st.Expect(t, err, nil)
+	st.Expect(t, keys, []string{"RENAMED"})
+
+	st.Expect(t, jiraClient.DeleteProject(ctx, id), nil)
+	_, err = jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, jiraservicedeskclient.IsNotFound(err), true)
+}
+
+func TestServer_shouldRejectProject_whenKeyIsInvalid(t *testing.T) {
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	invalidProject := sampleProject
+	invalidProject.Key = "sample"
+	_, err := jiraClient.CreateProject(context.TODO(), invalidProject)
+
+	apiErr, ok := jiraservicedeskclient.AsAPIError(err)
+	st.Expect(t, ok, true)
+	st.Expect(t, apiErr.StatusCode, http.StatusBadRequest)
+	st.Expect(t, apiErr.Errors["projectKey"], "Project keys must start with an uppercase letter, followed by one or more uppercase alphanumeric characters.")
+}
+
+func TestServer_shouldKeepCustomers_whenCustomersAreAddedToProjectsAndOrganizations(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	_, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	customerId, err := jiraClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: "customer@sample.com", DisplayName: "Customer"})
+	st.Expect(t, err, nil)
+
+	_, err = jiraClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: "CUSTOMER@sample.com", DisplayName: "Customer"})
+	st.Expect(t, jiraservicedeskclient.IsConflict(err), true)
+
+	foundId, err := jiraClient.GetCustomerIdByEmail(ctx, "customer@sample.com")
+	st.Expect(t, err, nil)
+	st.Expect(t, foundId, customerId)
+
+	st.Expect(t, jiraClient.AddCustomerToProject(ctx, customerId, sampleProject.Key), nil)
+	customers, err := jiraClient.GetCustomersByProjectKey(ctx, sampleProject.Key)
+	st.Expect(t, err, nil)
+	st.Expect(t, len(customers), 1)
+	st.Expect(t, customers[0].Email, "customer@sample.com")
+
+	organizationId, err := jiraClient.CreateOrganization(ctx, jiraservicedeskclient.Organization{Name: "Sample"})
+	st.Expect(t, err, nil)
+	st.Expect(t, jiraClient.AddOrganizationToProject(ctx, organizationId, sampleProject.Key), nil)
+	st.Expect(t, jiraClient.AddCustomerToOrganization(ctx, customerId, organizationId), nil)
+	users, err := jiraClient.GetCustomersByOrganizationId(ctx, organizationId)
+	st.Expect(t, err, nil)
+	st.Expect(t, len(users), 1)
+	st.Expect(t, users[0].AccountId, customerId)
+
+	st.Expect(t, jiraClient.DeleteCustomer(ctx, customerId), nil)
+	customers, _ = jiraClient.GetCustomersByProjectKey(ctx, sampleProject.Key)
+	st.Expect(t, len(customers), 0)
+
+	_, err = jiraClient.GetCustomerById(ctx, customerId)
+	st.Expect(t, jiraservicedeskclient.IsNotFound(err), true)
+}
+
+func TestServer_shouldIdentifyUsersByUsername_whenFlavourIsDataCenter(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourDataCenter)
+
+	dataCenterProject := sampleProject
+	dataCenterProject.LeadAccountId = "jdoe"
+	id, err := jiraClient.CreateProject(ctx, dataCenterProject)
+	st.Expect(t, err, nil)
+
+	project, err := jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, project.LeadAccountId, "jdoe")
+
+	customerId, err := jiraClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: "customer@sample.com", DisplayName: "Customer"})
+	st.Expect(t, err, nil)
+	st.Expect(t, customerId, "customer@sample.com")
+
+	st.Expect(t, jiraClient.AddCustomerToProject(ctx, customerId, sampleProject.Key), nil)
+	customer, err := jiraClient.GetCustomerById(ctx, customerId)
+	st.Expect(t, err, nil)
+	st.Expect(t, customer.DisplayName, "Customer")
+}
+
+func TestServer_shouldKeepServiceDeskObjects_whenObjectsAreCreated(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	_, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	requestTypeId, err := jiraClient.CreateRequestType(ctx, sampleProject.Key, jiraservicedeskclient.RequestType{Name: "Access", IssueTypeId: "10001"})
+	st.Expect(t, err, nil)
+	requestType, err := jiraClient.GetRequestTypeById(ctx, sampleProject.Key, requestTypeId)
+	st.Expect(t, err, nil)
+	st.Expect(t, requestType.Name, "Access")
+
+	queue := jiraservicedeskclient.Queue{Name: "Open", JQL: "project = SAMPLE AND resolution = EMPTY", Columns: []string{"issuekey", "summary"}}
+	queueId, err := jiraClient.CreateQueue(ctx, sampleProject.Key, queue)
+	st.Expect(t, err, nil)
+	existingQueue, err := jiraClient.GetQueueById(ctx, sampleProject.Key, queueId)
+	st.Expect(t, err, nil)
+	st.Expect(t, existingQueue.JQL, queue.JQL)
+	st.Expect(t, existingQueue.Columns, queue.Columns)
+
+	sla := jiraservicedeskclient.SLA{
+		Name:            "Time to resolution",
+		StartConditions: []jiraservicedeskclient.SLACondition{{FactoryKey: "issue-created-sla-condition-factory", ConditionId: "issue-created-hit-condition"}},
+		PauseConditions: []jiraservicedeskclient.SLACondition{},
+		StopConditions:  []jiraservicedeskclient.SLACondition{{FactoryKey: "resolution-sla-condition-factory", ConditionId: "resolution-set-hit-condition"}},
+		Goals:           []jiraservicedeskclient.SLAGoal{{Duration: 14400000, DefaultGoal: true}},
+	}
+	slaId, err := jiraClient.CreateSLA(ctx, sampleProject.Key, sla)
+	st.Expect(t, err, nil)
+	existingSLA, err := jiraClient.GetSLAById(ctx, sampleProject.Key, slaId)
+	st.Expect(t, err, nil)
+	sla.Id = slaId
+	st.Expect(t, existingSLA, sla)
+
+	st.Expect(t, jiraClient.DeleteQueue(ctx, sampleProject.Key, queueId), nil)
+	_, err = jiraClient.GetQueueById(ctx, sampleProject.Key, queueId)
+	st.Expect(t, jiraservicedeskclient.IsNotFound(err), true)
+}
+
+func TestServer_shouldRejectRequest_whenRequestIsNotAuthenticated(t *testing.T) {
+	server := httptest.NewServer(NewServer())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/rest/api/3/project/SAMPLE")
+	st.Expect(t, err, nil)
+	response.Body.Close()
+	st.Expect(t, response.StatusCode, http.StatusUnauthorized)
+}
+
+func TestServer_shouldKeepRequestSecurity_whenCustomerAccessIsUpdated(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	id, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	project, err := jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, project.CustomerAccess, &jiraservicedeskclient.CustomerAccess{})
+
+	customerAccess := jiraservicedeskclient.CustomerAccess{ServiceDeskOpenAccess: true, AutocompleteEnabled: true}
+	st.Expect(t, jiraClient.UpdateProjectAccessPermissions(ctx, customerAccess, sampleProject.Key), nil)
+
+	project, err = jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, project.CustomerAccess, &customerAccess)
+}
+
+func TestServer_shouldKeepSchemes_whenSchemesAreAssigned(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	id, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	project, err := jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, []int{project.PermissionScheme, project.NotificationScheme, project.IssueSecurityScheme, project.CategoryId}, []int{0, 10000, 0, 0})
+
+	err = jiraClient.UpdateProject(ctx, jiraservicedeskclient.Project{PermissionScheme: 10100, NotificationScheme: 10101, IssueSecurityScheme: 10200, CategoryId: 10300}, id)
+	st.Expect(t, err, nil)
+
+	project, err = jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, []int{project.PermissionScheme, project.NotificationScheme, project.IssueSecurityScheme, project.CategoryId}, []int{10100, 10101, 10200, 10300})
+
+	err = jiraClient.UpdateProject(ctx, jiraservicedeskclient.Project{PermissionScheme: 99999}, id)
+	st.Expect(t, jiraservicedeskclient.IsNotFound(err), true)
+}
+
+func TestServer_shouldChangeProjectLead_whenNewLeadExists(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	id, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	exists, err := jiraClient.ProjectLeadExists(ctx, "unknown")
+	st.Expect(t, err, nil)
+	st.Expect(t, exists, false)
+
+	leadId, err := jiraClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: "lead@sample.com", DisplayName: "Lead"})
+	st.Expect(t, err, nil)
+
+	exists, err = jiraClient.ProjectLeadExists(ctx, leadId)
+	st.Expect(t, err, nil)
+	st.Expect(t, exists, true)
+
+	st.Expect(t, jiraClient.UpdateProject(ctx, jiraservicedeskclient.Project{LeadAccountId: leadId}, id), nil)
+
+	project, err := jiraClient.GetProjectByIdentifier(ctx, id)
+	st.Expect(t, err, nil)
+	st.Expect(t, project.LeadAccountId, leadId)
+}
+
+func TestServer_shouldUpdateUser_whenFlavourIsDataCenter(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourDataCenter)
+
+	customerId, err := jiraClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: "customer@sample.com", DisplayName: "Customer"})
+	st.Expect(t, err, nil)
+	_, err = jiraClient.CreateCustomer(ctx, jiraservicedeskclient.Customer{Email: "other@sample.com", DisplayName: "Other"})
+	st.Expect(t, err, nil)
+
+	err = jiraClient.UpdateCustomer(ctx, customerId, jiraservicedeskclient.Customer{Email: "renamed@sample.com", DisplayName: "Renamed Customer"})
+	st.Expect(t, err, nil)
+
+	customer, err := jiraClient.GetCustomerById(ctx, customerId)
+	st.Expect(t, err, nil)
+	st.Expect(t, customer.Email, "renamed@sample.com")
+	st.Expect(t, customer.DisplayName, "Renamed Customer")
+
+	err = jiraClient.UpdateCustomer(ctx, customerId, jiraservicedeskclient.Customer{Email: "other@sample.com"})
+	st.Expect(t, err != nil, true)
+}
+
+func TestServer_shouldKeepAvatars_whenAvatarsAreUploadedAndSelected(t *testing.T) {
+	ctx := context.TODO()
+	jiraClient := newClient(t, jiraservicedeskclient.FlavourCloud)
+
+	_, err := jiraClient.CreateProject(ctx, sampleProject)
+	st.Expect(t, err, nil)
+
+	_, err = jiraClient.UploadProjectAvatar(ctx, sampleProject.Key, "image/gif", []byte("GIF89a"))
+	st.Expect(t, err != nil, true)
+
+	firstAvatarId, err := jiraClient.UploadProjectAvatar(ctx, sampleProject.Key, "image/png", []byte("\x89PNG\r\n\x1a\n"))
+	st.Expect(t, err, nil)
+	st.Expect(t, jiraClient.SetProjectAvatar(ctx, sampleProject.Key, firstAvatarId), nil)
+
+	secondAvatarId, err := jiraClient.UploadProjectAvatar(ctx, sampleProject.Key, "image/svg+xml", []byte("<svg/>"))
+	st.Expect(t, err, nil)
+
+	// The selected avatar can only be deleted once another one is selected
+	st.Expect(t, jiraClient.DeleteProjectAvatar(ctx, sampleProject.Key, firstAvatarId) != nil, true)
+	st.Expect(t, jiraClient.SetProjectAvatar(ctx, sampleProject.Key, secondAvatarId), nil)
+	st.Expect(t, jiraClient.DeleteProjectAvatar(ctx, sampleProject.Key, firstAvatarId), nil)
+
+	err = jiraClient.SetProjectAvatar(ctx, sampleProject.Key, firstAvatarId)
+	st.Expect(t, jiraservicedeskclient.IsNotFound(err), true)
+}
@@ -0,0 +1,271 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type requestType struct {
+	Id            string   `json:"id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	HelpText      string   `json:"helpText"`
+	IssueTypeId   string   `json:"issueTypeId"`
+	ServiceDeskId string   `json:"serviceDeskId"`
+	GroupIds      []string `json:"groupIds"`
+}
+
+type queue struct {
+	id      string
+	name    string
+	jql     string
+	columns []string
+}
+
+type queueRequest struct {
+	Name    string   `json:"name"`
+	JQL     string   `json:"jql"`
+	Columns []string `json:"columns"`
+}
+
+type queueDeleteRequest struct {
+	Deleted []int `json:"deleted"`
+}
+
+// sla keeps the definition and goals of an SLA metric as they were sent
+type sla struct {
+	Id         int             `json:"id"`
+	Name       string          `json:"name"`
+	Definition json.RawMessage `json:"definition"`
+	Goals      json.RawMessage `json:"goals"`
+}
+
+func (s *Server) getRequestType(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+	requestType, ok := project.requestTypes[params["requestTypeId"]]
+	if !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Request type "+params["requestTypeId"]+" does not exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, requestType)
+}
+
+func (s *Server) createRequestType(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+
+	var request requestType
+	if !decode(w, r, &request) {
+		return
+	}
+	if len(request.Name) == 0 || len(request.IssueTypeId) == 0 {
+		writeServiceDeskError(w, http.StatusBadRequest, "The name and issue type of a request type are required.")
+		return
+	}
+	for _, existingRequestType := range project.requestTypes {
+		if strings.EqualFold(existingRequestType.Name, request.Name) {
+			writeServiceDeskError(w, http.StatusBadRequest, "A request type with this name already exists.")
+			return
+		}
+	}
+
+	request.Id = s.newId()
+	request.ServiceDeskId = project.serviceDeskId
+	if request.GroupIds == nil {
+		request.GroupIds = []string{}
+	}
+	project.requestTypes[request.Id] = &request
+	writeJSON(w, http.StatusCreated, request)
+}
+
+func (s *Server) deleteRequestType(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+	if _, ok := project.requestTypes[params["requestTypeId"]]; !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Request type "+params["requestTypeId"]+" does not exist.")
+		return
+	}
+	delete(project.requestTypes, params["requestTypeId"])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getQueue(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["serviceDeskId"])
+	if project == nil {
+		writeServiceDeskError(w, http.StatusNotFound, "Service desk "+params["serviceDeskId"]+" does not exist.")
+		return
+	}
+	queue, ok := project.queues[params["queueId"]]
+	if !ok {
+		writeServiceDeskError(w, http.StatusNotFound, "Queue "+params["queueId"]+" does not exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     queue.id,
+		"name":   queue.name,
+		"jql":    queue.jql,
+		"fields": queue.columns,
+	})
+}
+
+func (s *Server) createQueue(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+
+	var request queueRequest
+	if !decode(w, r, &request) || !validateQueue(w, request) {
+		return
+	}
+
+	queue := &queue{id: s.newId(), name: request.Name, jql: request.JQL, columns: request.Columns}
+	project.queues[queue.id] = queue
+
+	id, _ := strconv.Atoi(queue.id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "name": queue.name})
+}
+
+func (s *Server) updateQueue(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+	queue, ok := project.queues[params["queueId"]]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "Queue "+params["queueId"]+" does not exist.")
+		return
+	}
+
+	var request queueRequest
+	if !decode(w, r, &request) || !validateQueue(w, request) {
+		return
+	}
+
+	queue.name, queue.jql, queue.columns = request.Name, request.JQL, request.Columns
+
+	id, _ := strconv.Atoi(queue.id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "name": queue.name})
+}
+
+func validateQueue(w http.ResponseWriter, request queueRequest) bool {
+	if len(request.Name) == 0 || len(request.JQL) == 0 {
+		writeFieldErrors(w, http.StatusBadRequest, map[string]string{"queue": "The name and JQL of a queue are required."})
+		return false
+	}
+	return true
+}
+
+// deleteQueues deletes the queues of the request, ignoring queues that do not exist like the JSD UI
+func (s *Server) deleteQueues(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+
+	var request queueDeleteRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	for _, id := range request.Deleted {
+		delete(project.queues, strconv.Itoa(id))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getSLA(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+	sla, ok := project.slas[params["slaId"]]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "SLA metric "+params["slaId"]+" does not exist.")
+		return
+	}
+	writeJSON(w, http.StatusOK, sla)
+}
+
+func (s *Server) createSLA(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+
+	var request sla
+	if !decode(w, r, &request) || !validateSLA(w, project, request, "") {
+		return
+	}
+
+	id := s.newId()
+	request.Id, _ = strconv.Atoi(id)
+	project.slas[id] = &request
+	writeJSON(w, http.StatusOK, request)
+}
+
+func (s *Server) updateSLA(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+	existingSLA, ok := project.slas[params["slaId"]]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "SLA metric "+params["slaId"]+" does not exist.")
+		return
+	}
+
+	var request sla
+	if !decode(w, r, &request) || !validateSLA(w, project, request, params["slaId"]) {
+		return
+	}
+
+	request.Id = existingSLA.Id
+	project.slas[params["slaId"]] = &request
+	writeJSON(w, http.StatusOK, request)
+}
+
+func validateSLA(w http.ResponseWriter, project *project, request sla, slaId string) bool {
+	if len(request.Name) == 0 {
+		writeFieldErrors(w, http.StatusBadRequest, map[string]string{"name": "The name of an SLA metric is required."})
+		return false
+	}
+	for id, existingSLA := range project.slas {
+		if id != slaId && strings.EqualFold(existingSLA.Name, request.Name) {
+			writeFieldErrors(w, http.StatusBadRequest, map[string]string{"name": "An SLA metric with this name already exists."})
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) deleteSLA(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	project := s.findServiceDesk(params["projectKey"])
+	if project == nil {
+		writeErrors(w, http.StatusNotFound, "Service desk "+params["projectKey"]+" does not exist.")
+		return
+	}
+	if _, ok := project.slas[params["slaId"]]; !ok {
+		writeErrors(w, http.StatusNotFound, "SLA metric "+params["slaId"]+" does not exist.")
+		return
+	}
+	delete(project.slas, params["slaId"])
+	w.WriteHeader(http.StatusNoContent)
+}